@@ -0,0 +1,363 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// Address is a Reapable, Filterable
+// embeds AWS API's ec2.Address
+type Address struct {
+	Resource
+	ec2.Address
+}
+
+// NewAddress creates an Address from the AWS API's ec2.Address
+// VPC addresses are identified by their AllocationId, classic addresses by their PublicIp
+// tags are described separately, and only VPC addresses can be tagged
+func NewAddress(region string, address *ec2.Address, tags []*ec2.TagDescription) *Address {
+	id := *address.PublicIp
+	if address.AllocationId != nil {
+		id = *address.AllocationId
+	}
+
+	a := Address{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(id),
+			Name:   *address.PublicIp,
+			Tags:   make(map[string]string),
+		},
+		Address: *address,
+	}
+
+	for _, tag := range tags {
+		a.Resource.Tags[*tag.Key] = *tag.Value
+	}
+
+	if a.Tagged("aws:cloudformation:stack-name") {
+		a.Dependency = true
+		a.IsInCloudformation = true
+	}
+
+	if a.Tagged(reaperTag) {
+		// restore previously tagged state
+		a.reaperState = state.NewStateWithTag(a.Tag(reaperTag))
+	} else {
+		// initial state
+		a.reaperState = state.NewState()
+	}
+
+	return &a
+}
+
+// Associated returns whether the Address is associated with an instance or a network interface
+func (a *Address) Associated() bool {
+	return a.AssociationId != nil || a.InstanceId != nil || a.NetworkInterfaceId != nil
+}
+
+// VPC returns whether the Address is for use in a VPC, rather than EC2-Classic
+func (a *Address) VPC() bool {
+	return a.Domain != nil && *a.Domain == ec2.DomainTypeVpc
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *Address) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableAddressEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *Address) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableAddressEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *Address) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableAddressEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *Address) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableAddressEventHTMLShort)
+	return
+}
+
+type addressEventData struct {
+	Config        *Config
+	Address       *Address
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+	IgnoreLink1   string
+	IgnoreLink3   string
+	IgnoreLink7   string
+}
+
+func (a *Address) getTemplateData() (interface{}, error) {
+	ignore1, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(1*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore3, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(3*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore7, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &addressEventData{
+		Config:        config,
+		Address:       a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+		IgnoreLink1:   ignore1,
+		IgnoreLink3:   ignore3,
+		IgnoreLink7:   ignore7,
+	}, nil
+}
+
+const reapableAddressEventHTML = `
+<html>
+<body>
+	<p>Elastic IP <a href="{{ .Address.AWSConsoleURL }}">{{ .Address.PublicIp }} in {{.Address.Region}}</a> {{ if .Address.VPC }}is scheduled to be released{{ else }}qualifies as reapable{{ end }}.</p>
+
+	<p>
+		{{ if .Address.Associated }}It is associated with {{ if .Address.InstanceId }}instance {{ .Address.InstanceId }}{{ else }}network interface {{ .Address.NetworkInterfaceId }}{{ end }}.{{ else }}It is not associated with anything, and is charged for every hour it stays that way.{{ end }}
+	</p>
+
+	<p>
+		{{ if .Address.VPC }}You can ignore this message and your Elastic IP will advance to the next state after <strong>{{.Address.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>. If you do not take action it will be released!{{ else }}EC2-Classic Elastic IPs cannot be tagged, so the Reaper cannot keep track of this Elastic IP: you will be notified again on every run, and it will not be released unless you release it below.{{ end }}
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Release it now</a></li>{{ if .Address.VPC }}
+			<li><a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a></li>
+			<li><a href="{{ .IgnoreLink3 }}">Ignore it for 3 more days</a></li>
+			<li><a href="{{ .IgnoreLink7}}">Ignore it for 7 more days</a></li>{{ end }}
+		</ul>
+	</p>
+
+	<p>
+		{{ if .Address.VPC }}If you want the Reaper to ignore this Elastic IP tag it with {{ .Config.WhitelistTag }} with any value, or click <a href="{{ .WhitelistLink }}">here</a>.{{ else }}EC2-Classic Elastic IPs cannot be tagged, so they cannot be whitelisted.{{ end }}
+	</p>
+</body>
+</html>
+`
+
+const reapableAddressEventHTMLShort = `
+<html>
+<body>
+	<p>Elastic IP <a href="{{ .Address.AWSConsoleURL }}">{{ .Address.PublicIp }}</a> in {{.Address.Region}} {{ if .Address.VPC }}is scheduled to be released after <strong>{{.Address.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>{{ else }}qualifies as reapable, and will not be released unless you release it{{ end }}.
+		<br />
+		<a href="{{ .TerminateLink }}">Release</a>{{ if .Address.VPC }},
+		<a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a>,
+		<a href="{{ .IgnoreLink3 }}">3 days</a>,
+		<a href="{{ .IgnoreLink7}}"> 7 days</a>, or
+		<a href="{{ .WhitelistLink }}">Whitelist</a> it{{ end }}.
+	</p>
+</body>
+</html>
+`
+
+const reapableAddressEventTextShort = `%%%
+Elastic IP [{{.Address.PublicIp}}]({{.Address.AWSConsoleURL}}) in region: [{{.Address.Region}}](https://{{.Address.Region}}.console.aws.amazon.com/ec2/v2/home?region={{.Address.Region}}).{{if .Address.Owned}} Owned by {{.Address.Owner}}.{{end}}\n
+{{if .Address.VPC}}[Whitelist]({{ .WhitelistLink }}) or {{end}}[Release]({{ .TerminateLink }}) this Elastic IP.
+%%%`
+
+const reapableAddressEventText = `%%%
+Reaper has discovered an Elastic IP qualified as reapable: [{{.Address.PublicIp}}]({{.Address.AWSConsoleURL}}) in region: [{{.Address.Region}}](https://{{.Address.Region}}.console.aws.amazon.com/ec2/v2/home?region={{.Address.Region}}).\n
+{{if .Address.Owned}}Owned by {{.Address.Owner}}.\n{{end}}
+{{if .Address.Associated}}Associated with {{if .Address.InstanceId}}instance {{.Address.InstanceId}}{{else}}network interface {{.Address.NetworkInterfaceId}}{{end}}.\n{{else}}Not associated.\n{{end}}
+{{ if .Address.AWSConsoleURL}}{{.Address.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.Address.AWSConsoleURL}})\n
+{{if .Address.VPC}}[Whitelist]({{ .WhitelistLink }}) this Elastic IP.{{end}}
+[Release]({{ .TerminateLink }}) this Elastic IP.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+// noop for EC2-Classic addresses, which cannot be tagged
+func (a *Address) Save(s *state.State) (bool, error) {
+	if !a.VPC() {
+		return false, nil
+	}
+	return a.Resource.Save(s)
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+// noop for EC2-Classic addresses, which cannot be tagged
+func (a *Address) Unsave() (bool, error) {
+	if !a.VPC() {
+		return false, nil
+	}
+	return a.Resource.Unsave()
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+// noop for EC2-Classic addresses, which cannot be tagged
+func (a *Address) Whitelist() (bool, error) {
+	if !a.VPC() {
+		return false, nil
+	}
+	return a.Resource.Whitelist()
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *Address) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "Associated":
+		if b, err := filter.BoolValue(0); err == nil && a.Associated() == b {
+			matched = true
+		}
+	case "Domain":
+		// one of:
+		// vpc
+		// standard
+		if a.Domain != nil && *a.Domain == filter.Arguments[0] {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering Addresses.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *Address) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/ec2/v2/home?region=%s#Addresses:search=%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(*a.PublicIp)))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+func (a *Address) Terminate() (bool, error) {
+	log.Info("Releasing Address %s", a.ReapableDescriptionTiny())
+	api := ec2.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	input := &ec2.ReleaseAddressInput{}
+	if a.VPC() {
+		input.AllocationId = a.AllocationId
+	} else {
+		input.PublicIp = a.PublicIp
+	}
+	_, err := api.ReleaseAddress(input)
+	if err != nil {
+		log.Error("could not release Address %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because there is no concept of stopping an Elastic IP
+func (a *Address) Stop() (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,32 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestAddressFilter(t *testing.T) {
+	for _, test := range []struct {
+		address   *ec2.Address
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{&ec2.Address{PublicIp: aws.String("1.2.3.4"), AllocationId: aws.String("eipalloc-1"), Domain: aws.String("vpc")}, "Associated", []string{"false"}, true},
+		{&ec2.Address{PublicIp: aws.String("1.2.3.4"), AllocationId: aws.String("eipalloc-1"), Domain: aws.String("vpc"), AssociationId: aws.String("eipassoc-1")}, "Associated", []string{"true"}, true},
+		{&ec2.Address{PublicIp: aws.String("1.2.3.5"), Domain: aws.String("standard"), InstanceId: aws.String("i-1")}, "Associated", []string{"false"}, false},
+		{&ec2.Address{PublicIp: aws.String("1.2.3.5"), Domain: aws.String("standard")}, "Domain", []string{"standard"}, true},
+		{&ec2.Address{PublicIp: aws.String("1.2.3.5"), Domain: aws.String("standard")}, "Domain", []string{"vpc"}, false},
+		{&ec2.Address{PublicIp: aws.String("1.2.3.5")}, "Named", []string{"1.2.3.5"}, true},
+		{&ec2.Address{PublicIp: aws.String("1.2.3.5")}, "Unknown", []string{}, false},
+	} {
+		a := NewAddress("us-west-2", test.address, nil)
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s %s(%v) = %t, want %t", a.ID(), test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
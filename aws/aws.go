@@ -9,9 +9,28 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/acm"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
+	"github.com/aws/aws-sdk-go/service/elasticsearchservice"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/emr"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/redshift"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/workspaces"
 	"github.com/mozilla-services/reaper/events"
 	"github.com/mozilla-services/reaper/reapable"
 	log "github.com/mozilla-services/reaper/reaperlog"
@@ -28,8 +47,41 @@ var (
 	config  *Config
 	timeout = time.Tick(100 * time.Millisecond)
 	sess    = session.New()
+
+	// the account Reaper runs in, see accountID
+	account     string
+	accountLock sync.Mutex
+
+	// the kinds of resources that could not all be described in a region, see DescribeFailed
+	describeFailures     = make(map[string]map[reapable.Kind]bool)
+	describeFailuresLock sync.Mutex
 )
 
+// describeFailed records that not every resource of kind could be described in region
+func describeFailed(kind reapable.Kind, region string) {
+	describeFailuresLock.Lock()
+	defer describeFailuresLock.Unlock()
+	if describeFailures[region] == nil {
+		describeFailures[region] = make(map[reapable.Kind]bool)
+	}
+	describeFailures[region][kind] = true
+}
+
+// DescribeFailed returns whether describing any resource of kind in region failed
+// since ResetDescribeFailures, in which case a resource that was not described may still exist
+func DescribeFailed(kind reapable.Kind, region reapable.Region) bool {
+	describeFailuresLock.Lock()
+	defer describeFailuresLock.Unlock()
+	return describeFailures[region.String()][kind]
+}
+
+// ResetDescribeFailures forgets the failures recorded by a previous run
+func ResetDescribeFailures() {
+	describeFailuresLock.Lock()
+	defer describeFailuresLock.Unlock()
+	describeFailures = make(map[string]map[reapable.Kind]bool)
+}
+
 // Config stores configuration for the aws package
 type Config struct {
 	Notifications    events.NotificationsConfig
@@ -39,6 +91,12 @@ type Config struct {
 	DefaultOwner     string
 	DefaultEmailHost string
 	DryRun           bool
+	// allow terminating Buckets that are not empty by deleting their contents
+	EmptyBuckets bool
+	// retention set when stopping LogGroups
+	LogGroupRetentionInDays int64
+	// release the Elastic IP addresses of terminated NatGateways
+	ReleaseNatGatewayAddresses bool
 
 	WithoutCloudformationResources bool
 }
@@ -54,6 +112,24 @@ func SetConfig(c *Config) {
 	config = c
 }
 
+// accountID returns the ID of the account Reaper runs in
+// some services (RDS...) require ARNs, which contain it
+func accountID() string {
+	accountLock.Lock()
+	defer accountLock.Unlock()
+	// retried until it succeeds
+	if account == "" {
+		api := sts.New(sess)
+		resp, err := api.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+		if err != nil {
+			log.Error("Error getting the account ID: %s", err.Error())
+			return ""
+		}
+		account = *resp.Account
+	}
+	return account
+}
+
 // AllCloudformations returns a chan of Cloudformations, sourced from the AWS API
 func AllCloudformations() chan *Cloudformation {
 	ch := make(chan *Cloudformation, len(config.Regions))
@@ -187,6 +263,7 @@ func AllAutoScalingGroups() chan *AutoScalingGroup {
 			if err != nil {
 				// probably should do something here...
 				log.Error(err.Error())
+				describeFailed("AutoScalingGroup", region)
 			}
 		}(region)
 	}
@@ -230,6 +307,7 @@ func AllInstances() chan *Instance {
 			if err != nil {
 				// probably should do something here...
 				log.Error(err.Error())
+				describeFailed("Instance", region)
 			}
 		}(region)
 	}
@@ -270,6 +348,7 @@ func AllVolumes() chan *Volume {
 			if err != nil {
 				// probably should do something here...
 				log.Error(err.Error())
+				describeFailed("Volume", region)
 			}
 		}(region)
 	}
@@ -314,3 +393,1591 @@ func AllSecurityGroups() chan *SecurityGroup {
 	}()
 	return ch
 }
+
+// AllSnapshots describes every Snapshot owned by this account in the requested regions
+// *Snapshots are created for each *ec2.Snapshot
+// and are passed to a channel
+func AllSnapshots() chan *Snapshot {
+	ch := make(chan *Snapshot, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := ec2.New(sess, aws.NewConfig().WithRegion(region))
+			// without an owner, public snapshots from every account are returned
+			input := &ec2.DescribeSnapshotsInput{OwnerIds: []*string{aws.String("self")}}
+			// DescribeSnapshotsPages does autopagination
+			err := api.DescribeSnapshotsPages(input, func(resp *ec2.DescribeSnapshotsOutput, lastPage bool) bool {
+				for _, snapshot := range resp.Snapshots {
+					ch <- NewSnapshot(region, snapshot)
+				}
+				// if we are at the last page, we should not continue
+				// the return value of this func is "shouldContinue"
+				if lastPage {
+					return false
+				}
+				return true
+			})
+			if err != nil {
+				log.Error("Error describing Snapshots in %s: %s", region, err.Error())
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// AllImages describes every Image owned by this account in the requested regions
+// *Images are created for each *ec2.Image
+// and are passed to a channel
+func AllImages() chan *Image {
+	ch := make(chan *Image, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := ec2.New(sess, aws.NewConfig().WithRegion(region))
+			// without an owner, public images from every account are returned
+			resp, err := api.DescribeImages(&ec2.DescribeImagesInput{Owners: []*string{aws.String("self")}})
+			if err != nil {
+				log.Error("Error describing Images in %s: %s", region, err.Error())
+				describeFailed("Image", region)
+				return
+			}
+			for _, image := range resp.Images {
+				ch <- NewImage(region, image)
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// AllLoadBalancers describes every classic LoadBalancer in the requested regions
+// *LoadBalancers are created for each *elb.LoadBalancerDescription
+// and are passed to a channel
+func AllLoadBalancers() chan *LoadBalancer {
+	ch := make(chan *LoadBalancer, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := elb.New(sess, aws.NewConfig().WithRegion(region))
+			err := api.DescribeLoadBalancersPages(&elb.DescribeLoadBalancersInput{}, func(resp *elb.DescribeLoadBalancersOutput, lastPage bool) bool {
+				var names []*string
+				for _, lb := range resp.LoadBalancerDescriptions {
+					names = append(names, lb.LoadBalancerName)
+				}
+				tags := loadBalancerTags(api, region, names)
+				for _, lb := range resp.LoadBalancerDescriptions {
+					// without its tags, a LoadBalancer's state and whitelisting are unknown
+					lbTags, ok := tags[*lb.LoadBalancerName]
+					if !ok {
+						continue
+					}
+					var instanceStates []*elb.InstanceState
+					if len(lb.Instances) > 0 {
+						health, err := api.DescribeInstanceHealth(&elb.DescribeInstanceHealthInput{
+							LoadBalancerName: lb.LoadBalancerName,
+						})
+						if err != nil {
+							log.Error("Error describing instance health for LoadBalancer %s in %s: %s", *lb.LoadBalancerName, region, err.Error())
+						} else {
+							instanceStates = health.InstanceStates
+						}
+					}
+					ch <- NewLoadBalancer(region, lb, lbTags, instanceStates)
+				}
+				// if we are at the last page, we should not continue
+				// the return value of this func is "shouldContinue"
+				return !lastPage
+			})
+			if err != nil {
+				log.Error("Error describing LoadBalancers in %s: %s", region, err.Error())
+				describeFailed("LoadBalancer", region)
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// loadBalancerTags describes the tags of the named LoadBalancers
+// DescribeTags accepts at most 20 names per call
+// LoadBalancers whose tags could not be described are missing from the map
+func loadBalancerTags(api *elb.ELB, region string, names []*string) map[string][]*elb.Tag {
+	tags := make(map[string][]*elb.Tag)
+	for i := 0; i < len(names); i += 20 {
+		end := i + 20
+		if end > len(names) {
+			end = len(names)
+		}
+		resp, err := api.DescribeTags(&elb.DescribeTagsInput{LoadBalancerNames: names[i:end]})
+		if err != nil {
+			log.Error("Error describing LoadBalancer tags in %s: %s", region, err.Error())
+			describeFailed("LoadBalancer", region)
+			continue
+		}
+		for _, description := range resp.TagDescriptions {
+			tags[*description.LoadBalancerName] = description.Tags
+		}
+	}
+	return tags
+}
+
+// AllRDSInstances describes every DB instance in the requested regions
+// *RDSInstances are created for each *rds.DBInstance
+// and are passed to a channel
+func AllRDSInstances() chan *RDSInstance {
+	ch := make(chan *RDSInstance, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := rds.New(sess, aws.NewConfig().WithRegion(region))
+			err := api.DescribeDBInstancesPages(&rds.DescribeDBInstancesInput{}, func(resp *rds.DescribeDBInstancesOutput, lastPage bool) bool {
+				for _, db := range resp.DBInstances {
+					tags, err := api.ListTagsForResource(&rds.ListTagsForResourceInput{
+						ResourceName: aws.String(rdsInstanceARN(region, *db.DBInstanceIdentifier)),
+					})
+					// without its tags, a DB instance's state and whitelisting are unknown
+					if err != nil {
+						log.Error("Error listing tags for RDSInstance %s in %s: %s", *db.DBInstanceIdentifier, region, err.Error())
+						describeFailed("RDSInstance", region)
+						continue
+					}
+					ch <- NewRDSInstance(region, db, tags.TagList)
+				}
+				// if we are at the last page, we should not continue
+				// the return value of this func is "shouldContinue"
+				return !lastPage
+			})
+			if err != nil {
+				log.Error("Error describing RDSInstances in %s: %s", region, err.Error())
+				describeFailed("RDSInstance", region)
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// AllAddresses describes every Elastic IP address in the requested regions
+// *Addresses are created for each *ec2.Address
+// and are passed to a channel
+func AllAddresses() chan *Address {
+	ch := make(chan *Address, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := ec2.New(sess, aws.NewConfig().WithRegion(region))
+			resp, err := api.DescribeAddresses(&ec2.DescribeAddressesInput{})
+			if err != nil {
+				log.Error("Error describing Addresses in %s: %s", region, err.Error())
+				return
+			}
+
+			// only VPC addresses can be tagged, by their allocation id
+			var allocationIDs []*string
+			for _, address := range resp.Addresses {
+				if address.AllocationId != nil {
+					allocationIDs = append(allocationIDs, address.AllocationId)
+				}
+			}
+			tags := make(map[string][]*ec2.TagDescription)
+			if len(allocationIDs) > 0 {
+				input := &ec2.DescribeTagsInput{
+					Filters: []*ec2.Filter{
+						&ec2.Filter{
+							Name:   aws.String("resource-id"),
+							Values: allocationIDs,
+						},
+					},
+				}
+				err = api.DescribeTagsPages(input, func(resp *ec2.DescribeTagsOutput, lastPage bool) bool {
+					for _, tag := range resp.Tags {
+						tags[*tag.ResourceId] = append(tags[*tag.ResourceId], tag)
+					}
+					// if we are at the last page, we should not continue
+					// the return value of this func is "shouldContinue"
+					return !lastPage
+				})
+				if err != nil {
+					log.Error("Error describing Address tags in %s: %s", region, err.Error())
+				}
+			}
+
+			for _, address := range resp.Addresses {
+				var addressTags []*ec2.TagDescription
+				if address.AllocationId != nil {
+					addressTags = tags[*address.AllocationId]
+				}
+				ch <- NewAddress(region, address, addressTags)
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// AllNetworkInterfaces describes every network interface in the requested regions
+// *NetworkInterfaces are created for each *ec2.NetworkInterface
+// and are passed to a channel
+func AllNetworkInterfaces() chan *NetworkInterface {
+	ch := make(chan *NetworkInterface, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := ec2.New(sess, aws.NewConfig().WithRegion(region))
+			resp, err := api.DescribeNetworkInterfaces(&ec2.DescribeNetworkInterfacesInput{})
+			if err != nil {
+				log.Error("Error describing NetworkInterfaces in %s: %s", region, err.Error())
+				return
+			}
+			for _, eni := range resp.NetworkInterfaces {
+				ch <- NewNetworkInterface(region, eni)
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// AllLaunchConfigurations describes every LaunchConfiguration in the requested regions
+// *LaunchConfigurations are created for each *autoscaling.LaunchConfiguration
+// and are passed to a channel
+func AllLaunchConfigurations() chan *LaunchConfiguration {
+	ch := make(chan *LaunchConfiguration, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := autoscaling.New(sess, aws.NewConfig().WithRegion(region))
+			err := api.DescribeLaunchConfigurationsPages(&autoscaling.DescribeLaunchConfigurationsInput{}, func(resp *autoscaling.DescribeLaunchConfigurationsOutput, lastPage bool) bool {
+				for _, lc := range resp.LaunchConfigurations {
+					ch <- NewLaunchConfiguration(region, lc)
+				}
+				// if we are at the last page, we should not continue
+				// the return value of this func is "shouldContinue"
+				return !lastPage
+			})
+			if err != nil {
+				log.Error("Error describing LaunchConfigurations in %s: %s", region, err.Error())
+				describeFailed("LaunchConfiguration", region)
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// AllCacheClusters describes every ElastiCache cluster in the requested regions
+// members of a replication group are grouped into a single *CacheCluster
+// and are passed to a channel
+func AllCacheClusters() chan *CacheCluster {
+	ch := make(chan *CacheCluster, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := elasticache.New(sess, aws.NewConfig().WithRegion(region))
+
+			var groups []*elasticache.ReplicationGroup
+			err := api.DescribeReplicationGroupsPages(&elasticache.DescribeReplicationGroupsInput{}, func(resp *elasticache.DescribeReplicationGroupsOutput, lastPage bool) bool {
+				groups = append(groups, resp.ReplicationGroups...)
+				// if we are at the last page, we should not continue
+				// the return value of this func is "shouldContinue"
+				return !lastPage
+			})
+			if err != nil {
+				log.Error("Error describing ReplicationGroups in %s: %s", region, err.Error())
+				describeFailed("CacheCluster", region)
+				// without groups, their members would be reaped individually
+				return
+			}
+
+			clusters := make(map[string]*elasticache.CacheCluster)
+			err = api.DescribeCacheClustersPages(&elasticache.DescribeCacheClustersInput{}, func(resp *elasticache.DescribeCacheClustersOutput, lastPage bool) bool {
+				for _, cluster := range resp.CacheClusters {
+					clusters[*cluster.CacheClusterId] = cluster
+				}
+				// if we are at the last page, we should not continue
+				// the return value of this func is "shouldContinue"
+				return !lastPage
+			})
+			if err != nil {
+				log.Error("Error describing CacheClusters in %s: %s", region, err.Error())
+				describeFailed("CacheCluster", region)
+				return
+			}
+
+			// without its tags, a cluster's state and whitelisting are unknown
+			for _, cluster := range clusters {
+				if cluster.ReplicationGroupId != nil {
+					continue
+				}
+				tags, err := cacheClusterTags(api, region, *cluster.CacheClusterId)
+				if err != nil {
+					log.Error("Error listing tags for CacheCluster %s in %s: %s", *cluster.CacheClusterId, region, err.Error())
+					describeFailed("CacheCluster", region)
+					continue
+				}
+				ch <- NewCacheCluster(region, cluster, tags)
+			}
+
+			for _, group := range groups {
+				var members []*elasticache.CacheCluster
+				for _, id := range group.MemberClusters {
+					if cluster, ok := clusters[*id]; ok {
+						members = append(members, cluster)
+					}
+				}
+				if len(members) == 0 {
+					continue
+				}
+				tags, err := cacheClusterTags(api, region, *members[0].CacheClusterId)
+				if err != nil {
+					log.Error("Error listing tags for CacheCluster %s in %s: %s", *group.ReplicationGroupId, region, err.Error())
+					describeFailed("CacheCluster", region)
+					continue
+				}
+				ch <- NewReplicationGroupCacheCluster(region, group, members, tags)
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// cacheClusterTags lists the tags of a cache cluster
+// replication groups are tagged through their members
+func cacheClusterTags(api *elasticache.ElastiCache, region, id string) ([]*elasticache.Tag, error) {
+	resp, err := api.ListTagsForResource(&elasticache.ListTagsForResourceInput{
+		ResourceName: aws.String(cacheClusterARN(region, id)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.TagList, nil
+}
+
+// AllRedshiftClusters describes every Redshift cluster in the requested regions
+// *RedshiftClusters are created for each *redshift.Cluster
+// and are passed to a channel
+func AllRedshiftClusters() chan *RedshiftCluster {
+	ch := make(chan *RedshiftCluster, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := redshift.New(sess, aws.NewConfig().WithRegion(region))
+			err := api.DescribeClustersPages(&redshift.DescribeClustersInput{}, func(resp *redshift.DescribeClustersOutput, lastPage bool) bool {
+				for _, cluster := range resp.Clusters {
+					ch <- NewRedshiftCluster(region, cluster)
+				}
+				// if we are at the last page, we should not continue
+				// the return value of this func is "shouldContinue"
+				return !lastPage
+			})
+			if err != nil {
+				log.Error("Error describing RedshiftClusters in %s: %s", region, err.Error())
+				describeFailed("RedshiftCluster", region)
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// AllEMRClusters describes every active EMR cluster in the requested regions
+// *EMRClusters are created for each *emr.Cluster
+// and are passed to a channel
+func AllEMRClusters() chan *EMRCluster {
+	ch := make(chan *EMRCluster, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := emr.New(sess, aws.NewConfig().WithRegion(region))
+			input := &emr.ListClustersInput{
+				ClusterStates: []*string{
+					aws.String(emr.ClusterStateStarting),
+					aws.String(emr.ClusterStateBootstrapping),
+					aws.String(emr.ClusterStateRunning),
+					aws.String(emr.ClusterStateWaiting),
+				},
+			}
+			var ids []*string
+			err := api.ListClustersPages(input, func(resp *emr.ListClustersOutput, lastPage bool) bool {
+				for _, summary := range resp.Clusters {
+					ids = append(ids, summary.Id)
+				}
+				// if we are at the last page, we should not continue
+				// the return value of this func is "shouldContinue"
+				return !lastPage
+			})
+			if err != nil {
+				log.Error("Error listing EMRClusters in %s: %s", region, err.Error())
+				describeFailed("EMRCluster", region)
+				return
+			}
+
+			// tags and termination protection are only described one cluster at a time
+			for _, id := range ids {
+				resp, err := api.DescribeCluster(&emr.DescribeClusterInput{ClusterId: id})
+				if err != nil {
+					log.Error("Error describing EMRCluster %s in %s: %s", *id, region, err.Error())
+					describeFailed("EMRCluster", region)
+					continue
+				}
+				ch <- NewEMRCluster(region, resp.Cluster, emrClusterStateChangeTime(api, region, resp.Cluster))
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// emrClusterStateChangeTime returns when an EMR cluster entered its current state
+// a cluster is WAITING since its last step ended, and RUNNING since its current step started
+func emrClusterStateChangeTime(api *emr.EMR, region string, cluster *emr.Cluster) time.Time {
+	var stateChangeTime time.Time
+	if cluster.Status == nil || cluster.Status.Timeline == nil {
+		return stateChangeTime
+	}
+	if cluster.Status.Timeline.CreationDateTime != nil {
+		stateChangeTime = *cluster.Status.Timeline.CreationDateTime
+	}
+	if cluster.Status.Timeline.ReadyDateTime != nil {
+		stateChangeTime = *cluster.Status.Timeline.ReadyDateTime
+	}
+
+	var stepStates []*string
+	switch *cluster.Status.State {
+	case emr.ClusterStateWaiting:
+		stepStates = aws.StringSlice([]string{
+			emr.StepStateCompleted,
+			emr.StepStateCancelled,
+			emr.StepStateFailed,
+			emr.StepStateInterrupted,
+		})
+	case emr.ClusterStateRunning:
+		stepStates = aws.StringSlice([]string{emr.StepStateRunning})
+	default:
+		return stateChangeTime
+	}
+
+	// steps are listed most recent first, so the first page is enough
+	resp, err := api.ListSteps(&emr.ListStepsInput{
+		ClusterId:  cluster.Id,
+		StepStates: stepStates,
+	})
+	if err != nil {
+		log.Error("Error listing steps of EMRCluster %s in %s: %s", *cluster.Id, region, err.Error())
+		return stateChangeTime
+	}
+	for _, step := range resp.Steps {
+		if step.Status == nil || step.Status.Timeline == nil {
+			continue
+		}
+		t := step.Status.Timeline.EndDateTime
+		if *cluster.Status.State == emr.ClusterStateRunning {
+			t = step.Status.Timeline.StartDateTime
+		}
+		if t != nil && t.After(stateChangeTime) {
+			stateChangeTime = *t
+		}
+	}
+	return stateChangeTime
+}
+
+// AllBuckets describes every Bucket in the requested regions
+// S3 lists Buckets globally, so each Bucket's region comes from GetBucketLocation
+// *Buckets are created for each *s3.Bucket
+// and are passed to a channel
+func AllBuckets() chan *Bucket {
+	ch := make(chan *Bucket, len(config.Regions))
+	go func() {
+		defer close(ch)
+		regions := make(map[string]bool)
+		for _, region := range config.Regions {
+			regions[region] = true
+		}
+
+		api := s3.New(sess, aws.NewConfig().WithRegion("us-east-1"))
+		resp, err := api.ListBuckets(&s3.ListBucketsInput{})
+		if err != nil {
+			log.Error("Error listing Buckets: %s", err.Error())
+			return
+		}
+
+		for _, bucket := range resp.Buckets {
+			location, err := api.GetBucketLocation(&s3.GetBucketLocationInput{Bucket: bucket.Name})
+			if err != nil {
+				log.Error("Error getting the location of Bucket %s: %s", *bucket.Name, err.Error())
+				continue
+			}
+			region := bucketRegion(location.LocationConstraint)
+			if !regions[region] {
+				continue
+			}
+
+			regionAPI := s3.New(sess, aws.NewConfig().WithRegion(region))
+			tags, err := bucketTags(regionAPI, *bucket.Name)
+			if err != nil {
+				log.Error("Error getting the tags of Bucket %s in %s: %s", *bucket.Name, region, err.Error())
+				continue
+			}
+			contents, err := regionAPI.ListObjects(&s3.ListObjectsInput{
+				Bucket:  bucket.Name,
+				MaxKeys: aws.Int64(bucketListingBound),
+			})
+			if err != nil {
+				log.Error("Error listing the contents of Bucket %s in %s: %s", *bucket.Name, region, err.Error())
+				continue
+			}
+			ch <- NewBucket(region, bucket, tags, contents)
+		}
+	}()
+	return ch
+}
+
+// bucketRegion returns the region of a GetBucketLocation LocationConstraint
+// us-east-1 has no LocationConstraint, and EU is the legacy name of eu-west-1
+func bucketRegion(constraint *string) string {
+	if constraint == nil || *constraint == "" {
+		return "us-east-1"
+	}
+	if *constraint == "EU" {
+		return "eu-west-1"
+	}
+	return *constraint
+}
+
+// AllLambdaFunctions describes every LambdaFunction in the requested regions
+// *LambdaFunctions are created for each *lambda.FunctionConfiguration
+// and are passed to a channel
+func AllLambdaFunctions() chan *LambdaFunction {
+	ch := make(chan *LambdaFunction, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := lambda.New(sess, aws.NewConfig().WithRegion(region))
+
+			// event source mappings reference functions by ARN, possibly qualified
+			// arn:aws:lambda:region:account:function:name[:qualifier]
+			eventSourceMappings := make(map[string]int)
+			err := api.ListEventSourceMappingsPages(&lambda.ListEventSourceMappingsInput{}, func(resp *lambda.ListEventSourceMappingsOutput, lastPage bool) bool {
+				for _, mapping := range resp.EventSourceMappings {
+					if parts := strings.Split(*mapping.FunctionArn, ":"); len(parts) > 6 {
+						eventSourceMappings[parts[6]]++
+					}
+				}
+				// if we are at the last page, we should not continue
+				// the return value of this func is "shouldContinue"
+				return !lastPage
+			})
+			if err != nil {
+				log.Error("Error listing event source mappings in %s: %s", region, err.Error())
+				describeFailed("LambdaFunction", region)
+				return
+			}
+
+			err = api.ListFunctionsPages(&lambda.ListFunctionsInput{}, func(resp *lambda.ListFunctionsOutput, lastPage bool) bool {
+				for _, function := range resp.Functions {
+					ch <- NewLambdaFunction(region, function, eventSourceMappings[*function.FunctionName])
+				}
+				// if we are at the last page, we should not continue
+				// the return value of this func is "shouldContinue"
+				return !lastPage
+			})
+			if err != nil {
+				log.Error("Error listing LambdaFunctions in %s: %s", region, err.Error())
+				describeFailed("LambdaFunction", region)
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// AllDynamoDBTables describes every DynamoDBTable in the requested regions
+// *DynamoDBTables are created for each *dynamodb.TableDescription
+// and are passed to a channel
+func AllDynamoDBTables() chan *DynamoDBTable {
+	ch := make(chan *DynamoDBTable, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := dynamodb.New(sess, aws.NewConfig().WithRegion(region))
+			var names []*string
+			err := api.ListTablesPages(&dynamodb.ListTablesInput{}, func(resp *dynamodb.ListTablesOutput, lastPage bool) bool {
+				names = append(names, resp.TableNames...)
+				// if we are at the last page, we should not continue
+				// the return value of this func is "shouldContinue"
+				return !lastPage
+			})
+			if err != nil {
+				log.Error("Error listing DynamoDBTables in %s: %s", region, err.Error())
+				return
+			}
+
+			// capacity, item count and size are only described one table at a time
+			for _, name := range names {
+				resp, err := api.DescribeTable(&dynamodb.DescribeTableInput{TableName: name})
+				if err != nil {
+					log.Error("Error describing DynamoDBTable %s in %s: %s", *name, region, err.Error())
+					continue
+				}
+				ch <- NewDynamoDBTable(region, resp.Table)
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// ecsClusters describes every ECS cluster in a region
+func ecsClusters(api *ecs.ECS, region string) []*ecs.Cluster {
+	var arns []*string
+	err := api.ListClustersPages(&ecs.ListClustersInput{}, func(resp *ecs.ListClustersOutput, lastPage bool) bool {
+		arns = append(arns, resp.ClusterArns...)
+		// if we are at the last page, we should not continue
+		// the return value of this func is "shouldContinue"
+		return !lastPage
+	})
+	if err != nil {
+		log.Error("Error listing ECSClusters in %s: %s", region, err.Error())
+		describeFailed("ECSCluster", region)
+		return nil
+	}
+
+	var clusters []*ecs.Cluster
+	for i := 0; i < len(arns); i += 100 {
+		end := i + 100
+		if end > len(arns) {
+			end = len(arns)
+		}
+		resp, err := api.DescribeClusters(&ecs.DescribeClustersInput{Clusters: arns[i:end]})
+		if err != nil {
+			log.Error("Error describing ECSClusters in %s: %s", region, err.Error())
+			describeFailed("ECSCluster", region)
+			continue
+		}
+		clusters = append(clusters, resp.Clusters...)
+	}
+	return clusters
+}
+
+// ecsContainerInstanceIDs returns the EC2 instance IDs of an ECS cluster's container instances
+func ecsContainerInstanceIDs(api *ecs.ECS, region string, cluster *ecs.Cluster) []reapable.ID {
+	var arns []*string
+	input := &ecs.ListContainerInstancesInput{Cluster: cluster.ClusterArn}
+	err := api.ListContainerInstancesPages(input, func(resp *ecs.ListContainerInstancesOutput, lastPage bool) bool {
+		arns = append(arns, resp.ContainerInstanceArns...)
+		// if we are at the last page, we should not continue
+		// the return value of this func is "shouldContinue"
+		return !lastPage
+	})
+	if err != nil {
+		log.Error("Error listing container instances of ECSCluster %s in %s: %s", *cluster.ClusterName, region, err.Error())
+		return nil
+	}
+
+	var ids []reapable.ID
+	for i := 0; i < len(arns); i += 100 {
+		end := i + 100
+		if end > len(arns) {
+			end = len(arns)
+		}
+		resp, err := api.DescribeContainerInstances(&ecs.DescribeContainerInstancesInput{
+			Cluster:            cluster.ClusterArn,
+			ContainerInstances: arns[i:end],
+		})
+		if err != nil {
+			log.Error("Error describing container instances of ECSCluster %s in %s: %s", *cluster.ClusterName, region, err.Error())
+			continue
+		}
+		for _, instance := range resp.ContainerInstances {
+			if instance.Ec2InstanceId != nil {
+				ids = append(ids, reapable.ID(*instance.Ec2InstanceId))
+			}
+		}
+	}
+	return ids
+}
+
+// AllECSClusters describes every ECSCluster in the requested regions
+// *ECSClusters are created for each *ecs.Cluster
+// and are passed to a channel
+func AllECSClusters() chan *ECSCluster {
+	ch := make(chan *ECSCluster, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := ecs.New(sess, aws.NewConfig().WithRegion(region))
+			for _, cluster := range ecsClusters(api, region) {
+				ch <- NewECSCluster(region, cluster, ecsContainerInstanceIDs(api, region, cluster))
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// AllECSServices describes every ECSService of every ECSCluster in the requested regions
+// *ECSServices are created for each *ecs.Service
+// and are passed to a channel
+func AllECSServices() chan *ECSService {
+	ch := make(chan *ECSService, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := ecs.New(sess, aws.NewConfig().WithRegion(region))
+			for _, cluster := range ecsClusters(api, region) {
+				var arns []*string
+				input := &ecs.ListServicesInput{Cluster: cluster.ClusterArn}
+				err := api.ListServicesPages(input, func(resp *ecs.ListServicesOutput, lastPage bool) bool {
+					arns = append(arns, resp.ServiceArns...)
+					// if we are at the last page, we should not continue
+					// the return value of this func is "shouldContinue"
+					return !lastPage
+				})
+				if err != nil {
+					log.Error("Error listing ECSServices of ECSCluster %s in %s: %s", *cluster.ClusterName, region, err.Error())
+					continue
+				}
+
+				for i := 0; i < len(arns); i += 10 {
+					end := i + 10
+					if end > len(arns) {
+						end = len(arns)
+					}
+					resp, err := api.DescribeServices(&ecs.DescribeServicesInput{
+						Cluster:  cluster.ClusterArn,
+						Services: arns[i:end],
+					})
+					if err != nil {
+						log.Error("Error describing ECSServices of ECSCluster %s in %s: %s", *cluster.ClusterName, region, err.Error())
+						continue
+					}
+					for _, service := range resp.Services {
+						ch <- NewECSService(region, *cluster.ClusterName, service)
+					}
+				}
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// AllEFSFileSystems describes every EFS file system in the requested regions
+// *EFSFileSystems are created for each *efs.FileSystemDescription
+// and are passed to a channel
+func AllEFSFileSystems() chan *EFSFileSystem {
+	ch := make(chan *EFSFileSystem, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := efs.New(sess, aws.NewConfig().WithRegion(region))
+			input := &efs.DescribeFileSystemsInput{}
+			for {
+				resp, err := api.DescribeFileSystems(input)
+				if err != nil {
+					log.Error("Error describing EFSFileSystems in %s: %s", region, err.Error())
+					return
+				}
+				for _, fs := range resp.FileSystems {
+					tags, err := efsFileSystemTags(api, *fs.FileSystemId)
+					// without its tags, a file system's state and whitelisting are unknown
+					if err != nil {
+						log.Error("Error describing tags for EFSFileSystem %s in %s: %s", *fs.FileSystemId, region, err.Error())
+						continue
+					}
+					ch <- NewEFSFileSystem(region, fs, tags)
+				}
+				if resp.NextMarker == nil {
+					break
+				}
+				input.Marker = resp.NextMarker
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// efsFileSystemTags returns the tags of an EFS file system
+// file systems have at most 50 tags, fewer than a page
+func efsFileSystemTags(api *efs.EFS, id string) ([]*efs.Tag, error) {
+	resp, err := api.DescribeTags(&efs.DescribeTagsInput{FileSystemId: aws.String(id)})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Tags, nil
+}
+
+// AllElasticsearchDomains describes every Elasticsearch domain in the requested regions
+// *ElasticsearchDomains are created for each *elasticsearchservice.ElasticsearchDomainStatus
+// and are passed to a channel
+func AllElasticsearchDomains() chan *ElasticsearchDomain {
+	ch := make(chan *ElasticsearchDomain, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := elasticsearchservice.New(sess, aws.NewConfig().WithRegion(region))
+			resp, err := api.ListDomainNames(&elasticsearchservice.ListDomainNamesInput{})
+			if err != nil {
+				log.Error("Error listing ElasticsearchDomains in %s: %s", region, err.Error())
+				return
+			}
+			var names []*string
+			for _, domain := range resp.DomainNames {
+				names = append(names, domain.DomainName)
+			}
+
+			// at most 5 domains are described at a time
+			for i := 0; i < len(names); i += 5 {
+				end := i + 5
+				if end > len(names) {
+					end = len(names)
+				}
+				resp, err := api.DescribeElasticsearchDomains(&elasticsearchservice.DescribeElasticsearchDomainsInput{DomainNames: names[i:end]})
+				if err != nil {
+					log.Error("Error describing ElasticsearchDomains in %s: %s", region, err.Error())
+					continue
+				}
+				for _, domain := range resp.DomainStatusList {
+					// domains being deleted are still listed
+					if domain.Deleted != nil && *domain.Deleted {
+						continue
+					}
+					tags, err := api.ListTags(&elasticsearchservice.ListTagsInput{ARN: domain.ARN})
+					if err != nil {
+						log.Error("Error listing tags for ElasticsearchDomain %s in %s: %s", *domain.DomainName, region, err.Error())
+						continue
+					}
+					ch <- NewElasticsearchDomain(region, domain, elasticsearchDomainCreationDate(api, region, *domain.DomainName), tags.TagList)
+				}
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// elasticsearchDomainCreationDate returns when an Elasticsearch domain was created
+// only the status of its configuration has a creation date
+func elasticsearchDomainCreationDate(api *elasticsearchservice.ElasticsearchService, region, name string) *time.Time {
+	resp, err := api.DescribeElasticsearchDomainConfig(&elasticsearchservice.DescribeElasticsearchDomainConfigInput{
+		DomainName: aws.String(name),
+	})
+	if err != nil {
+		log.Error("Error describing the config of ElasticsearchDomain %s in %s: %s", name, region, err.Error())
+		return nil
+	}
+	if resp.DomainConfig.ElasticsearchClusterConfig == nil || resp.DomainConfig.ElasticsearchClusterConfig.Status == nil {
+		return nil
+	}
+	return resp.DomainConfig.ElasticsearchClusterConfig.Status.CreationDate
+}
+
+// AllLogGroups describes every CloudWatch Logs log group in the requested regions
+// *LogGroups are created for each *cloudwatchlogs.LogGroup
+// and are passed to a channel
+func AllLogGroups() chan *LogGroup {
+	ch := make(chan *LogGroup, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := cloudwatchlogs.New(sess, aws.NewConfig().WithRegion(region))
+			err := api.DescribeLogGroupsPages(&cloudwatchlogs.DescribeLogGroupsInput{}, func(resp *cloudwatchlogs.DescribeLogGroupsOutput, lastPage bool) bool {
+				for _, group := range resp.LogGroups {
+					lastEventTime, err := logGroupLastEventTime(api, *group.LogGroupName)
+					if err != nil {
+						log.Error("Error describing log streams of LogGroup %s in %s: %s", *group.LogGroupName, region, err.Error())
+					}
+					ch <- NewLogGroup(region, group, lastEventTime, err == nil)
+				}
+				// if we are at the last page, we should not continue
+				// the return value of this func is "shouldContinue"
+				return !lastPage
+			})
+			if err != nil {
+				log.Error("Error describing LogGroups in %s: %s", region, err.Error())
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// logGroupLastEventTime returns the time of the latest event in a log group
+// from its most recently written log stream, zero if it has none
+func logGroupLastEventTime(api *cloudwatchlogs.CloudWatchLogs, name string) (time.Time, error) {
+	resp, err := api.DescribeLogStreams(&cloudwatchlogs.DescribeLogStreamsInput{
+		LogGroupName: aws.String(name),
+		OrderBy:      aws.String(cloudwatchlogs.OrderByLastEventTime),
+		Descending:   aws.Bool(true),
+		Limit:        aws.Int64(1),
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(resp.LogStreams) == 0 || resp.LogStreams[0].LastEventTimestamp == nil {
+		return time.Time{}, nil
+	}
+	return millisecondsToTime(*resp.LogStreams[0].LastEventTimestamp), nil
+}
+
+// AllKeyPairs describes every KeyPair in the requested regions
+// *KeyPairs are created for each *ec2.KeyPairInfo
+// and are passed to a channel
+func AllKeyPairs() chan *KeyPair {
+	ch := make(chan *KeyPair, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := ec2.New(sess, aws.NewConfig().WithRegion(region))
+			resp, err := api.DescribeKeyPairs(&ec2.DescribeKeyPairsInput{})
+			if err != nil {
+				log.Error("Error describing KeyPairs in %s: %s", region, err.Error())
+				return
+			}
+			for _, keyPair := range resp.KeyPairs {
+				ch <- NewKeyPair(region, keyPair)
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// AllNatGateways describes every NatGateway in the requested regions
+// *NatGateways are created for each *ec2.NatGateway
+// and are passed to a channel
+func AllNatGateways() chan *NatGateway {
+	ch := make(chan *NatGateway, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := ec2.New(sess, aws.NewConfig().WithRegion(region))
+
+			// route tables with a route to each NAT gateway
+			routeTableIDs := make(map[string][]reapable.ID)
+			resp, err := api.DescribeRouteTables(&ec2.DescribeRouteTablesInput{})
+			if err != nil {
+				log.Error("Error describing route tables in %s: %s", region, err.Error())
+				describeFailed("NatGateway", region)
+				return
+			}
+			for _, table := range resp.RouteTables {
+				for _, route := range table.Routes {
+					if route.NatGatewayId != nil {
+						routeTableIDs[*route.NatGatewayId] = append(routeTableIDs[*route.NatGatewayId], reapable.ID(*table.RouteTableId))
+					}
+				}
+			}
+
+			input := &ec2.DescribeNatGatewaysInput{}
+			for {
+				resp, err := api.DescribeNatGateways(input)
+				if err != nil {
+					log.Error("Error describing NatGateways in %s: %s", region, err.Error())
+					describeFailed("NatGateway", region)
+					return
+				}
+				for _, gateway := range resp.NatGateways {
+					// deleted gateways are listed for about an hour
+					if *gateway.State == ec2.NatGatewayStateDeleted {
+						continue
+					}
+					ch <- NewNatGateway(region, gateway, routeTableIDs[*gateway.NatGatewayId])
+				}
+				if resp.NextToken == nil {
+					break
+				}
+				input.NextToken = resp.NextToken
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// AllCertificates describes every ACM Certificate in the requested regions
+// *Certificates are created for each *acm.CertificateDetail
+// and are passed to a channel
+func AllCertificates() chan *Certificate {
+	ch := make(chan *Certificate, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := acm.New(sess, aws.NewConfig().WithRegion(region))
+			err := api.ListCertificatesPages(&acm.ListCertificatesInput{}, func(resp *acm.ListCertificatesOutput, lastPage bool) bool {
+				for _, summary := range resp.CertificateSummaryList {
+					cert, err := api.DescribeCertificate(&acm.DescribeCertificateInput{CertificateArn: summary.CertificateArn})
+					if err != nil {
+						log.Error("Error describing Certificate %s in %s: %s", *summary.CertificateArn, region, err.Error())
+						continue
+					}
+					tags, err := api.ListTagsForCertificate(&acm.ListTagsForCertificateInput{CertificateArn: summary.CertificateArn})
+					if err != nil {
+						log.Error("Error listing tags for Certificate %s in %s: %s", *summary.CertificateArn, region, err.Error())
+						continue
+					}
+					ch <- NewCertificate(region, cert.Certificate, tags.Tags)
+				}
+				return true
+			})
+			if err != nil {
+				log.Error("Error listing Certificates in %s: %s", region, err.Error())
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// AllECRRepositories describes every ECR repository and its images in the requested regions
+// *ECRRepositories are created for each *ecr.Repository
+// and are passed to a channel
+func AllECRRepositories() chan *ECRRepository {
+	ch := make(chan *ECRRepository, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := ecr.New(sess, aws.NewConfig().WithRegion(region))
+			// without every task definition, images in use could look unreferenced
+			referenced, err := ecsTaskDefinitionImages(ecs.New(sess, aws.NewConfig().WithRegion(region)))
+			if err != nil {
+				log.Error("Error listing the images of ECS task definitions in %s, skipping ECRRepositories: %s", region, err.Error())
+				return
+			}
+
+			input := &ecr.DescribeRepositoriesInput{}
+			for {
+				resp, err := api.DescribeRepositories(input)
+				if err != nil {
+					log.Error("Error describing ECRRepositories in %s: %s", region, err.Error())
+					return
+				}
+				for _, repository := range resp.Repositories {
+					images, err := ecrImages(api, region, repository, referenced)
+					if err != nil {
+						log.Error("Error describing images of ECRRepository %s in %s: %s", *repository.RepositoryName, region, err.Error())
+						continue
+					}
+					ch <- NewECRRepository(region, repository, images)
+				}
+				if resp.NextToken == nil {
+					break
+				}
+				input.NextToken = resp.NextToken
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// ecsTaskDefinitionImages returns the images referenced by active ECS task definitions
+// images referenced without a tag or digest are the latest tag
+// any error is returned, because a partial result would let images in use be reaped
+func ecsTaskDefinitionImages(api *ecs.ECS) (map[string]bool, error) {
+	images := make(map[string]bool)
+	var arns []*string
+	err := api.ListTaskDefinitionsPages(&ecs.ListTaskDefinitionsInput{}, func(resp *ecs.ListTaskDefinitionsOutput, lastPage bool) bool {
+		arns = append(arns, resp.TaskDefinitionArns...)
+		// if we are at the last page, we should not continue
+		// the return value of this func is "shouldContinue"
+		return !lastPage
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, arn := range arns {
+		resp, err := api.DescribeTaskDefinition(&ecs.DescribeTaskDefinitionInput{TaskDefinition: arn})
+		if err != nil {
+			return nil, err
+		}
+		for _, container := range resp.TaskDefinition.ContainerDefinitions {
+			if container.Image == nil {
+				continue
+			}
+			image := *container.Image
+			name := image[strings.LastIndex(image, "/")+1:]
+			if !strings.ContainsAny(name, ":@") {
+				image += ":latest"
+			}
+			images[image] = true
+		}
+	}
+	return images, nil
+}
+
+// ecrImages lists the images of an ECR repository, one per digest
+// referenced are the images referenced by ECS task definitions, from ecsTaskDefinitionImages
+func ecrImages(api *ecr.ECR, region string, repository *ecr.Repository, referenced map[string]bool) ([]*ECRImage, error) {
+	var images []*ECRImage
+	input := &ecr.ListImagesInput{
+		RegistryId:     repository.RegistryId,
+		RepositoryName: repository.RepositoryName,
+	}
+	for {
+		output := &describeImagesOutput{}
+		if err := ecrRequest(api, "DescribeImages", input, output); err != nil {
+			return nil, err
+		}
+		for _, detail := range output.ImageDetails {
+			var imageTags []string
+			inTaskDefinition := referenced[fmt.Sprintf("%s@%s", *repository.RepositoryUri, *detail.ImageDigest)]
+			for _, tag := range detail.ImageTags {
+				imageTags = append(imageTags, *tag)
+				if referenced[fmt.Sprintf("%s:%s", *repository.RepositoryUri, *tag)] {
+					inTaskDefinition = true
+				}
+			}
+			images = append(images, NewECRImage(region, *repository.RepositoryName, *detail.ImageDigest, imageTags, detail.ImagePushedAt, inTaskDefinition))
+		}
+		if output.NextToken == nil {
+			return images, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// AllMetricAlarms describes every CloudWatch alarm in the requested regions
+// *MetricAlarms are created for each *cloudwatch.MetricAlarm
+// and are passed to a channel
+func AllMetricAlarms() chan *MetricAlarm {
+	ch := make(chan *MetricAlarm, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := cloudwatch.New(sess, aws.NewConfig().WithRegion(region))
+			err := api.DescribeAlarmsPages(&cloudwatch.DescribeAlarmsInput{}, func(resp *cloudwatch.DescribeAlarmsOutput, lastPage bool) bool {
+				for _, alarm := range resp.MetricAlarms {
+					ch <- NewMetricAlarm(region, alarm)
+				}
+				// if we are at the last page, we should not continue
+				// the return value of this func is "shouldContinue"
+				return !lastPage
+			})
+			if err != nil {
+				log.Error("Error describing MetricAlarms in %s: %s", region, err.Error())
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// AllHostedZones describes every Route53 HostedZone
+// Route53 is global, so HostedZones are described once, and reported in the first configured region
+// *HostedZones are created for each *route53.HostedZone
+// and are passed to a channel
+func AllHostedZones() chan *HostedZone {
+	ch := make(chan *HostedZone)
+	go func() {
+		defer close(ch)
+		if len(config.Regions) == 0 {
+			return
+		}
+		region := config.Regions[0]
+
+		// VPCs by region, described when a private HostedZone is associated with them
+		vpcs := make(map[string]map[string]bool)
+
+		api := route53.New(sess, aws.NewConfig().WithRegion("us-east-1"))
+		var zones []*route53.HostedZone
+		err := api.ListHostedZonesPages(&route53.ListHostedZonesInput{}, func(resp *route53.ListHostedZonesOutput, lastPage bool) bool {
+			zones = append(zones, resp.HostedZones...)
+			// if we are at the last page, we should not continue
+			// the return value of this func is "shouldContinue"
+			return !lastPage
+		})
+		if err != nil {
+			log.Error("Error listing HostedZones: %s", err.Error())
+			return
+		}
+
+		for _, zone := range zones {
+			id := strings.TrimPrefix(*zone.Id, "/hostedzone/")
+			tags, err := api.ListTagsForResource(&route53.ListTagsForResourceInput{
+				ResourceId:   aws.String(id),
+				ResourceType: aws.String(route53.TagResourceTypeHostedzone),
+			})
+			if err != nil {
+				log.Error("Error listing tags for HostedZone %s: %s", id, err.Error())
+				continue
+			}
+
+			var zoneVPCs []*route53.VPC
+			vpcExists := false
+			if zone.Config != nil && zone.Config.PrivateZone != nil && *zone.Config.PrivateZone {
+				resp, err := api.GetHostedZone(&route53.GetHostedZoneInput{Id: zone.Id})
+				if err != nil {
+					log.Error("Error getting HostedZone %s: %s", id, err.Error())
+					continue
+				}
+				zoneVPCs = resp.VPCs
+				for _, vpc := range zoneVPCs {
+					if _, ok := vpcs[*vpc.VPCRegion]; !ok {
+						vpcs[*vpc.VPCRegion] = vpcIDs(*vpc.VPCRegion)
+					}
+					// VPCs that could not be described are assumed to exist
+					if vpcs[*vpc.VPCRegion] == nil || vpcs[*vpc.VPCRegion][*vpc.VPCId] {
+						vpcExists = true
+					}
+				}
+			}
+
+			ch <- NewHostedZone(region, zone, zoneVPCs, vpcExists, tags.ResourceTagSet.Tags)
+		}
+	}()
+	return ch
+}
+
+// vpcIDs returns the IDs of the VPCs in a region, nil if they could not be described
+func vpcIDs(region string) map[string]bool {
+	api := ec2.New(sess, aws.NewConfig().WithRegion(region))
+	resp, err := api.DescribeVpcs(&ec2.DescribeVpcsInput{})
+	if err != nil {
+		log.Error("Error describing VPCs in %s: %s", region, err.Error())
+		return nil
+	}
+	ids := make(map[string]bool)
+	for _, vpc := range resp.Vpcs {
+		ids[*vpc.VpcId] = true
+	}
+	return ids
+}
+
+// AllWorkSpaces describes every WorkSpace in the requested regions
+// *WorkSpaces are created for each *workspaces.Workspace
+// and are passed to a channel
+func AllWorkSpaces() chan *WorkSpace {
+	ch := make(chan *WorkSpace, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := workspaces.New(sess, aws.NewConfig().WithRegion(region))
+			var all []*workspaces.Workspace
+			err := api.DescribeWorkspacesPages(&workspaces.DescribeWorkspacesInput{}, func(resp *workspaces.DescribeWorkspacesOutput, lastPage bool) bool {
+				all = append(all, resp.Workspaces...)
+				// if we are at the last page, we should not continue
+				// the return value of this func is "shouldContinue"
+				return !lastPage
+			})
+			if err != nil {
+				log.Error("Error describing WorkSpaces in %s: %s", region, err.Error())
+				return
+			}
+			if len(all) == 0 {
+				return
+			}
+
+			runningModes := workspaceRunningModes(api, region)
+			connectionStatuses := workspaceConnectionStatuses(api, region)
+			for _, workspace := range all {
+				if *workspace.State == workspaces.WorkspaceStateTerminated {
+					continue
+				}
+				tags, err := api.DescribeTags(&workspaces.DescribeTagsInput{ResourceId: workspace.WorkspaceId})
+				if err != nil {
+					log.Error("Error describing tags for WorkSpace %s in %s: %s", *workspace.WorkspaceId, region, err.Error())
+					continue
+				}
+				status, connectionStatusKnown := connectionStatuses[*workspace.WorkspaceId]
+				var lastKnownUserConnection *time.Time
+				if connectionStatusKnown {
+					lastKnownUserConnection = status.LastKnownUserConnectionTimestamp
+				}
+				ch <- NewWorkSpace(region, workspace, runningModes[*workspace.WorkspaceId], connectionStatusKnown, lastKnownUserConnection, tags.TagList)
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// AllEBEnvironments describes every Elastic Beanstalk environment in the requested regions
+// *EBEnvironments are created for each *elasticbeanstalk.EnvironmentDescription
+// with the resources it created, and are passed to a channel
+func AllEBEnvironments() chan *EBEnvironment {
+	ch := make(chan *EBEnvironment, len(config.Regions))
+	// waitgroup for all regions
+	wg := sync.WaitGroup{}
+	for _, region := range config.Regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			// add region to waitgroup
+			api := elasticbeanstalk.New(sess, aws.NewConfig().WithRegion(region))
+			resp, err := api.DescribeEnvironments(&elasticbeanstalk.DescribeEnvironmentsInput{
+				IncludeDeleted: aws.Bool(false),
+			})
+			if err != nil {
+				log.Error("Error describing EBEnvironments in %s: %s", region, err.Error())
+				return
+			}
+			for _, environment := range resp.Environments {
+				if *environment.Status == elasticbeanstalk.EnvironmentStatusTerminated {
+					continue
+				}
+				resources, err := api.DescribeEnvironmentResources(&elasticbeanstalk.DescribeEnvironmentResourcesInput{
+					EnvironmentId: environment.EnvironmentId,
+				})
+				if err != nil {
+					log.Error("Error describing resources of EBEnvironment %s in %s: %s", *environment.EnvironmentId, region, err.Error())
+					continue
+				}
+				ch <- NewEBEnvironment(region, environment, resources.EnvironmentResources)
+			}
+		}(region)
+	}
+	go func() {
+		// in a separate goroutine, wait for all regions to finish
+		// when they finish, close the chan
+		wg.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+// metricSum returns the sum of a CloudWatch metric over the last d
+// for the resource identified by a single dimension
+func metricSum(region reapable.Region, namespace, metric, dimension, value string, d time.Duration) (float64, error) {
+	// a single datapoint covers the whole duration
+	// CloudWatch periods are whole minutes, and whole hours for older data
+	period := int64((d + time.Hour - 1) / time.Hour * 3600)
+	end := time.Now()
+	api := cloudwatch.New(sess, aws.NewConfig().WithRegion(region.String()))
+	resp, err := api.GetMetricStatistics(&cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String(namespace),
+		MetricName: aws.String(metric),
+		Dimensions: []*cloudwatch.Dimension{
+			&cloudwatch.Dimension{
+				Name:  aws.String(dimension),
+				Value: aws.String(value),
+			},
+		},
+		StartTime:  aws.Time(end.Add(-d)),
+		EndTime:    aws.Time(end),
+		Period:     aws.Int64(period),
+		Statistics: []*string{aws.String(cloudwatch.StatisticSum)},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var sum float64
+	for _, datapoint := range resp.Datapoints {
+		if datapoint.Sum != nil {
+			sum += *datapoint.Sum
+		}
+	}
+	return sum, nil
+}
@@ -0,0 +1,22 @@
+package aws
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// testSession returns a session whose requests are answered by handler instead of AWS
+// the returned func stops the server
+func testSession(handler http.HandlerFunc) (*session.Session, func()) {
+	server := httptest.NewServer(handler)
+	return session.New(aws.NewConfig().
+		WithEndpoint(server.URL).
+		WithRegion("us-west-2").
+		WithCredentials(credentials.NewStaticCredentials("AKID", "SECRET", "")).
+		WithS3ForcePathStyle(true).
+		WithMaxRetries(0)), server.Close
+}
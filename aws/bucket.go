@@ -0,0 +1,518 @@
+package aws
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// bucketListingBound is the most objects listed per Bucket
+// ObjectCount and Size are lower bounds when a Bucket holds more
+const bucketListingBound = 1000
+
+// ErrBucketNotEmpty is returned when terminating a Bucket with objects
+// unless EmptyBuckets is set in the AWS config
+var ErrBucketNotEmpty = errors.New("Bucket is not empty and EmptyBuckets is not enabled")
+
+// Bucket is a Reapable, Filterable
+// embeds AWS API's s3.Bucket
+type Bucket struct {
+	Resource
+	s3.Bucket
+
+	// from a listing of at most bucketListingBound objects
+	ObjectCount int64
+	Size        int64
+	Truncated   bool
+}
+
+// NewBucket creates a Bucket from the AWS API's s3.Bucket
+// region comes from GetBucketLocation, contents from a bounded ListObjects
+func NewBucket(region string, bucket *s3.Bucket, tags []*s3.Tag, contents *s3.ListObjectsOutput) *Bucket {
+	a := Bucket{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*bucket.Name),
+			Name:   *bucket.Name,
+			Tags:   make(map[string]string),
+		},
+		Bucket: *bucket,
+	}
+
+	for _, tag := range tags {
+		a.Resource.Tags[*tag.Key] = *tag.Value
+	}
+
+	if contents != nil {
+		for _, object := range contents.Contents {
+			a.ObjectCount++
+			if object.Size != nil {
+				a.Size += *object.Size
+			}
+		}
+		a.Truncated = contents.IsTruncated != nil && *contents.IsTruncated
+	}
+
+	if a.Tagged("aws:cloudformation:stack-name") {
+		a.Dependency = true
+		a.IsInCloudformation = true
+	}
+
+	if a.Tagged(reaperTag) {
+		// restore previously tagged state
+		a.reaperState = state.NewStateWithTag(a.Tag(reaperTag))
+	} else {
+		// initial state
+		a.reaperState = state.NewState()
+	}
+
+	return &a
+}
+
+// Empty returns whether the Bucket has no objects
+func (a *Bucket) Empty() bool {
+	return a.ObjectCount == 0 && !a.Truncated
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *Bucket) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableBucketEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *Bucket) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableBucketEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *Bucket) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableBucketEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *Bucket) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableBucketEventHTMLShort)
+	return
+}
+
+type bucketEventData struct {
+	Config        *Config
+	Bucket        *Bucket
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+	IgnoreLink1   string
+	IgnoreLink3   string
+	IgnoreLink7   string
+}
+
+func (a *Bucket) getTemplateData() (interface{}, error) {
+	ignore1, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(1*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore3, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(3*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore7, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bucketEventData{
+		Config:        config,
+		Bucket:        a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+		IgnoreLink1:   ignore1,
+		IgnoreLink3:   ignore3,
+		IgnoreLink7:   ignore7,
+	}, nil
+}
+
+const reapableBucketEventHTML = `
+<html>
+<body>
+	<p>Bucket <a href="{{ .Bucket.AWSConsoleURL }}">{{ .Bucket.ID }} in {{.Bucket.Region}}</a> is scheduled to be terminated.</p>
+
+	<p>
+		{{ if .Bucket.Empty }}It is empty.{{ else }}It holds {{ if .Bucket.Truncated }}more than {{ end }}{{ .Bucket.ObjectCount }} objects ({{ if .Bucket.Truncated }}more than {{ end }}{{ .Bucket.Size }} bytes). {{ if .Config.EmptyBuckets }}They will be deleted with it!{{ else }}Reaper only deletes empty Buckets, so it will not be deleted unless it is emptied.{{ end }}{{ end }}
+	</p>
+
+	<p>
+		You can ignore this message and your Bucket will advance to the next state after <strong>{{.Bucket.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>. If you do not take action it will be terminated!
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Terminate it now</a></li>
+			<li><a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a></li>
+			<li><a href="{{ .IgnoreLink3 }}">Ignore it for 3 more days</a></li>
+			<li><a href="{{ .IgnoreLink7}}">Ignore it for 7 more days</a></li>
+		</ul>
+	</p>
+
+	<p>
+		If you want the Reaper to ignore this Bucket tag it with {{ .Config.WhitelistTag }} with any value, or click <a href="{{ .WhitelistLink }}">here</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableBucketEventHTMLShort = `
+<html>
+<body>
+	<p>Bucket <a href="{{ .Bucket.AWSConsoleURL }}">{{ .Bucket.ID }}</a> in {{.Bucket.Region}} ({{ if .Bucket.Empty }}empty{{ else }}{{ if .Bucket.Truncated }}more than {{ end }}{{ .Bucket.ObjectCount }} objects{{ end }}) is scheduled to be terminated after <strong>{{.Bucket.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>.
+		<br />
+		<a href="{{ .TerminateLink }}">Terminate</a>,
+		<a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a>,
+		<a href="{{ .IgnoreLink3 }}">3 days</a>,
+		<a href="{{ .IgnoreLink7}}"> 7 days</a>, or
+		<a href="{{ .WhitelistLink }}">Whitelist</a> it.
+	</p>
+</body>
+</html>
+`
+
+const reapableBucketEventTextShort = `%%%
+Bucket [{{.Bucket.ID}}]({{.Bucket.AWSConsoleURL}}) in region: {{.Bucket.Region}}.{{if .Bucket.Owned}} Owned by {{.Bucket.Owner}}.{{end}}\n
+{{if .Bucket.Empty}}Empty.{{else}}{{if .Bucket.Truncated}}More than {{end}}{{.Bucket.ObjectCount}} objects.{{end}}\n
+[Whitelist]({{ .WhitelistLink }}) or [Terminate]({{ .TerminateLink }}) this Bucket.
+%%%`
+
+const reapableBucketEventText = `%%%
+Reaper has discovered a Bucket qualified as reapable: [{{.Bucket.ID}}]({{.Bucket.AWSConsoleURL}}) in region: {{.Bucket.Region}}.\n
+{{if .Bucket.Owned}}Owned by {{.Bucket.Owner}}.\n{{end}}
+{{if .Bucket.Empty}}It is empty.{{else}}It holds {{if .Bucket.Truncated}}more than {{end}}{{.Bucket.ObjectCount}} objects ({{if .Bucket.Truncated}}more than {{end}}{{.Bucket.Size}} bytes).{{end}}\n
+{{ if .Bucket.AWSConsoleURL}}{{.Bucket.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.Bucket.AWSConsoleURL}})\n
+[Whitelist]({{ .WhitelistLink }}) this Bucket.
+[Terminate]({{ .TerminateLink }}) this Bucket.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *Bucket) Save(s *state.State) (bool, error) {
+	log.Info("Saving %s", a.ReapableDescriptionTiny())
+	return tagBucket(a.Region(), a.ID(), reaperTag, s.String())
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *Bucket) Unsave() (bool, error) {
+	log.Info("Unsaving %s", a.ReapableDescriptionTiny())
+	return untagBucket(a.Region(), a.ID(), reaperTag)
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+func (a *Bucket) Whitelist() (bool, error) {
+	log.Info("Whitelisting Bucket %s", a.ReapableDescriptionTiny())
+	return tagBucket(a.Region(), a.ID(), config.WhitelistTag, "true")
+}
+
+// bucketTags returns a Bucket's tags
+// a Bucket without tags has no tag set, which S3 reports as an error
+func bucketTags(api *s3.S3, name string) ([]*s3.Tag, error) {
+	resp, err := api.GetBucketTagging(&s3.GetBucketTaggingInput{Bucket: aws.String(name)})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "NoSuchTagSet" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return resp.TagSet, nil
+}
+
+// putBucketTags replaces a Bucket's tags with tags
+func putBucketTags(api *s3.S3, name string, tags []*s3.Tag) error {
+	var err error
+	if len(tags) == 0 {
+		_, err = api.DeleteBucketTagging(&s3.DeleteBucketTaggingInput{Bucket: aws.String(name)})
+	} else {
+		_, err = api.PutBucketTagging(&s3.PutBucketTaggingInput{
+			Bucket:  aws.String(name),
+			Tagging: &s3.Tagging{TagSet: tags},
+		})
+	}
+	return err
+}
+
+// untagBucket and tagBucket rewrite the whole tag set
+// S3 does not allow tagging a single key
+func untagBucket(region reapable.Region, id reapable.ID, key string) (bool, error) {
+	api := s3.New(sess, aws.NewConfig().WithRegion(region.String()))
+	tags, err := bucketTags(api, id.String())
+	if err != nil {
+		return false, err
+	}
+
+	var kept []*s3.Tag
+	for _, tag := range tags {
+		if *tag.Key != key {
+			kept = append(kept, tag)
+		}
+	}
+	if len(kept) == len(tags) {
+		return true, nil
+	}
+
+	if err := putBucketTags(api, id.String(), kept); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func tagBucket(region reapable.Region, id reapable.ID, key, value string) (bool, error) {
+	api := s3.New(sess, aws.NewConfig().WithRegion(region.String()))
+	tags, err := bucketTags(api, id.String())
+	if err != nil {
+		return false, err
+	}
+
+	tagged := false
+	for _, tag := range tags {
+		// tags reserved by AWS cannot be written back
+		if strings.HasPrefix(*tag.Key, "aws:") {
+			return false, fmt.Errorf("Bucket %s has tag %s, which cannot be rewritten", id, *tag.Key)
+		}
+		if *tag.Key == key {
+			tag.Value = aws.String(value)
+			tagged = true
+		}
+	}
+	if !tagged {
+		tags = append(tags, &s3.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(value),
+		})
+	}
+
+	if err := putBucketTags(api, id.String(), tags); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *Bucket) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "IsEmpty":
+		if b, err := filter.BoolValue(0); err == nil && a.Empty() == b {
+			matched = true
+		}
+	case "ObjectCountGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.ObjectCount > i {
+			matched = true
+		}
+	case "ObjectCountLessThan":
+		// a truncated listing does not know how many objects there are
+		if i, err := filter.Int64Value(0); err == nil && !a.Truncated && a.ObjectCount < i {
+			matched = true
+		}
+	case "SizeGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.Size > i {
+			matched = true
+		}
+	case "SizeLessThan":
+		if i, err := filter.Int64Value(0); err == nil && !a.Truncated && a.Size < i {
+			matched = true
+		}
+	case "CreatedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CreationDate != nil && time.Since(*a.CreationDate) < d {
+			matched = true
+		}
+	case "CreatedNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CreationDate != nil && time.Since(*a.CreationDate) > d {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Resource.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Resource.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Resource.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Resource.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering Buckets.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *Bucket) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://console.aws.amazon.com/s3/home?region=%s&bucket=%s",
+		a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+// only empty Buckets are deleted, unless EmptyBuckets is set
+func (a *Bucket) Terminate() (bool, error) {
+	api := s3.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	if !a.Empty() {
+		if !config.EmptyBuckets {
+			log.Error("could not terminate Bucket %s: %s", a.ReapableDescriptionTiny(), ErrBucketNotEmpty.Error())
+			return false, ErrBucketNotEmpty
+		}
+		log.Info("Emptying Bucket %s", a.ReapableDescriptionTiny())
+		if err := emptyBucket(api, a.ID().String()); err != nil {
+			log.Error("could not empty Bucket %s", a.ReapableDescriptionTiny())
+			return false, err
+		}
+	}
+
+	log.Info("Terminating Bucket %s", a.ReapableDescriptionTiny())
+	// S3 refuses to delete a Bucket that is no longer empty
+	_, err := api.DeleteBucket(&s3.DeleteBucketInput{Bucket: aws.String(a.ID().String())})
+	if err != nil {
+		log.Error("could not terminate Bucket %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// emptyBucket deletes every object version and delete marker in a Bucket
+// each listed page is deleted with a single DeleteObjects call
+func emptyBucket(api *s3.S3, name string) error {
+	var deleteErr error
+	err := api.ListObjectVersionsPages(&s3.ListObjectVersionsInput{Bucket: aws.String(name)}, func(resp *s3.ListObjectVersionsOutput, lastPage bool) bool {
+		var objects []*s3.ObjectIdentifier
+		for _, version := range resp.Versions {
+			objects = append(objects, &s3.ObjectIdentifier{Key: version.Key, VersionId: version.VersionId})
+		}
+		for _, marker := range resp.DeleteMarkers {
+			objects = append(objects, &s3.ObjectIdentifier{Key: marker.Key, VersionId: marker.VersionId})
+		}
+		if len(objects) == 0 {
+			return !lastPage
+		}
+
+		out, err := api.DeleteObjects(&s3.DeleteObjectsInput{
+			Bucket: aws.String(name),
+			Delete: &s3.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			deleteErr = err
+			return false
+		}
+		if len(out.Errors) > 0 {
+			deleteErr = fmt.Errorf("could not delete %d objects from Bucket %s", len(out.Errors), name)
+			return false
+		}
+		// if we are at the last page, we should not continue
+		// the return value of this func is "shouldContinue"
+		return !lastPage
+	})
+	if err != nil {
+		return err
+	}
+	return deleteErr
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because Buckets cannot be stopped
+func (a *Bucket) Stop() (bool, error) {
+	return false, nil
+}
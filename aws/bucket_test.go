@@ -0,0 +1,173 @@
+package aws
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestBucketRegion(t *testing.T) {
+	for _, test := range []struct {
+		constraint *string
+		region     string
+	}{
+		{nil, "us-east-1"},
+		{aws.String(""), "us-east-1"},
+		{aws.String("EU"), "eu-west-1"},
+		{aws.String("eu-central-1"), "eu-central-1"},
+		{aws.String("us-west-2"), "us-west-2"},
+	} {
+		if region := bucketRegion(test.constraint); region != test.region {
+			t.Errorf("bucketRegion(%s) = %s, want %s", aws.StringValue(test.constraint), region, test.region)
+		}
+	}
+}
+
+func TestBucketFilter(t *testing.T) {
+	a := NewBucket("us-west-2", &s3.Bucket{
+		Name:         aws.String("logs"),
+		CreationDate: aws.Time(time.Now().Add(-48 * time.Hour)),
+	}, nil, &s3.ListObjectsOutput{
+		Contents: []*s3.Object{
+			{Key: aws.String("a"), Size: aws.Int64(100)},
+			{Key: aws.String("b"), Size: aws.Int64(200)},
+		},
+		IsTruncated: aws.Bool(false),
+	})
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"IsEmpty", []string{"false"}, true},
+		{"ObjectCountGreaterThan", []string{"1"}, true},
+		{"ObjectCountLessThan", []string{"3"}, true},
+		{"SizeGreaterThan", []string{"300"}, false},
+		{"SizeLessThan", []string{"301"}, true},
+		{"CreatedInTheLast", []string{"24h"}, false},
+		{"CreatedNotInTheLast", []string{"24h"}, true},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
+
+func TestTruncatedBucketFilter(t *testing.T) {
+	a := NewBucket("us-west-2", &s3.Bucket{Name: aws.String("big")}, nil, &s3.ListObjectsOutput{
+		Contents:    []*s3.Object{{Key: aws.String("a"), Size: aws.Int64(100)}},
+		IsTruncated: aws.Bool(true),
+	})
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"IsEmpty", []string{"true"}, false},
+		// a truncated listing does not know how many objects there are
+		{"ObjectCountLessThan", []string{"1000"}, false},
+		{"SizeLessThan", []string{"1000"}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
+
+// fakeS3 answers the calls Bucket.Terminate makes, and records them
+// DeleteObjects fails for the keys in failing
+type fakeS3 struct {
+	calls   []string
+	deleted []string
+	failing []string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	switch {
+	case r.Method == "GET" && query["versions"] != nil:
+		f.calls = append(f.calls, "ListObjectVersions")
+		fmt.Fprint(w, `<ListVersionsResult><IsTruncated>false</IsTruncated>`+
+			`<Version><Key>a</Key><VersionId>1</VersionId></Version>`+
+			`<DeleteMarker><Key>b</Key><VersionId>2</VersionId></DeleteMarker></ListVersionsResult>`)
+	case r.Method == "POST" && query["delete"] != nil:
+		f.calls = append(f.calls, "DeleteObjects")
+		var input struct {
+			Objects []struct{ Key, VersionId string } `xml:"Object"`
+		}
+		xml.NewDecoder(r.Body).Decode(&input)
+		for _, object := range input.Objects {
+			f.deleted = append(f.deleted, object.Key+"?versionId="+object.VersionId)
+		}
+		fmt.Fprint(w, `<DeleteResult>`)
+		for _, key := range f.failing {
+			fmt.Fprintf(w, `<Error><Key>%s</Key><Code>AccessDenied</Code></Error>`, key)
+		}
+		fmt.Fprint(w, `</DeleteResult>`)
+	case r.Method == "DELETE":
+		f.calls = append(f.calls, "DeleteBucket")
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		f.calls = append(f.calls, r.Method+" "+r.URL.String())
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func TestBucketTerminate(t *testing.T) {
+	defer func(c *Config, s *session.Session) { config, sess = c, s }(config, sess)
+
+	empty := &s3.ListObjectsOutput{IsTruncated: aws.Bool(false)}
+	notEmpty := &s3.ListObjectsOutput{Contents: []*s3.Object{{Key: aws.String("a")}}, IsTruncated: aws.Bool(false)}
+	// only the first page of objects is listed
+	truncated := &s3.ListObjectsOutput{IsTruncated: aws.Bool(true)}
+
+	for _, test := range []struct {
+		contents     *s3.ListObjectsOutput
+		emptyBuckets bool
+		failing      []string
+		terminated   bool
+		calls        []string
+	}{
+		{empty, false, nil, true, []string{"DeleteBucket"}},
+		// Buckets with objects are only emptied if EmptyBuckets is set
+		{notEmpty, false, nil, false, nil},
+		{truncated, false, nil, false, nil},
+		{notEmpty, true, nil, true, []string{"ListObjectVersions", "DeleteObjects", "DeleteBucket"}},
+		// a Bucket that could not be emptied is not deleted
+		{notEmpty, true, []string{"a"}, false, []string{"ListObjectVersions", "DeleteObjects"}},
+	} {
+		f := &fakeS3{failing: test.failing}
+		var closeServer func()
+		sess, closeServer = testSession(f.ServeHTTP)
+		config = &Config{EmptyBuckets: test.emptyBuckets}
+
+		a := NewBucket("us-west-2", &s3.Bucket{Name: aws.String("logs")}, nil, test.contents)
+		terminated, err := a.Terminate()
+		closeServer()
+
+		if terminated != test.terminated || (err == nil) != test.terminated {
+			t.Errorf("%+v: Terminate() = %t, %v, want %t", test, terminated, err, test.terminated)
+		}
+		if !test.emptyBuckets && !a.Empty() && err != ErrBucketNotEmpty {
+			t.Errorf("%+v: Terminate() error = %v, want %v", test, err, ErrBucketNotEmpty)
+		}
+		if strings.Join(f.calls, ",") != strings.Join(test.calls, ",") {
+			t.Errorf("%+v: calls = %v, want %v", test, f.calls, test.calls)
+		}
+		// every version and delete marker is deleted
+		if f.deleted != nil && strings.Join(f.deleted, ",") != "a?versionId=1,b?versionId=2" {
+			t.Errorf("%+v: deleted %v", test, f.deleted)
+		}
+	}
+}
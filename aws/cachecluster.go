@@ -0,0 +1,502 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// CacheCluster is a Reapable, Filterable
+// embeds AWS API's elasticache.CacheCluster
+// a CacheCluster stands for either a standalone cache cluster
+// or a whole replication group, which is deleted as a unit
+type CacheCluster struct {
+	Resource
+	elasticache.CacheCluster
+
+	// set when the CacheCluster stands for a replication group
+	ReplicationGroup *elasticache.ReplicationGroup
+	MemberClusters   []*elasticache.CacheCluster
+
+	SecurityGroupIDs []reapable.ID
+
+	// ARN is used to tag the CacheCluster
+	// replication groups are tagged through their first member
+	ARN string
+}
+
+// NewCacheCluster creates a CacheCluster from the AWS API's elasticache.CacheCluster
+// tags are listed separately by the ElastiCache API
+func NewCacheCluster(region string, cluster *elasticache.CacheCluster, tags []*elasticache.Tag) *CacheCluster {
+	a := CacheCluster{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*cluster.CacheClusterId),
+			Name:   *cluster.CacheClusterId,
+			Tags:   make(map[string]string),
+		},
+		CacheCluster: *cluster,
+		ARN:          cacheClusterARN(region, *cluster.CacheClusterId),
+	}
+
+	for _, group := range cluster.SecurityGroups {
+		if group.SecurityGroupId != nil {
+			a.SecurityGroupIDs = append(a.SecurityGroupIDs, reapable.ID(*group.SecurityGroupId))
+		}
+	}
+
+	for _, tag := range tags {
+		a.Resource.Tags[*tag.Key] = *tag.Value
+	}
+
+	if a.Tagged("aws:cloudformation:stack-name") {
+		a.Dependency = true
+		a.IsInCloudformation = true
+	}
+
+	if a.Tagged(reaperTag) {
+		// restore previously tagged state
+		a.reaperState = state.NewStateWithTag(a.Tag(reaperTag))
+	} else {
+		// initial state
+		a.reaperState = state.NewState()
+	}
+
+	return &a
+}
+
+// NewReplicationGroupCacheCluster creates a CacheCluster standing for a replication group
+// members must not be empty, and the first member's tags are used for the group
+func NewReplicationGroupCacheCluster(region string, group *elasticache.ReplicationGroup, members []*elasticache.CacheCluster, tags []*elasticache.Tag) *CacheCluster {
+	a := NewCacheCluster(region, members[0], tags)
+	a.id = reapable.ID(*group.ReplicationGroupId)
+	a.Name = *group.ReplicationGroupId
+	a.ReplicationGroup = group
+	a.MemberClusters = members
+
+	for _, member := range members[1:] {
+		// the group is as old as its oldest member
+		if member.CacheClusterCreateTime != nil &&
+			(a.CacheClusterCreateTime == nil || member.CacheClusterCreateTime.Before(*a.CacheClusterCreateTime)) {
+			a.CacheClusterCreateTime = member.CacheClusterCreateTime
+		}
+		for _, sg := range member.SecurityGroups {
+			if sg.SecurityGroupId != nil {
+				a.SecurityGroupIDs = append(a.SecurityGroupIDs, reapable.ID(*sg.SecurityGroupId))
+			}
+		}
+	}
+	return a
+}
+
+func cacheClusterARN(region, id string) string {
+	return fmt.Sprintf("arn:aws:elasticache:%s:%s:cluster:%s", region, accountID(), id)
+}
+
+// IsReplicationGroup returns whether the CacheCluster stands for a replication group
+func (a *CacheCluster) IsReplicationGroup() bool {
+	return a.ReplicationGroup != nil
+}
+
+// NodeCount returns the number of cache nodes in the CacheCluster, or in all members of its replication group
+func (a *CacheCluster) NodeCount() int64 {
+	if !a.IsReplicationGroup() {
+		if a.NumCacheNodes == nil {
+			return 0
+		}
+		return *a.NumCacheNodes
+	}
+	var count int64
+	for _, member := range a.MemberClusters {
+		if member.NumCacheNodes != nil {
+			count += *member.NumCacheNodes
+		}
+	}
+	return count
+}
+
+// TakesFinalSnapshot returns whether a final snapshot is taken when the CacheCluster is terminated
+// only Redis supports snapshots, and not on cache.t1.micro nodes
+func (a *CacheCluster) TakesFinalSnapshot() bool {
+	return a.Engine != nil && *a.Engine == "redis" &&
+		a.CacheNodeType != nil && *a.CacheNodeType != "cache.t1.micro"
+}
+
+// FinalSnapshotIdentifier returns the name of the snapshot taken when the CacheCluster is terminated
+func (a *CacheCluster) FinalSnapshotIdentifier() string {
+	return finalSnapshotIdentifier(a.ID(), a.CacheClusterCreateTime)
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *CacheCluster) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableCacheClusterEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *CacheCluster) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableCacheClusterEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *CacheCluster) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableCacheClusterEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *CacheCluster) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableCacheClusterEventHTMLShort)
+	return
+}
+
+type cacheClusterEventData struct {
+	Config        *Config
+	CacheCluster  *CacheCluster
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+	IgnoreLink1   string
+	IgnoreLink3   string
+	IgnoreLink7   string
+}
+
+func (a *CacheCluster) getTemplateData() (interface{}, error) {
+	ignore1, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(1*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore3, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(3*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore7, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cacheClusterEventData{
+		Config:        config,
+		CacheCluster:  a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+		IgnoreLink1:   ignore1,
+		IgnoreLink3:   ignore3,
+		IgnoreLink7:   ignore7,
+	}, nil
+}
+
+const reapableCacheClusterEventHTML = `
+<html>
+<body>
+	<p>{{ if .CacheCluster.IsReplicationGroup }}ElastiCache replication group{{ else }}ElastiCache cluster{{ end }} <a href="{{ .CacheCluster.AWSConsoleURL }}">{{ .CacheCluster.ID }} in {{.CacheCluster.Region}}</a> ({{.CacheCluster.Engine}}, {{.CacheCluster.NodeCount}} {{.CacheCluster.CacheNodeType}} nodes) is scheduled to be deleted.</p>
+
+	<p>
+		{{ if .CacheCluster.TakesFinalSnapshot }}A final snapshot named <strong>{{ .CacheCluster.FinalSnapshotIdentifier }}</strong> will be taken before it is deleted.{{ else }}No final snapshot will be taken.{{ end }}
+	</p>
+
+	<p>
+		You can ignore this message and your CacheCluster will advance to the next state after <strong>{{.CacheCluster.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>. If you do not take action it will be deleted!
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+			<li><a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a></li>
+			<li><a href="{{ .IgnoreLink3 }}">Ignore it for 3 more days</a></li>
+			<li><a href="{{ .IgnoreLink7}}">Ignore it for 7 more days</a></li>
+		</ul>
+	</p>
+
+	<p>
+		If you want the Reaper to ignore this CacheCluster tag it with {{ .Config.WhitelistTag }} with any value, or click <a href="{{ .WhitelistLink }}">here</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableCacheClusterEventHTMLShort = `
+<html>
+<body>
+	<p>CacheCluster <a href="{{ .CacheCluster.AWSConsoleURL }}">{{ .CacheCluster.ID }}</a> in {{.CacheCluster.Region}} ({{.CacheCluster.Engine}}, {{.CacheCluster.NodeCount}} {{.CacheCluster.CacheNodeType}} nodes) is scheduled to be deleted after <strong>{{.CacheCluster.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>{{ if .CacheCluster.TakesFinalSnapshot }}, with a final snapshot named {{ .CacheCluster.FinalSnapshotIdentifier }}{{ end }}.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a>,
+		<a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a>,
+		<a href="{{ .IgnoreLink3 }}">3 days</a>,
+		<a href="{{ .IgnoreLink7}}"> 7 days</a>, or
+		<a href="{{ .WhitelistLink }}">Whitelist</a> it.
+	</p>
+</body>
+</html>
+`
+
+const reapableCacheClusterEventTextShort = `%%%
+CacheCluster [{{.CacheCluster.ID}}]({{.CacheCluster.AWSConsoleURL}}) in region: [{{.CacheCluster.Region}}](https://{{.CacheCluster.Region}}.console.aws.amazon.com/elasticache/home?region={{.CacheCluster.Region}}).{{if .CacheCluster.Owned}} Owned by {{.CacheCluster.Owner}}.{{end}}\n
+{{.CacheCluster.Engine}}, {{.CacheCluster.NodeCount}} {{.CacheCluster.CacheNodeType}} nodes.\n
+[Whitelist]({{ .WhitelistLink }}) or [Delete]({{ .TerminateLink }}) this CacheCluster.
+%%%`
+
+const reapableCacheClusterEventText = `%%%
+Reaper has discovered a CacheCluster qualified as reapable: [{{.CacheCluster.ID}}]({{.CacheCluster.AWSConsoleURL}}) in region: [{{.CacheCluster.Region}}](https://{{.CacheCluster.Region}}.console.aws.amazon.com/elasticache/home?region={{.CacheCluster.Region}}).\n
+{{if .CacheCluster.Owned}}Owned by {{.CacheCluster.Owner}}.\n{{end}}
+{{if .CacheCluster.IsReplicationGroup}}Replication group of {{len .CacheCluster.MemberClusters}} clusters.\n{{end}}
+Engine: {{.CacheCluster.Engine}}, {{.CacheCluster.NodeCount}} {{.CacheCluster.CacheNodeType}} nodes.\n
+{{if .CacheCluster.TakesFinalSnapshot}}A final snapshot named {{.CacheCluster.FinalSnapshotIdentifier}} will be taken before it is deleted.\n{{end}}
+{{ if .CacheCluster.AWSConsoleURL}}{{.CacheCluster.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.CacheCluster.AWSConsoleURL}})\n
+[Whitelist]({{ .WhitelistLink }}) this CacheCluster.
+[Delete]({{ .TerminateLink }}) this CacheCluster.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *CacheCluster) Save(s *state.State) (bool, error) {
+	log.Info("Saving %s", a.ReapableDescriptionTiny())
+	return tagCacheCluster(a.Region(), a.ARN, reaperTag, s.String())
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *CacheCluster) Unsave() (bool, error) {
+	log.Info("Unsaving %s", a.ReapableDescriptionTiny())
+	return untagCacheCluster(a.Region(), a.ARN, reaperTag)
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+func (a *CacheCluster) Whitelist() (bool, error) {
+	log.Info("Whitelisting CacheCluster %s", a.ReapableDescriptionTiny())
+	return tagCacheCluster(a.Region(), a.ARN, config.WhitelistTag, "true")
+}
+
+func untagCacheCluster(region reapable.Region, arn, key string) (bool, error) {
+	api := elasticache.New(sess, aws.NewConfig().WithRegion(region.String()))
+	_, err := api.RemoveTagsFromResource(&elasticache.RemoveTagsFromResourceInput{
+		ResourceName: aws.String(arn),
+		TagKeys:      []*string{aws.String(key)},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func tagCacheCluster(region reapable.Region, arn, key, value string) (bool, error) {
+	api := elasticache.New(sess, aws.NewConfig().WithRegion(region.String()))
+	_, err := api.AddTagsToResource(&elasticache.AddTagsToResourceInput{
+		ResourceName: aws.String(arn),
+		Tags: []*elasticache.Tag{
+			&elasticache.Tag{
+				Key:   aws.String(key),
+				Value: aws.String(value),
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *CacheCluster) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "Engine":
+		// one of:
+		// memcached
+		// redis
+		if a.Engine != nil && *a.Engine == filter.Arguments[0] {
+			matched = true
+		}
+	case "NodeType":
+		if a.CacheNodeType != nil && *a.CacheNodeType == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNodeType":
+		if a.CacheNodeType == nil || *a.CacheNodeType != filter.Arguments[0] {
+			matched = true
+		}
+	case "Status":
+		if a.CacheClusterStatus != nil && *a.CacheClusterStatus == filter.Arguments[0] {
+			matched = true
+		}
+	case "IsReplicationGroup":
+		if b, err := filter.BoolValue(0); err == nil && a.IsReplicationGroup() == b {
+			matched = true
+		}
+	case "NodeCountGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.NodeCount() > i {
+			matched = true
+		}
+	case "NodeCountLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.NodeCount() < i {
+			matched = true
+		}
+	case "NodeCountEqualTo":
+		if i, err := filter.Int64Value(0); err == nil && a.NodeCount() == i {
+			matched = true
+		}
+	case "CreatedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CacheClusterCreateTime != nil && time.Since(*a.CacheClusterCreateTime) < d {
+			matched = true
+		}
+	case "CreatedNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CacheClusterCreateTime != nil && time.Since(*a.CacheClusterCreateTime) > d {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering CacheClusters.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *CacheCluster) AWSConsoleURL() *url.URL {
+	view := "cache-clusters"
+	if a.IsReplicationGroup() {
+		view = "replication-groups"
+	}
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/elasticache/home?region=%s#%s:id=%s",
+		a.Region().String(), a.Region().String(), view, url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+// replication groups are deleted with all their members
+// a final snapshot named FinalSnapshotIdentifier is taken for Redis
+func (a *CacheCluster) Terminate() (bool, error) {
+	log.Info("Terminating CacheCluster %s", a.ReapableDescriptionTiny())
+	api := elasticache.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+
+	var finalSnapshotIdentifier *string
+	if a.TakesFinalSnapshot() {
+		finalSnapshotIdentifier = aws.String(a.FinalSnapshotIdentifier())
+	}
+
+	var err error
+	if a.IsReplicationGroup() {
+		_, err = api.DeleteReplicationGroup(&elasticache.DeleteReplicationGroupInput{
+			ReplicationGroupId:      aws.String(a.ID().String()),
+			FinalSnapshotIdentifier: finalSnapshotIdentifier,
+		})
+	} else {
+		_, err = api.DeleteCacheCluster(&elasticache.DeleteCacheClusterInput{
+			CacheClusterId:          aws.String(a.ID().String()),
+			FinalSnapshotIdentifier: finalSnapshotIdentifier,
+		})
+	}
+	if err != nil {
+		log.Error("could not delete CacheCluster %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because cache clusters cannot be stopped
+func (a *CacheCluster) Stop() (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,78 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestCacheClusterFilter(t *testing.T) {
+	// avoids looking up the account for the ARN
+	account = "123456789012"
+
+	a := NewCacheCluster("us-west-2", &elasticache.CacheCluster{
+		CacheClusterId:         aws.String("cache"),
+		Engine:                 aws.String("memcached"),
+		CacheNodeType:          aws.String("cache.t2.micro"),
+		CacheClusterStatus:     aws.String("available"),
+		NumCacheNodes:          aws.Int64(2),
+		CacheClusterCreateTime: aws.Time(time.Now().Add(-48 * time.Hour)),
+	}, nil)
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"Engine", []string{"memcached"}, true},
+		{"Engine", []string{"redis"}, false},
+		{"NodeType", []string{"cache.t2.micro"}, true},
+		{"NotNodeType", []string{"cache.t2.micro"}, false},
+		{"Status", []string{"available"}, true},
+		{"IsReplicationGroup", []string{"false"}, true},
+		{"NodeCountGreaterThan", []string{"1"}, true},
+		{"NodeCountLessThan", []string{"2"}, false},
+		{"NodeCountEqualTo", []string{"2"}, true},
+		{"CreatedInTheLast", []string{"24h"}, false},
+		{"CreatedNotInTheLast", []string{"24h"}, true},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
+
+func TestReplicationGroupCacheClusterFilter(t *testing.T) {
+	// avoids looking up the account for the ARN
+	account = "123456789012"
+
+	oldest := time.Now().Add(-72 * time.Hour)
+	a := NewReplicationGroupCacheCluster("us-west-2", &elasticache.ReplicationGroup{
+		ReplicationGroupId: aws.String("group"),
+	}, []*elasticache.CacheCluster{
+		{CacheClusterId: aws.String("group-001"), Engine: aws.String("redis"), NumCacheNodes: aws.Int64(1), CacheClusterCreateTime: aws.Time(time.Now().Add(-time.Hour))},
+		{CacheClusterId: aws.String("group-002"), Engine: aws.String("redis"), NumCacheNodes: aws.Int64(1), CacheClusterCreateTime: aws.Time(oldest)},
+	}, nil)
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"IsReplicationGroup", []string{"true"}, true},
+		{"Named", []string{"group"}, true},
+		// nodes of all members
+		{"NodeCountEqualTo", []string{"2"}, true},
+		// as old as its oldest member
+		{"CreatedNotInTheLast", []string{"48h"}, true},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
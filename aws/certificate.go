@@ -0,0 +1,422 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/acm"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// Certificate is a Reapable, Filterable
+// embeds AWS API's acm.CertificateDetail
+type Certificate struct {
+	Resource
+	acm.CertificateDetail
+}
+
+// NewCertificate creates a Certificate from the AWS API's acm.CertificateDetail
+// certificates in use by other resources are dependencies
+func NewCertificate(region string, cert *acm.CertificateDetail, tags []*acm.Tag) *Certificate {
+	a := Certificate{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*cert.CertificateArn),
+			Name:   *cert.DomainName,
+			Tags:   make(map[string]string),
+		},
+		CertificateDetail: *cert,
+	}
+
+	for _, tag := range tags {
+		if tag.Value != nil {
+			a.Resource.Tags[*tag.Key] = *tag.Value
+		} else {
+			a.Resource.Tags[*tag.Key] = ""
+		}
+	}
+
+	if a.InUse() {
+		a.Dependency = true
+	}
+
+	if a.Tagged("aws:cloudformation:stack-name") {
+		a.Dependency = true
+		a.IsInCloudformation = true
+	}
+
+	if a.Tagged(reaperTag) {
+		// restore previously tagged state
+		a.reaperState = state.NewStateWithTag(a.Tag(reaperTag))
+	} else {
+		// initial state
+		a.reaperState = state.NewState()
+	}
+
+	return &a
+}
+
+// InUse returns whether any resource, such as a LoadBalancer or a CloudFront distribution, uses the Certificate
+func (a *Certificate) InUse() bool {
+	return len(a.InUseBy) > 0
+}
+
+// Expired returns whether the Certificate is past its expiry date
+func (a *Certificate) Expired() bool {
+	return a.NotAfter != nil && time.Now().After(*a.NotAfter)
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *Certificate) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableCertificateEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *Certificate) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableCertificateEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *Certificate) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableCertificateEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *Certificate) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableCertificateEventHTMLShort)
+	return
+}
+
+type certificateEventData struct {
+	Config        *Config
+	Certificate   *Certificate
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+	IgnoreLink1   string
+	IgnoreLink3   string
+	IgnoreLink7   string
+}
+
+func (a *Certificate) getTemplateData() (interface{}, error) {
+	ignore1, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(1*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore3, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(3*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore7, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &certificateEventData{
+		Config:        config,
+		Certificate:   a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+		IgnoreLink1:   ignore1,
+		IgnoreLink3:   ignore3,
+		IgnoreLink7:   ignore7,
+	}, nil
+}
+
+const reapableCertificateEventHTML = `
+<html>
+<body>
+	<p>Certificate <a href="{{ .Certificate.AWSConsoleURL }}">{{ .Certificate.Name }} in {{.Certificate.Region}}</a> is scheduled to be deleted.</p>
+
+	<p>
+		Its status is {{ .Certificate.Status }}{{ if .Certificate.NotAfter }} and it {{ if .Certificate.Expired }}expired{{ else }}expires{{ end }} on {{ .Certificate.NotAfter.UTC.Format "Jan 2, 2006" }}{{ end }}. {{ if .Certificate.InUse }}It is used by {{ len .Certificate.InUseBy }} resources.{{ else }}It is not used by any resource.{{ end }}
+	</p>
+
+	<p>
+		You can ignore this message and your Certificate will advance to the next state after <strong>{{.Certificate.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>. If you do not take action it will be deleted!
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+			<li><a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a></li>
+			<li><a href="{{ .IgnoreLink3 }}">Ignore it for 3 more days</a></li>
+			<li><a href="{{ .IgnoreLink7}}">Ignore it for 7 more days</a></li>
+		</ul>
+	</p>
+
+	<p>
+		If you want the Reaper to ignore this Certificate tag it with {{ .Config.WhitelistTag }} with any value, or click <a href="{{ .WhitelistLink }}">here</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableCertificateEventHTMLShort = `
+<html>
+<body>
+	<p>Certificate <a href="{{ .Certificate.AWSConsoleURL }}">{{ .Certificate.Name }}</a> ({{ .Certificate.Status }}) in {{.Certificate.Region}} is scheduled to be deleted after <strong>{{.Certificate.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a>,
+		<a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a>,
+		<a href="{{ .IgnoreLink3 }}">3 days</a>,
+		<a href="{{ .IgnoreLink7}}"> 7 days</a>, or
+		<a href="{{ .WhitelistLink }}">Whitelist</a> it.
+	</p>
+</body>
+</html>
+`
+
+const reapableCertificateEventTextShort = `%%%
+Certificate [{{.Certificate.Name}}]({{.Certificate.AWSConsoleURL}}) ({{.Certificate.Status}}) in region: [{{.Certificate.Region}}](https://{{.Certificate.Region}}.console.aws.amazon.com/acm/home?region={{.Certificate.Region}}).{{if .Certificate.Owned}} Owned by {{.Certificate.Owner}}.{{end}}\n
+[Whitelist]({{ .WhitelistLink }}) or [Delete]({{ .TerminateLink }}) this Certificate.
+%%%`
+
+const reapableCertificateEventText = `%%%
+Reaper has discovered a Certificate qualified as reapable: [{{.Certificate.Name}}]({{.Certificate.AWSConsoleURL}}) in region: [{{.Certificate.Region}}](https://{{.Certificate.Region}}.console.aws.amazon.com/acm/home?region={{.Certificate.Region}}).\n
+{{if .Certificate.Owned}}Owned by {{.Certificate.Owner}}.\n{{end}}
+Status: {{.Certificate.Status}}.{{if .Certificate.NotAfter}} Expiry: {{.Certificate.NotAfter.UTC.Format "Jan 2, 2006"}}.{{end}}\n
+ARN: {{.Certificate.ID}}\n
+{{ if .Certificate.AWSConsoleURL}}{{.Certificate.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.Certificate.AWSConsoleURL}})\n
+[Whitelist]({{ .WhitelistLink }}) this Certificate.
+[Delete]({{ .TerminateLink }}) this Certificate.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *Certificate) Save(s *state.State) (bool, error) {
+	log.Info("Saving %s", a.ReapableDescriptionTiny())
+	return tagCertificate(a.Region(), a.ID(), reaperTag, s.RestrictedString())
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *Certificate) Unsave() (bool, error) {
+	log.Info("Unsaving %s", a.ReapableDescriptionTiny())
+	return untagCertificate(a.Region(), a.ID(), reaperTag)
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+func (a *Certificate) Whitelist() (bool, error) {
+	log.Info("Whitelisting Certificate %s", a.ReapableDescriptionTiny())
+	return tagCertificate(a.Region(), a.ID(), config.WhitelistTag, "true")
+}
+
+func untagCertificate(region reapable.Region, arn reapable.ID, key string) (bool, error) {
+	api := acm.New(sess, aws.NewConfig().WithRegion(region.String()))
+	_, err := api.RemoveTagsFromCertificate(&acm.RemoveTagsFromCertificateInput{
+		CertificateArn: aws.String(arn.String()),
+		Tags: []*acm.Tag{
+			&acm.Tag{
+				Key: aws.String(key),
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func tagCertificate(region reapable.Region, arn reapable.ID, key, value string) (bool, error) {
+	api := acm.New(sess, aws.NewConfig().WithRegion(region.String()))
+	_, err := api.AddTagsToCertificate(&acm.AddTagsToCertificateInput{
+		CertificateArn: aws.String(arn.String()),
+		Tags: []*acm.Tag{
+			&acm.Tag{
+				Key:   aws.String(key),
+				Value: aws.String(value),
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *Certificate) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "Status":
+		// one of:
+		// PENDING_VALIDATION
+		// ISSUED
+		// INACTIVE
+		// EXPIRED
+		// VALIDATION_TIMED_OUT
+		// REVOKED
+		// FAILED
+		for _, status := range filter.Arguments {
+			if a.Status != nil && *a.Status == status {
+				matched = true
+			}
+		}
+	case "NotStatus":
+		if a.Status != nil && *a.Status != filter.Arguments[0] {
+			matched = true
+		}
+	case "InUse":
+		if b, err := filter.BoolValue(0); err == nil && a.InUse() == b {
+			matched = true
+		}
+	case "Expired":
+		if b, err := filter.BoolValue(0); err == nil && a.Expired() == b {
+			matched = true
+		}
+	case "ExpiresInTheNext":
+		// expired certificates also expire in the next d
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.NotAfter != nil && a.NotAfter.Before(time.Now().Add(d)) {
+			matched = true
+		}
+	case "ExpiredMoreThan":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.NotAfter != nil && time.Since(*a.NotAfter) > d {
+			matched = true
+		}
+	case "CreatedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CreatedAt != nil && time.Since(*a.CreatedAt) < d {
+			matched = true
+		}
+	case "CreatedNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CreatedAt != nil && time.Since(*a.CreatedAt) > d {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering Certificates.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *Certificate) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/acm/home?region=%s#/?id=%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+func (a *Certificate) Terminate() (bool, error) {
+	log.Info("Terminating Certificate %s", a.ReapableDescriptionTiny())
+	api := acm.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.DeleteCertificate(&acm.DeleteCertificateInput{
+		CertificateArn: aws.String(a.ID().String()),
+	})
+	if err != nil {
+		log.Error("could not delete Certificate %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because certificates cannot be stopped
+func (a *Certificate) Stop() (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,58 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/acm"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestCertificateFilter(t *testing.T) {
+	a := NewCertificate("us-west-2", &acm.CertificateDetail{
+		CertificateArn: aws.String("arn:aws:acm:us-west-2:123456789012:certificate/1234"),
+		DomainName:     aws.String("example.com"),
+		Status:         aws.String("EXPIRED"),
+		NotAfter:       aws.Time(time.Now().Add(-48 * time.Hour)),
+		CreatedAt:      aws.Time(time.Now().Add(-400 * 24 * time.Hour)),
+	}, []*acm.Tag{
+		{Key: aws.String("Owner"), Value: aws.String("bob")},
+		{Key: aws.String("NoValue")},
+	})
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"Status", []string{"ISSUED", "EXPIRED"}, true},
+		{"Status", []string{"ISSUED"}, false},
+		{"NotStatus", []string{"ISSUED"}, true},
+		{"InUse", []string{"false"}, true},
+		{"Expired", []string{"true"}, true},
+		// expired certificates also expire in the next d
+		{"ExpiresInTheNext", []string{"24h"}, true},
+		{"ExpiredMoreThan", []string{"24h"}, true},
+		{"ExpiredMoreThan", []string{"72h"}, false},
+		{"CreatedInTheLast", []string{"24h"}, false},
+		{"CreatedNotInTheLast", []string{"24h"}, true},
+		{"Named", []string{"example.com"}, true},
+		{"Tagged", []string{"NoValue"}, true},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+
+	inUse := NewCertificate("us-west-2", &acm.CertificateDetail{
+		CertificateArn: aws.String("arn:aws:acm:us-west-2:123456789012:certificate/5678"),
+		DomainName:     aws.String("example.org"),
+		InUseBy:        []*string{aws.String("arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/web")},
+	}, nil)
+	if !inUse.Filter(*filters.NewFilter("IsDependency", []string{"true"})) {
+		t.Error("a Certificate in use is not a dependency")
+	}
+}
@@ -0,0 +1,433 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// minimumCapacityUnits is the lowest provisioned capacity of a DynamoDB table or index
+const minimumCapacityUnits = 1
+
+// DynamoDBTable is a Reapable, Filterable
+// embeds AWS API's dynamodb.TableDescription
+type DynamoDBTable struct {
+	Resource
+	dynamodb.TableDescription
+
+	// consumed capacity sums from CloudWatch, by duration
+	consumedCapacity map[time.Duration]float64
+}
+
+// NewDynamoDBTable creates a DynamoDBTable from the AWS API's dynamodb.TableDescription
+// tables cannot be tagged, so they always start in the initial state
+func NewDynamoDBTable(region string, table *dynamodb.TableDescription) *DynamoDBTable {
+	a := DynamoDBTable{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*table.TableName),
+			Name:   *table.TableName,
+			Tags:   make(map[string]string),
+		},
+		TableDescription: *table,
+		consumedCapacity: make(map[time.Duration]float64),
+	}
+
+	// initial state
+	a.reaperState = state.NewState()
+
+	return &a
+}
+
+// ReadCapacityUnits returns the DynamoDBTable's provisioned read capacity
+func (a *DynamoDBTable) ReadCapacityUnits() int64 {
+	if a.ProvisionedThroughput == nil || a.ProvisionedThroughput.ReadCapacityUnits == nil {
+		return 0
+	}
+	return *a.ProvisionedThroughput.ReadCapacityUnits
+}
+
+// WriteCapacityUnits returns the DynamoDBTable's provisioned write capacity
+func (a *DynamoDBTable) WriteCapacityUnits() int64 {
+	if a.ProvisionedThroughput == nil || a.ProvisionedThroughput.WriteCapacityUnits == nil {
+		return 0
+	}
+	return *a.ProvisionedThroughput.WriteCapacityUnits
+}
+
+// ConsumedCapacity returns the read and write capacity units the DynamoDBTable consumed in the last d
+// from the AWS/DynamoDB metrics in CloudWatch
+func (a *DynamoDBTable) ConsumedCapacity(d time.Duration) (float64, error) {
+	if sum, ok := a.consumedCapacity[d]; ok {
+		return sum, nil
+	}
+
+	var sum float64
+	for _, metric := range []string{"ConsumedReadCapacityUnits", "ConsumedWriteCapacityUnits"} {
+		s, err := metricSum(a.Region(), "AWS/DynamoDB", metric, "TableName", a.ID().String(), d)
+		if err != nil {
+			return 0, err
+		}
+		sum += s
+	}
+	a.consumedCapacity[d] = sum
+	return sum, nil
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *DynamoDBTable) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableDynamoDBTableEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *DynamoDBTable) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableDynamoDBTableEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *DynamoDBTable) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableDynamoDBTableEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *DynamoDBTable) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableDynamoDBTableEventHTMLShort)
+	return
+}
+
+type dynamoDBTableEventData struct {
+	Config        *Config
+	DynamoDBTable *DynamoDBTable
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+}
+
+func (a *DynamoDBTable) getTemplateData() (interface{}, error) {
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamoDBTableEventData{
+		Config:        config,
+		DynamoDBTable: a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+	}, nil
+}
+
+const reapableDynamoDBTableEventHTML = `
+<html>
+<body>
+	<p>DynamoDBTable <a href="{{ .DynamoDBTable.AWSConsoleURL }}">{{ .DynamoDBTable.ID }} in {{.DynamoDBTable.Region}}</a> qualifies as reapable.</p>
+
+	<p>
+		It holds {{ .DynamoDBTable.ItemCount }} items ({{ .DynamoDBTable.TableSizeBytes }} bytes) with {{ .DynamoDBTable.ReadCapacityUnits }} read and {{ .DynamoDBTable.WriteCapacityUnits }} write capacity units provisioned.
+	</p>
+
+	<p>
+		DynamoDB tables cannot be tagged, so the Reaper cannot keep track of this DynamoDBTable: you will be notified again on every run, and it will not be deleted unless you delete it below.
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+			<li><a href="{{ .StopLink }}">Scale it down to the minimum capacity</a></li>
+		</ul>
+	</p>
+</body>
+</html>
+`
+
+const reapableDynamoDBTableEventHTMLShort = `
+<html>
+<body>
+	<p>DynamoDBTable <a href="{{ .DynamoDBTable.AWSConsoleURL }}">{{ .DynamoDBTable.ID }}</a> in {{.DynamoDBTable.Region}} qualifies as reapable, and will not be deleted unless you delete it.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a> or
+		<a href="{{ .StopLink }}">Scale down</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableDynamoDBTableEventTextShort = `%%%
+DynamoDBTable [{{.DynamoDBTable.ID}}]({{.DynamoDBTable.AWSConsoleURL}}) in region: [{{.DynamoDBTable.Region}}](https://{{.DynamoDBTable.Region}}.console.aws.amazon.com/dynamodb/home?region={{.DynamoDBTable.Region}}).{{if .DynamoDBTable.Owned}} Owned by {{.DynamoDBTable.Owner}}.{{end}}\n
+[Scale down]({{ .StopLink }}) or [Delete]({{ .TerminateLink }}) this DynamoDBTable.
+%%%`
+
+const reapableDynamoDBTableEventText = `%%%
+Reaper has discovered a DynamoDBTable qualified as reapable: [{{.DynamoDBTable.ID}}]({{.DynamoDBTable.AWSConsoleURL}}) in region: [{{.DynamoDBTable.Region}}](https://{{.DynamoDBTable.Region}}.console.aws.amazon.com/dynamodb/home?region={{.DynamoDBTable.Region}}).\n
+{{if .DynamoDBTable.Owned}}Owned by {{.DynamoDBTable.Owner}}.\n{{end}}
+{{.DynamoDBTable.ItemCount}} items, {{.DynamoDBTable.TableSizeBytes}} bytes, {{.DynamoDBTable.ReadCapacityUnits}} read and {{.DynamoDBTable.WriteCapacityUnits}} write capacity units provisioned.\n
+{{ if .DynamoDBTable.AWSConsoleURL}}{{.DynamoDBTable.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.DynamoDBTable.AWSConsoleURL}})\n
+[Scale down]({{ .StopLink }}) this DynamoDBTable.
+[Delete]({{ .TerminateLink }}) this DynamoDBTable.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because DynamoDB tables cannot be tagged
+func (a *DynamoDBTable) Save(s *state.State) (bool, error) {
+	return false, nil
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because DynamoDB tables cannot be tagged
+func (a *DynamoDBTable) Unsave() (bool, error) {
+	return false, nil
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+// no op because DynamoDB tables cannot be tagged
+func (a *DynamoDBTable) Whitelist() (bool, error) {
+	return false, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *DynamoDBTable) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "TableStatus":
+		if a.TableStatus != nil && *a.TableStatus == filter.Arguments[0] {
+			matched = true
+		}
+	case "ReadCapacityGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.ReadCapacityUnits() > i {
+			matched = true
+		}
+	case "ReadCapacityLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.ReadCapacityUnits() < i {
+			matched = true
+		}
+	case "WriteCapacityGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.WriteCapacityUnits() > i {
+			matched = true
+		}
+	case "WriteCapacityLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.WriteCapacityUnits() < i {
+			matched = true
+		}
+	case "ItemCountGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.ItemCount != nil && *a.ItemCount > i {
+			matched = true
+		}
+	case "ItemCountLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.ItemCount != nil && *a.ItemCount < i {
+			matched = true
+		}
+	case "TableSizeGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.TableSizeBytes != nil && *a.TableSizeBytes > i {
+			matched = true
+		}
+	case "TableSizeLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.TableSizeBytes != nil && *a.TableSizeBytes < i {
+			matched = true
+		}
+	case "CreatedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CreationDateTime != nil && time.Since(*a.CreationDateTime) < d {
+			matched = true
+		}
+	case "CreatedNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CreationDateTime != nil && time.Since(*a.CreationDateTime) > d {
+			matched = true
+		}
+	case "ConsumedCapacityInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err != nil {
+			break
+		}
+		consumed, err := a.ConsumedCapacity(d)
+		if err != nil {
+			log.Error("Error getting consumed capacity of DynamoDBTable %s: %s", a.ReapableDescriptionTiny(), err.Error())
+		} else if consumed > 0 {
+			matched = true
+		}
+	case "NoConsumedCapacityInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err != nil {
+			break
+		}
+		consumed, err := a.ConsumedCapacity(d)
+		if err != nil {
+			log.Error("Error getting consumed capacity of DynamoDBTable %s: %s", a.ReapableDescriptionTiny(), err.Error())
+		} else if consumed == 0 {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering DynamoDBTables.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *DynamoDBTable) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/dynamodb/home?region=%s#tables:selected=%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+func (a *DynamoDBTable) Terminate() (bool, error) {
+	log.Info("Terminating DynamoDBTable %s", a.ReapableDescriptionTiny())
+	api := dynamodb.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.DeleteTable(&dynamodb.DeleteTableInput{
+		TableName: aws.String(a.ID().String()),
+	})
+	if err != nil {
+		log.Error("could not delete DynamoDBTable %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// scales the table and its global secondary indexes down to the minimum provisioned capacity
+func (a *DynamoDBTable) Stop() (bool, error) {
+	log.Info("Stopping DynamoDBTable %s", a.ReapableDescriptionTiny())
+	minimum := &dynamodb.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(minimumCapacityUnits),
+		WriteCapacityUnits: aws.Int64(minimumCapacityUnits),
+	}
+	input := &dynamodb.UpdateTableInput{
+		TableName: aws.String(a.ID().String()),
+	}
+
+	// DynamoDB rejects updates that do not change the provisioned capacity
+	if !atMinimumCapacity(a.ProvisionedThroughput) {
+		input.ProvisionedThroughput = minimum
+	}
+	for _, index := range a.GlobalSecondaryIndexes {
+		if !atMinimumCapacity(index.ProvisionedThroughput) {
+			input.GlobalSecondaryIndexUpdates = append(input.GlobalSecondaryIndexUpdates, &dynamodb.GlobalSecondaryIndexUpdate{
+				Update: &dynamodb.UpdateGlobalSecondaryIndexAction{
+					IndexName:             index.IndexName,
+					ProvisionedThroughput: minimum,
+				},
+			})
+		}
+	}
+	if input.ProvisionedThroughput == nil && len(input.GlobalSecondaryIndexUpdates) == 0 {
+		log.Info("DynamoDBTable %s is already at the minimum capacity", a.ReapableDescriptionTiny())
+		return false, nil
+	}
+
+	api := dynamodb.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.UpdateTable(input)
+	if err != nil {
+		log.Error("could not stop DynamoDBTable %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// atMinimumCapacity returns whether a table or index's provisioned capacity cannot be lowered
+func atMinimumCapacity(throughput *dynamodb.ProvisionedThroughputDescription) bool {
+	return throughput == nil ||
+		(throughput.ReadCapacityUnits != nil && *throughput.ReadCapacityUnits <= minimumCapacityUnits &&
+			throughput.WriteCapacityUnits != nil && *throughput.WriteCapacityUnits <= minimumCapacityUnits)
+}
@@ -0,0 +1,151 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestDynamoDBTableFilter(t *testing.T) {
+	a := NewDynamoDBTable("us-west-2", &dynamodb.TableDescription{
+		TableName:   aws.String("sessions"),
+		TableStatus: aws.String("ACTIVE"),
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughputDescription{
+			ReadCapacityUnits:  aws.Int64(10),
+			WriteCapacityUnits: aws.Int64(5),
+		},
+		ItemCount:        aws.Int64(100),
+		TableSizeBytes:   aws.Int64(4096),
+		CreationDateTime: aws.Time(time.Now().Add(-48 * time.Hour)),
+	})
+	// avoids getting consumed capacity from CloudWatch
+	a.consumedCapacity[24*time.Hour] = 0
+	a.consumedCapacity[72*time.Hour] = 12
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"TableStatus", []string{"ACTIVE"}, true},
+		{"TableStatus", []string{"DELETING"}, false},
+		{"ReadCapacityGreaterThan", []string{"5"}, true},
+		{"ReadCapacityLessThan", []string{"10"}, false},
+		{"WriteCapacityGreaterThan", []string{"5"}, false},
+		{"WriteCapacityLessThan", []string{"6"}, true},
+		{"ItemCountGreaterThan", []string{"99"}, true},
+		{"TableSizeGreaterThan", []string{"1024"}, true},
+		{"CreatedInTheLast", []string{"24h"}, false},
+		{"CreatedNotInTheLast", []string{"24h"}, true},
+		{"ConsumedCapacityInTheLast", []string{"24h"}, false},
+		{"NoConsumedCapacityInTheLast", []string{"24h"}, true},
+		{"ConsumedCapacityInTheLast", []string{"72h"}, true},
+		// DynamoDB tables cannot be tagged
+		{"Tagged", []string{"Owner"}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
+
+func TestAtMinimumCapacity(t *testing.T) {
+	for _, test := range []struct {
+		throughput *dynamodb.ProvisionedThroughputDescription
+		minimum    bool
+	}{
+		{nil, true},
+		{&dynamodb.ProvisionedThroughputDescription{ReadCapacityUnits: aws.Int64(1), WriteCapacityUnits: aws.Int64(1)}, true},
+		{&dynamodb.ProvisionedThroughputDescription{ReadCapacityUnits: aws.Int64(1), WriteCapacityUnits: aws.Int64(5)}, false},
+		{&dynamodb.ProvisionedThroughputDescription{ReadCapacityUnits: aws.Int64(5), WriteCapacityUnits: aws.Int64(1)}, false},
+		// unknown capacity may be lowered
+		{&dynamodb.ProvisionedThroughputDescription{ReadCapacityUnits: aws.Int64(1)}, false},
+	} {
+		if minimum := atMinimumCapacity(test.throughput); minimum != test.minimum {
+			t.Errorf("atMinimumCapacity(%v) = %t, want %t", test.throughput, minimum, test.minimum)
+		}
+	}
+}
+
+func TestDynamoDBTableStop(t *testing.T) {
+	defer func(s *session.Session) { sess = s }(sess)
+
+	minimum := &dynamodb.ProvisionedThroughputDescription{ReadCapacityUnits: aws.Int64(1), WriteCapacityUnits: aws.Int64(1)}
+	provisioned := &dynamodb.ProvisionedThroughputDescription{ReadCapacityUnits: aws.Int64(10), WriteCapacityUnits: aws.Int64(5)}
+
+	for _, test := range []struct {
+		table   *dynamodb.ProvisionedThroughputDescription
+		indexes []*dynamodb.ProvisionedThroughputDescription
+		stopped bool
+		// whether the table's capacity is updated, and which indexes are
+		tableUpdated   bool
+		indexesUpdated []string
+	}{
+		{provisioned, nil, true, true, nil},
+		{provisioned, []*dynamodb.ProvisionedThroughputDescription{minimum, provisioned}, true, true, []string{"index-1"}},
+		// DynamoDB rejects updates that do not change the provisioned capacity
+		{minimum, []*dynamodb.ProvisionedThroughputDescription{provisioned}, true, false, []string{"index-0"}},
+		{minimum, []*dynamodb.ProvisionedThroughputDescription{minimum}, false, false, nil},
+	} {
+		var input *dynamodb.UpdateTableInput
+		var closeServer func()
+		sess, closeServer = testSession(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Amz-Target") != "DynamoDB_20120810.UpdateTable" {
+				w.WriteHeader(http.StatusNotImplemented)
+				return
+			}
+			input = &dynamodb.UpdateTableInput{}
+			json.NewDecoder(r.Body).Decode(input)
+			w.Write([]byte("{}"))
+		})
+
+		description := &dynamodb.TableDescription{
+			TableName:             aws.String("sessions"),
+			ProvisionedThroughput: test.table,
+		}
+		for i, throughput := range test.indexes {
+			description.GlobalSecondaryIndexes = append(description.GlobalSecondaryIndexes, &dynamodb.GlobalSecondaryIndexDescription{
+				IndexName:             aws.String(fmt.Sprintf("index-%d", i)),
+				ProvisionedThroughput: throughput,
+			})
+		}
+		stopped, err := NewDynamoDBTable("us-west-2", description).Stop()
+		closeServer()
+
+		if stopped != test.stopped || err != nil {
+			t.Errorf("%+v: Stop() = %t, %v, want %t", test, stopped, err, test.stopped)
+		}
+		if !test.stopped {
+			if input != nil {
+				t.Errorf("%+v: UpdateTable was called with %v", test, input)
+			}
+			continue
+		}
+		if input == nil || *input.TableName != "sessions" {
+			t.Errorf("%+v: UpdateTable input = %v", test, input)
+			continue
+		}
+		if (input.ProvisionedThroughput != nil) != test.tableUpdated ||
+			(input.ProvisionedThroughput != nil && (*input.ProvisionedThroughput.ReadCapacityUnits != 1 || *input.ProvisionedThroughput.WriteCapacityUnits != 1)) {
+			t.Errorf("%+v: table update = %v", test, input.ProvisionedThroughput)
+		}
+		var indexes []string
+		for _, update := range input.GlobalSecondaryIndexUpdates {
+			indexes = append(indexes, *update.Update.IndexName)
+			if *update.Update.ProvisionedThroughput.ReadCapacityUnits != 1 || *update.Update.ProvisionedThroughput.WriteCapacityUnits != 1 {
+				t.Errorf("%+v: index update = %v", test, update)
+			}
+		}
+		if fmt.Sprint(indexes) != fmt.Sprint(test.indexesUpdated) {
+			t.Errorf("%+v: updated indexes %v, want %v", test, indexes, test.indexesUpdated)
+		}
+	}
+}
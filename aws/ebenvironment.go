@@ -0,0 +1,367 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// EBEnvironment is a Reapable, Filterable
+// embeds AWS API's elasticbeanstalk.EnvironmentDescription
+// its resources are terminated with it, and are not reaped separately
+type EBEnvironment struct {
+	Resource
+	elasticbeanstalk.EnvironmentDescription
+
+	// the resources the environment created
+	EnvironmentResources *elasticbeanstalk.EnvironmentResourceDescription
+}
+
+// NewEBEnvironment creates an EBEnvironment from the AWS API's elasticbeanstalk.EnvironmentDescription
+// Elastic Beanstalk environments cannot be tagged, so they always start in the initial state
+func NewEBEnvironment(region string, environment *elasticbeanstalk.EnvironmentDescription, resources *elasticbeanstalk.EnvironmentResourceDescription) *EBEnvironment {
+	a := EBEnvironment{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*environment.EnvironmentId),
+			Name:   *environment.EnvironmentName,
+			Tags:   make(map[string]string),
+		},
+		EnvironmentDescription: *environment,
+		EnvironmentResources:   resources,
+	}
+
+	// initial state
+	a.reaperState = state.NewState()
+
+	return &a
+}
+
+// StackName returns the name of the Cloudformation stack the EBEnvironment's resources are created by
+func (a *EBEnvironment) StackName() string {
+	return fmt.Sprintf("awseb-%s-stack", a.ID())
+}
+
+// ResourceIDs returns the IDs of the EBEnvironment's AutoScalingGroups, Instances, LaunchConfigurations and LoadBalancers
+func (a *EBEnvironment) ResourceIDs() []reapable.ID {
+	var ids []reapable.ID
+	if a.EnvironmentResources == nil {
+		return ids
+	}
+	for _, group := range a.EnvironmentResources.AutoScalingGroups {
+		ids = append(ids, reapable.ID(*group.Name))
+	}
+	for _, instance := range a.EnvironmentResources.Instances {
+		ids = append(ids, reapable.ID(*instance.Id))
+	}
+	for _, configuration := range a.EnvironmentResources.LaunchConfigurations {
+		ids = append(ids, reapable.ID(*configuration.Name))
+	}
+	for _, loadBalancer := range a.EnvironmentResources.LoadBalancers {
+		ids = append(ids, reapable.ID(*loadBalancer.Name))
+	}
+	return ids
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *EBEnvironment) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableEBEnvironmentEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *EBEnvironment) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableEBEnvironmentEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *EBEnvironment) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableEBEnvironmentEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *EBEnvironment) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableEBEnvironmentEventHTMLShort)
+	return
+}
+
+type eBEnvironmentEventData struct {
+	Config        *Config
+	EBEnvironment *EBEnvironment
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+}
+
+func (a *EBEnvironment) getTemplateData() (interface{}, error) {
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &eBEnvironmentEventData{
+		Config:        config,
+		EBEnvironment: a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+	}, nil
+}
+
+const reapableEBEnvironmentEventHTML = `
+<html>
+<body>
+	<p>EBEnvironment <a href="{{ .EBEnvironment.AWSConsoleURL }}">{{ .EBEnvironment.EnvironmentName }} ({{ .EBEnvironment.ID }}) in {{.EBEnvironment.Region}}</a> of application {{ .EBEnvironment.ApplicationName }} qualifies as reapable.</p>
+
+	<p>
+		It is {{ .EBEnvironment.Status }} with {{ .EBEnvironment.Health }} health{{ if .EBEnvironment.CNAME }}, served at <strong>{{ .EBEnvironment.CNAME }}</strong>{{ end }}.
+		Its {{ len .EBEnvironment.ResourceIDs }} AutoScalingGroups, Instances, LaunchConfigurations and LoadBalancers, and the rest of its Cloudformation stack, will be terminated with it.
+	</p>
+
+	<p>
+		Elastic Beanstalk environments cannot be tagged, so the Reaper cannot keep track of this EBEnvironment: you will be notified again on every run, and it will not be terminated unless you terminate it below.
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Terminate it now</a></li>
+		</ul>
+	</p>
+</body>
+</html>
+`
+
+const reapableEBEnvironmentEventHTMLShort = `
+<html>
+<body>
+	<p>EBEnvironment <a href="{{ .EBEnvironment.AWSConsoleURL }}">{{ .EBEnvironment.EnvironmentName }}</a> of {{ .EBEnvironment.ApplicationName }} in {{.EBEnvironment.Region}} qualifies as reapable, and will not be terminated unless you terminate it.
+		<br />
+		<a href="{{ .TerminateLink }}">Terminate</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableEBEnvironmentEventTextShort = `%%%
+EBEnvironment [{{.EBEnvironment.EnvironmentName}}]({{.EBEnvironment.AWSConsoleURL}}) of {{.EBEnvironment.ApplicationName}} in region: [{{.EBEnvironment.Region}}](https://{{.EBEnvironment.Region}}.console.aws.amazon.com/elasticbeanstalk/home?region={{.EBEnvironment.Region}}).{{if .EBEnvironment.Owned}} Owned by {{.EBEnvironment.Owner}}.{{end}}\n
+[Terminate]({{ .TerminateLink }}) this EBEnvironment.
+%%%`
+
+const reapableEBEnvironmentEventText = `%%%
+Reaper has discovered an EBEnvironment qualified as reapable: [{{.EBEnvironment.EnvironmentName}}]({{.EBEnvironment.AWSConsoleURL}}) ({{.EBEnvironment.ID}}) in region: [{{.EBEnvironment.Region}}](https://{{.EBEnvironment.Region}}.console.aws.amazon.com/elasticbeanstalk/home?region={{.EBEnvironment.Region}}).\n
+{{if .EBEnvironment.Owned}}Owned by {{.EBEnvironment.Owner}}.\n{{end}}
+Application: {{.EBEnvironment.ApplicationName}}, status: {{.EBEnvironment.Status}}, health: {{.EBEnvironment.Health}}, resources: {{len .EBEnvironment.ResourceIDs}}.\n
+{{ if .EBEnvironment.AWSConsoleURL}}{{.EBEnvironment.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.EBEnvironment.AWSConsoleURL}})\n
+[Terminate]({{ .TerminateLink }}) this EBEnvironment.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because Elastic Beanstalk environments cannot be tagged
+func (a *EBEnvironment) Save(s *state.State) (bool, error) {
+	return false, nil
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because Elastic Beanstalk environments cannot be tagged
+func (a *EBEnvironment) Unsave() (bool, error) {
+	return false, nil
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+// no op because Elastic Beanstalk environments cannot be tagged
+func (a *EBEnvironment) Whitelist() (bool, error) {
+	return false, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *EBEnvironment) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "Health":
+		// one of:
+		// Green
+		// Yellow
+		// Red
+		// Grey
+		if a.Health != nil && *a.Health == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotHealth":
+		if a.Health != nil && *a.Health != filter.Arguments[0] {
+			matched = true
+		}
+	case "Status":
+		// one of:
+		// Launching
+		// Updating
+		// Ready
+		// Terminating
+		if a.Status != nil && *a.Status == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotStatus":
+		if a.Status != nil && *a.Status != filter.Arguments[0] {
+			matched = true
+		}
+	case "Application":
+		if a.ApplicationName != nil && *a.ApplicationName == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotApplication":
+		if a.ApplicationName != nil && *a.ApplicationName != filter.Arguments[0] {
+			matched = true
+		}
+	case "CreatedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.DateCreated != nil && time.Since(*a.DateCreated) < d {
+			matched = true
+		}
+	case "CreatedNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.DateCreated != nil && time.Since(*a.DateCreated) > d {
+			matched = true
+		}
+	case "UpdatedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.DateUpdated != nil && time.Since(*a.DateUpdated) < d {
+			matched = true
+		}
+	case "UpdatedNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.DateUpdated != nil && time.Since(*a.DateUpdated) > d {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Resource.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Resource.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Resource.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Resource.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering EBEnvironments.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *EBEnvironment) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/elasticbeanstalk/home?region=%s#/environment/dashboard?applicationName=%s&environmentId=%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(*a.ApplicationName), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+// terminates the EBEnvironment's resources with it
+func (a *EBEnvironment) Terminate() (bool, error) {
+	log.Info("Terminating EBEnvironment %s", a.ReapableDescriptionTiny())
+	api := elasticbeanstalk.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.TerminateEnvironment(&elasticbeanstalk.TerminateEnvironmentInput{
+		EnvironmentId:      aws.String(a.ID().String()),
+		TerminateResources: aws.Bool(true),
+	})
+	if err != nil {
+		log.Error("could not terminate EBEnvironment %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because Elastic Beanstalk environments cannot be stopped
+func (a *EBEnvironment) Stop() (bool, error) {
+	return false, nil
+}
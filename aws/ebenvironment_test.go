@@ -0,0 +1,47 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestEBEnvironmentFilter(t *testing.T) {
+	a := NewEBEnvironment("us-west-2", &elasticbeanstalk.EnvironmentDescription{
+		EnvironmentId:   aws.String("e-1234"),
+		EnvironmentName: aws.String("web-staging"),
+		ApplicationName: aws.String("web"),
+		Health:          aws.String("Grey"),
+		Status:          aws.String("Ready"),
+		DateCreated:     aws.Time(time.Now().Add(-72 * time.Hour)),
+		DateUpdated:     aws.Time(time.Now().Add(-time.Hour)),
+	}, nil)
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"Health", []string{"Grey"}, true},
+		{"NotHealth", []string{"Grey"}, false},
+		{"Status", []string{"Ready"}, true},
+		{"NotStatus", []string{"Terminating"}, true},
+		{"Application", []string{"web"}, true},
+		{"NotApplication", []string{"web"}, false},
+		{"CreatedInTheLast", []string{"24h"}, false},
+		{"CreatedNotInTheLast", []string{"24h"}, true},
+		{"UpdatedInTheLast", []string{"24h"}, true},
+		{"UpdatedNotInTheLast", []string{"24h"}, false},
+		// named by its name, identified by its id
+		{"Named", []string{"web-staging"}, true},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
@@ -0,0 +1,484 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecs"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// at most 100 images are deleted or described at a time
+const ecrImageBatchSize = 100
+
+// ECRImage is a Filterable
+// an image in an ECR repository, identified by its repository and digest
+// because the same digest can be pushed to several repositories
+// images are notified about and deleted per repository, by their ECRRepository
+type ECRImage struct {
+	Resource
+
+	Repository string
+	Digest     string
+	ImageTags  []string
+
+	// when the image was pushed
+	PushedAt *time.Time
+
+	// an active ECS task definition references the image
+	InTaskDefinition bool
+}
+
+// NewECRImage creates an ECRImage from an image's digest and tags
+func NewECRImage(region, repository, digest string, imageTags []string, pushedAt *time.Time, inTaskDefinition bool) *ECRImage {
+	return &ECRImage{
+		Resource: Resource{
+			region:      reapable.Region(region),
+			id:          reapable.ID(fmt.Sprintf("%s@%s", repository, digest)),
+			Name:        fmt.Sprintf("%s@%s", repository, digest),
+			Tags:        make(map[string]string),
+			reaperState: state.NewState(),
+		},
+		Repository:       repository,
+		Digest:           digest,
+		ImageTags:        imageTags,
+		PushedAt:         pushedAt,
+		InTaskDefinition: inTaskDefinition,
+	}
+}
+
+// Untagged returns whether the ECRImage has no image tags
+func (a *ECRImage) Untagged() bool {
+	return len(a.ImageTags) == 0
+}
+
+// imageTagMatches returns whether any of the ECRImage's image tags matches the pattern
+func (a *ECRImage) imageTagMatches(pattern string) (bool, error) {
+	for _, tag := range a.ImageTags {
+		m, err := regexp.MatchString(pattern, tag)
+		if err != nil {
+			return false, err
+		}
+		if m {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *ECRImage) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "Untagged":
+		if b, err := filter.BoolValue(0); err == nil && a.Untagged() == b {
+			matched = true
+		}
+	case "ImageTagMatches":
+		if m, err := a.imageTagMatches(filter.Arguments[0]); err == nil && m {
+			matched = true
+		}
+	case "NotImageTagMatches":
+		// untagged images have no tag matching the pattern
+		if m, err := a.imageTagMatches(filter.Arguments[0]); err == nil && !m {
+			matched = true
+		}
+	case "InTaskDefinition":
+		if b, err := filter.BoolValue(0); err == nil && a.InTaskDefinition == b {
+			matched = true
+		}
+	case "PushedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.PushedAt != nil && time.Since(*a.PushedAt) < d {
+			matched = true
+		}
+	case "PushedNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.PushedAt != nil && time.Since(*a.PushedAt) > d {
+			matched = true
+		}
+	case "Repository":
+		if a.Repository == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotRepository":
+		if a.Repository != filter.Arguments[0] {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering ECRImages.", filter.Function)
+	}
+	return matched
+}
+
+type ecrImageDetail struct {
+	_ struct{} `type:"structure"`
+
+	ImageDigest   *string    `locationName:"imageDigest" type:"string"`
+	ImagePushedAt *time.Time `locationName:"imagePushedAt" type:"timestamp" timestampFormat:"unix"`
+	ImageTags     []*string  `locationName:"imageTags" type:"list"`
+}
+
+type describeImagesOutput struct {
+	_ struct{} `type:"structure"`
+
+	ImageDetails []*ecrImageDetail `locationName:"imageDetails" type:"list"`
+	NextToken    *string           `locationName:"nextToken" type:"string"`
+}
+
+// ecrRequest sends an ECR operation that the vendored client does not have
+func ecrRequest(api *ecr.ECR, operation string, input, output interface{}) error {
+	return api.NewRequest(&request.Operation{
+		Name:       operation,
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}, input, output).Send()
+}
+
+// ECRRepository is a Reapable, Filterable
+// embeds AWS API's ecr.Repository
+// it is reapable when any of its images matches the ECRImages filter groups
+// and, if there are any, it matches the ECRRepositories filter groups
+// so that a repository's images are notified about together
+type ECRRepository struct {
+	Resource
+	ecr.Repository
+
+	Images []*ECRImage
+
+	// the images matching the ECRImages filter groups, notified about
+	ReapableImages []*ECRImage
+
+	// applies the ECRImages filter groups, set by the reaper
+	// Terminate applies it again before deleting any image
+	ImageFilter func(*ECRImage) bool
+}
+
+// NewECRRepository creates an ECRRepository from the AWS API's ecr.Repository
+// ECR repositories cannot be tagged, so they always start in the initial state
+func NewECRRepository(region string, repository *ecr.Repository, images []*ECRImage) *ECRRepository {
+	a := ECRRepository{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*repository.RepositoryName),
+			Name:   *repository.RepositoryName,
+			Tags:   make(map[string]string),
+		},
+		Repository: *repository,
+		Images:     images,
+	}
+
+	// initial state
+	a.reaperState = state.NewState()
+
+	return &a
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *ECRRepository) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableECRRepositoryEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *ECRRepository) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableECRRepositoryEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *ECRRepository) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableECRRepositoryEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *ECRRepository) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableECRRepositoryEventHTMLShort)
+	return
+}
+
+type eCRRepositoryEventData struct {
+	Config        *Config
+	ECRRepository *ECRRepository
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+}
+
+func (a *ECRRepository) getTemplateData() (interface{}, error) {
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &eCRRepositoryEventData{
+		Config:        config,
+		ECRRepository: a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+	}, nil
+}
+
+const reapableECRRepositoryEventHTML = `
+<html>
+<body>
+	<p>{{ len .ECRRepository.ReapableImages }} of the {{ len .ECRRepository.Images }} images in ECRRepository <a href="{{ .ECRRepository.AWSConsoleURL }}">{{ .ECRRepository.ID }} in {{.ECRRepository.Region}}</a> qualify as reapable.</p>
+
+	<p>
+		<ul>
+		{{ range .ECRRepository.ReapableImages }}
+			<li>{{ .Digest }}{{ if .ImageTags }} tagged {{ .ImageTags }}{{ else }} (untagged){{ end }}{{ if .PushedAt }}, pushed {{ .PushedAt.UTC.Format "Jan 2, 2006" }}{{ end }}</li>
+		{{ end }}
+		</ul>
+	</p>
+
+	<p>
+		ECR repositories cannot be tagged, so the Reaper cannot keep track of these images: you will be notified again on every run, and they will not be deleted unless you delete them below.
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete them now</a></li>
+		</ul>
+	</p>
+</body>
+</html>
+`
+
+const reapableECRRepositoryEventHTMLShort = `
+<html>
+<body>
+	<p>{{ len .ECRRepository.ReapableImages }} images in ECRRepository <a href="{{ .ECRRepository.AWSConsoleURL }}">{{ .ECRRepository.ID }}</a> in {{.ECRRepository.Region}} qualify as reapable, and will not be deleted unless you delete them.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableECRRepositoryEventTextShort = `%%%
+ECRRepository [{{.ECRRepository.ID}}]({{.ECRRepository.AWSConsoleURL}}) in region: [{{.ECRRepository.Region}}](https://{{.ECRRepository.Region}}.console.aws.amazon.com/ecs/home?region={{.ECRRepository.Region}}#/repositories).{{if .ECRRepository.Owned}} Owned by {{.ECRRepository.Owner}}.{{end}}\n
+{{len .ECRRepository.ReapableImages}} of {{len .ECRRepository.Images}} images are reapable.\n
+[Delete]({{ .TerminateLink }}) these images.
+%%%`
+
+const reapableECRRepositoryEventText = `%%%
+Reaper has discovered images qualified as reapable in ECRRepository: [{{.ECRRepository.ID}}]({{.ECRRepository.AWSConsoleURL}}) in region: [{{.ECRRepository.Region}}](https://{{.ECRRepository.Region}}.console.aws.amazon.com/ecs/home?region={{.ECRRepository.Region}}#/repositories).\n
+{{if .ECRRepository.Owned}}Owned by {{.ECRRepository.Owner}}.\n{{end}}
+{{len .ECRRepository.ReapableImages}} of {{len .ECRRepository.Images}} images are reapable.\n
+{{ if .ECRRepository.AWSConsoleURL}}{{.ECRRepository.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.ECRRepository.AWSConsoleURL}})\n
+[Delete]({{ .TerminateLink }}) these images.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because ECR repositories cannot be tagged
+func (a *ECRRepository) Save(s *state.State) (bool, error) {
+	return false, nil
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because ECR repositories cannot be tagged
+func (a *ECRRepository) Unsave() (bool, error) {
+	return false, nil
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+// no op because ECR repositories cannot be tagged
+func (a *ECRRepository) Whitelist() (bool, error) {
+	return false, nil
+}
+
+// Filter is part of the filter.Filterable interface
+// ECRRepositories are also matched through their images, see ECRImage.Filter
+func (a *ECRRepository) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering ECRRepositories.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *ECRRepository) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/ecs/home?region=%s#/repositories/%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+// deletes the ECRRepository's reapable images, not the repository
+// images may have been tagged or referenced by a task definition since they were notified about,
+// so only those that still match the ECRImages filter groups are deleted
+func (a *ECRRepository) Terminate() (bool, error) {
+	api := ecr.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	referenced, err := ecsTaskDefinitionImages(ecs.New(sess, aws.NewConfig().WithRegion(a.Region().String())))
+	if err != nil {
+		log.Error("could not list the images of ECS task definitions for ECRRepository %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	images, err := ecrImages(api, a.Region().String(), &a.Repository, referenced)
+	if err != nil {
+		log.Error("could not describe images of ECRRepository %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+
+	notified := make(map[string]bool)
+	for _, image := range a.ReapableImages {
+		notified[image.Digest] = true
+	}
+	var imageIDs []*ecr.ImageIdentifier
+	for _, image := range images {
+		if notified[image.Digest] && a.ImageFilter != nil && a.ImageFilter(image) {
+			// deleting by digest deletes all of the image's tags
+			imageIDs = append(imageIDs, &ecr.ImageIdentifier{ImageDigest: aws.String(image.Digest)})
+		}
+	}
+	log.Info("Terminating %d of %d notified images of ECRRepository %s", len(imageIDs), len(a.ReapableImages), a.ReapableDescriptionTiny())
+
+	failures := 0
+	for i := 0; i < len(imageIDs); i += ecrImageBatchSize {
+		end := i + ecrImageBatchSize
+		if end > len(imageIDs) {
+			end = len(imageIDs)
+		}
+		resp, err := api.BatchDeleteImage(&ecr.BatchDeleteImageInput{
+			RegistryId:     a.RegistryId,
+			RepositoryName: aws.String(a.ID().String()),
+			ImageIds:       imageIDs[i:end],
+		})
+		if err != nil {
+			log.Error("could not delete images of ECRRepository %s", a.ReapableDescriptionTiny())
+			return false, err
+		}
+		for _, failure := range resp.Failures {
+			log.Error("could not delete image %s of ECRRepository %s: %s", *failure.ImageId.ImageDigest, a.ReapableDescriptionTiny(), *failure.FailureReason)
+			failures++
+		}
+	}
+	if failures > 0 {
+		return false, fmt.Errorf("%d of %d images of ECRRepository %s could not be deleted", failures, len(imageIDs), a.ID())
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because images cannot be stopped
+func (a *ECRRepository) Stop() (bool, error) {
+	return false, nil
+}
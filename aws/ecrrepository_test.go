@@ -0,0 +1,90 @@
+package aws
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/private/protocol/json/jsonutil"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestDescribeImagesOutput(t *testing.T) {
+	output := &describeImagesOutput{}
+	err := jsonutil.UnmarshalJSON(output, strings.NewReader(`{
+		"imageDetails": [{
+			"registryId": "123456789012",
+			"repositoryName": "web",
+			"imageDigest": "sha256:1234",
+			"imageTags": ["latest", "v1"],
+			"imageSizeInBytes": 1024,
+			"imagePushedAt": 1464793445.0
+		}],
+		"nextToken": "token"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(output.ImageDetails) != 1 {
+		t.Fatalf("%d image details, want 1", len(output.ImageDetails))
+	}
+	detail := output.ImageDetails[0]
+	if *detail.ImageDigest != "sha256:1234" || len(detail.ImageTags) != 2 || *detail.ImageTags[1] != "v1" {
+		t.Errorf("image detail = %+v", detail)
+	}
+	if detail.ImagePushedAt == nil || !detail.ImagePushedAt.Equal(time.Unix(1464793445, 0)) {
+		t.Errorf("ImagePushedAt = %v", detail.ImagePushedAt)
+	}
+	if output.NextToken == nil || *output.NextToken != "token" {
+		t.Errorf("NextToken = %v", output.NextToken)
+	}
+}
+
+func TestECRImageFilter(t *testing.T) {
+	pushed := time.Now().Add(-48 * time.Hour)
+	a := NewECRImage("us-west-2", "web", "sha256:1234", []string{"latest", "v1"}, &pushed, false)
+
+	if a.ID() != "web@sha256:1234" {
+		t.Errorf("ID() = %s, want web@sha256:1234", a.ID())
+	}
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"Untagged", []string{"false"}, true},
+		{"ImageTagMatches", []string{"^v[0-9]+$"}, true},
+		{"ImageTagMatches", []string{"^release-"}, false},
+		{"NotImageTagMatches", []string{"^release-"}, true},
+		{"InTaskDefinition", []string{"false"}, true},
+		{"PushedInTheLast", []string{"24h"}, false},
+		{"PushedNotInTheLast", []string{"24h"}, true},
+		{"Repository", []string{"web"}, true},
+		{"NotRepository", []string{"web"}, false},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+
+	untagged := NewECRImage("us-west-2", "web", "sha256:5678", nil, nil, true)
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"Untagged", []string{"true"}, true},
+		// untagged images have no tag matching the pattern
+		{"NotImageTagMatches", []string{".*"}, true},
+		// unknown push time
+		{"PushedNotInTheLast", []string{"24h"}, false},
+	} {
+		if matched := untagged.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("untagged %s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
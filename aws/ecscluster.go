@@ -0,0 +1,352 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// ECSCluster is a Reapable, Filterable
+// embeds AWS API's ecs.Cluster
+type ECSCluster struct {
+	Resource
+	ecs.Cluster
+
+	// EC2 instances registered as container instances
+	InstanceIDs []reapable.ID
+}
+
+// NewECSCluster creates an ECSCluster from the AWS API's ecs.Cluster
+// clusters cannot be tagged, so they always start in the initial state
+// clusters with services or container instances are dependencies
+func NewECSCluster(region string, cluster *ecs.Cluster, instanceIDs []reapable.ID) *ECSCluster {
+	a := ECSCluster{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*cluster.ClusterName),
+			Name:   *cluster.ClusterName,
+			Tags:   make(map[string]string),
+		},
+		Cluster:     *cluster,
+		InstanceIDs: instanceIDs,
+	}
+
+	if a.ActiveServices() > 0 || a.ContainerInstances() > 0 {
+		a.Dependency = true
+	}
+
+	// initial state
+	a.reaperState = state.NewState()
+
+	return &a
+}
+
+// ActiveServices returns the number of ACTIVE services in the ECSCluster
+func (a *ECSCluster) ActiveServices() int64 {
+	if a.ActiveServicesCount == nil {
+		return 0
+	}
+	return *a.ActiveServicesCount
+}
+
+// ContainerInstances returns the number of container instances registered with the ECSCluster
+func (a *ECSCluster) ContainerInstances() int64 {
+	if a.RegisteredContainerInstancesCount == nil {
+		return 0
+	}
+	return *a.RegisteredContainerInstancesCount
+}
+
+// RunningTasks returns the number of tasks running in the ECSCluster
+func (a *ECSCluster) RunningTasks() int64 {
+	if a.RunningTasksCount == nil {
+		return 0
+	}
+	return *a.RunningTasksCount
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *ECSCluster) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableECSClusterEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *ECSCluster) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableECSClusterEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *ECSCluster) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableECSClusterEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *ECSCluster) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableECSClusterEventHTMLShort)
+	return
+}
+
+type eCSClusterEventData struct {
+	Config        *Config
+	ECSCluster    *ECSCluster
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+}
+
+func (a *ECSCluster) getTemplateData() (interface{}, error) {
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &eCSClusterEventData{
+		Config:        config,
+		ECSCluster:    a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+	}, nil
+}
+
+const reapableECSClusterEventHTML = `
+<html>
+<body>
+	<p>ECSCluster <a href="{{ .ECSCluster.AWSConsoleURL }}">{{ .ECSCluster.ID }} in {{.ECSCluster.Region}}</a> qualifies as reapable.</p>
+
+	<p>
+		It has {{ .ECSCluster.ActiveServices }} active services, {{ .ECSCluster.ContainerInstances }} container instances and {{ .ECSCluster.RunningTasks }} running tasks.
+	</p>
+
+	<p>
+		ECS clusters cannot be tagged, so the Reaper cannot keep track of this ECSCluster: you will be notified again on every run, and it will not be deleted unless you delete it below.
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+		</ul>
+	</p>
+</body>
+</html>
+`
+
+const reapableECSClusterEventHTMLShort = `
+<html>
+<body>
+	<p>ECSCluster <a href="{{ .ECSCluster.AWSConsoleURL }}">{{ .ECSCluster.ID }}</a> in {{.ECSCluster.Region}} qualifies as reapable, and will not be deleted unless you delete it.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableECSClusterEventTextShort = `%%%
+ECSCluster [{{.ECSCluster.ID}}]({{.ECSCluster.AWSConsoleURL}}) in region: [{{.ECSCluster.Region}}](https://{{.ECSCluster.Region}}.console.aws.amazon.com/ecs/home?region={{.ECSCluster.Region}}).{{if .ECSCluster.Owned}} Owned by {{.ECSCluster.Owner}}.{{end}}\n
+[Delete]({{ .TerminateLink }}) this ECSCluster.
+%%%`
+
+const reapableECSClusterEventText = `%%%
+Reaper has discovered an ECSCluster qualified as reapable: [{{.ECSCluster.ID}}]({{.ECSCluster.AWSConsoleURL}}) in region: [{{.ECSCluster.Region}}](https://{{.ECSCluster.Region}}.console.aws.amazon.com/ecs/home?region={{.ECSCluster.Region}}).\n
+{{if .ECSCluster.Owned}}Owned by {{.ECSCluster.Owner}}.\n{{end}}
+{{.ECSCluster.ActiveServices}} active services, {{.ECSCluster.ContainerInstances}} container instances, {{.ECSCluster.RunningTasks}} running tasks.\n
+{{ if .ECSCluster.AWSConsoleURL}}{{.ECSCluster.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.ECSCluster.AWSConsoleURL}})\n
+[Delete]({{ .TerminateLink }}) this ECSCluster.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because ECS clusters cannot be tagged
+func (a *ECSCluster) Save(s *state.State) (bool, error) {
+	return false, nil
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because ECS clusters cannot be tagged
+func (a *ECSCluster) Unsave() (bool, error) {
+	return false, nil
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+// no op because ECS clusters cannot be tagged
+func (a *ECSCluster) Whitelist() (bool, error) {
+	return false, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *ECSCluster) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "Status":
+		if a.Status != nil && *a.Status == filter.Arguments[0] {
+			matched = true
+		}
+	case "ActiveServicesCountGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.ActiveServices() > i {
+			matched = true
+		}
+	case "ActiveServicesCountLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.ActiveServices() < i {
+			matched = true
+		}
+	case "ActiveServicesCountEqualTo":
+		if i, err := filter.Int64Value(0); err == nil && a.ActiveServices() == i {
+			matched = true
+		}
+	case "ContainerInstancesCountGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.ContainerInstances() > i {
+			matched = true
+		}
+	case "ContainerInstancesCountLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.ContainerInstances() < i {
+			matched = true
+		}
+	case "ContainerInstancesCountEqualTo":
+		if i, err := filter.Int64Value(0); err == nil && a.ContainerInstances() == i {
+			matched = true
+		}
+	case "RunningTasksCountGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.RunningTasks() > i {
+			matched = true
+		}
+	case "RunningTasksCountLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.RunningTasks() < i {
+			matched = true
+		}
+	case "RunningTasksCountEqualTo":
+		if i, err := filter.Int64Value(0); err == nil && a.RunningTasks() == i {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering ECSClusters.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *ECSCluster) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/ecs/home?region=%s#/clusters/%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+// ECS refuses to delete clusters that still have services or container instances
+func (a *ECSCluster) Terminate() (bool, error) {
+	log.Info("Terminating ECSCluster %s", a.ReapableDescriptionTiny())
+	api := ecs.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.DeleteCluster(&ecs.DeleteClusterInput{
+		Cluster: aws.String(a.ID().String()),
+	})
+	if err != nil {
+		log.Error("could not delete ECSCluster %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because ECS clusters cannot be stopped, their services can
+func (a *ECSCluster) Stop() (bool, error) {
+	return false, nil
+}
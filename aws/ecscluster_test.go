@@ -0,0 +1,46 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+)
+
+func TestECSClusterFilter(t *testing.T) {
+	a := NewECSCluster("us-west-2", &ecs.Cluster{
+		ClusterName:                       aws.String("web"),
+		Status:                            aws.String("ACTIVE"),
+		ActiveServicesCount:               aws.Int64(0),
+		RegisteredContainerInstancesCount: aws.Int64(2),
+		RunningTasksCount:                 aws.Int64(0),
+	}, []reapable.ID{"i-1", "i-2"})
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"Status", []string{"ACTIVE"}, true},
+		{"Status", []string{"INACTIVE"}, false},
+		{"ActiveServicesCountEqualTo", []string{"0"}, true},
+		{"ActiveServicesCountGreaterThan", []string{"0"}, false},
+		{"ActiveServicesCountLessThan", []string{"1"}, true},
+		{"ContainerInstancesCountEqualTo", []string{"2"}, true},
+		{"ContainerInstancesCountGreaterThan", []string{"2"}, false},
+		{"ContainerInstancesCountLessThan", []string{"3"}, true},
+		{"RunningTasksCountEqualTo", []string{"0"}, true},
+		{"RunningTasksCountGreaterThan", []string{"0"}, false},
+		{"RunningTasksCountLessThan", []string{"0"}, false},
+		// container instances make it a dependency
+		{"IsDependency", []string{"true"}, true},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// ECSService is a Reapable, Filterable
+// embeds AWS API's ecs.Service
+type ECSService struct {
+	Resource
+	ecs.Service
+
+	ClusterName string
+}
+
+// NewECSService creates an ECSService from the AWS API's ecs.Service
+// service names are only unique within a cluster, so its ID is <cluster>/<service>
+// services cannot be tagged, so they always start in the initial state
+func NewECSService(region, clusterName string, service *ecs.Service) *ECSService {
+	a := ECSService{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(fmt.Sprintf("%s/%s", clusterName, *service.ServiceName)),
+			Name:   *service.ServiceName,
+			Tags:   make(map[string]string),
+		},
+		Service:     *service,
+		ClusterName: clusterName,
+	}
+
+	// initial state
+	a.reaperState = state.NewState()
+
+	return &a
+}
+
+// Desired returns the ECSService's desired count
+func (a *ECSService) Desired() int64 {
+	if a.DesiredCount == nil {
+		return 0
+	}
+	return *a.DesiredCount
+}
+
+// Running returns the ECSService's running count
+func (a *ECSService) Running() int64 {
+	if a.RunningCount == nil {
+		return 0
+	}
+	return *a.RunningCount
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *ECSService) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableECSServiceEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *ECSService) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableECSServiceEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *ECSService) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableECSServiceEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *ECSService) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableECSServiceEventHTMLShort)
+	return
+}
+
+type eCSServiceEventData struct {
+	Config        *Config
+	ECSService    *ECSService
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+}
+
+func (a *ECSService) getTemplateData() (interface{}, error) {
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &eCSServiceEventData{
+		Config:        config,
+		ECSService:    a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+	}, nil
+}
+
+const reapableECSServiceEventHTML = `
+<html>
+<body>
+	<p>ECSService <a href="{{ .ECSService.AWSConsoleURL }}">{{ .ECSService.Resource.Name }} in cluster {{ .ECSService.ClusterName }} in {{.ECSService.Region}}</a> qualifies as reapable.</p>
+
+	<p>
+		It runs {{ .ECSService.Running }} of {{ .ECSService.Desired }} desired tasks of {{ .ECSService.TaskDefinition }}.
+	</p>
+
+	<p>
+		ECS services cannot be tagged, so the Reaper cannot keep track of this ECSService: you will be notified again on every run, and it will not be deleted unless you delete it below.
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+			<li><a href="{{ .StopLink }}">Scale it to 0</a></li>
+		</ul>
+	</p>
+</body>
+</html>
+`
+
+const reapableECSServiceEventHTMLShort = `
+<html>
+<body>
+	<p>ECSService <a href="{{ .ECSService.AWSConsoleURL }}">{{ .ECSService.ID }}</a> in {{.ECSService.Region}} qualifies as reapable, and will not be deleted unless you delete it.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a> or
+		<a href="{{ .StopLink }}">Stop</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableECSServiceEventTextShort = `%%%
+ECSService [{{.ECSService.ID}}]({{.ECSService.AWSConsoleURL}}) in region: [{{.ECSService.Region}}](https://{{.ECSService.Region}}.console.aws.amazon.com/ecs/home?region={{.ECSService.Region}}).{{if .ECSService.Owned}} Owned by {{.ECSService.Owner}}.{{end}}\n
+[Scale to 0]({{ .StopLink }}) or [Delete]({{ .TerminateLink }}) this ECSService.
+%%%`
+
+const reapableECSServiceEventText = `%%%
+Reaper has discovered an ECSService qualified as reapable: [{{.ECSService.ID}}]({{.ECSService.AWSConsoleURL}}) in region: [{{.ECSService.Region}}](https://{{.ECSService.Region}}.console.aws.amazon.com/ecs/home?region={{.ECSService.Region}}).\n
+{{if .ECSService.Owned}}Owned by {{.ECSService.Owner}}.\n{{end}}
+Running {{.ECSService.Running}} of {{.ECSService.Desired}} desired tasks of {{.ECSService.TaskDefinition}}.\n
+{{ if .ECSService.AWSConsoleURL}}{{.ECSService.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.ECSService.AWSConsoleURL}})\n
+[Scale to 0]({{ .StopLink }}) this ECSService.
+[Delete]({{ .TerminateLink }}) this ECSService.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because ECS services cannot be tagged
+func (a *ECSService) Save(s *state.State) (bool, error) {
+	return false, nil
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because ECS services cannot be tagged
+func (a *ECSService) Unsave() (bool, error) {
+	return false, nil
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+// no op because ECS services cannot be tagged
+func (a *ECSService) Whitelist() (bool, error) {
+	return false, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *ECSService) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "Status":
+		if a.Status != nil && *a.Status == filter.Arguments[0] {
+			matched = true
+		}
+	case "Cluster":
+		if a.ClusterName == filter.Arguments[0] {
+			matched = true
+		}
+	case "DesiredCountGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.Desired() > i {
+			matched = true
+		}
+	case "DesiredCountLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.Desired() < i {
+			matched = true
+		}
+	case "DesiredCountEqualTo":
+		if i, err := filter.Int64Value(0); err == nil && a.Desired() == i {
+			matched = true
+		}
+	case "RunningCountGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.Running() > i {
+			matched = true
+		}
+	case "RunningCountLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.Running() < i {
+			matched = true
+		}
+	case "RunningCountEqualTo":
+		if i, err := filter.Int64Value(0); err == nil && a.Running() == i {
+			matched = true
+		}
+	case "CreatedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CreatedAt != nil && time.Since(*a.CreatedAt) < d {
+			matched = true
+		}
+	case "CreatedNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CreatedAt != nil && time.Since(*a.CreatedAt) > d {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering ECSServices.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *ECSService) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/ecs/home?region=%s#/clusters/%s/services/%s/details",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ClusterName), url.QueryEscape(a.Name)))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+// ECS only deletes services scaled to 0, so it is stopped first
+func (a *ECSService) Terminate() (bool, error) {
+	if a.Desired() > 0 {
+		if _, err := a.scaleToCount(0); err != nil {
+			return false, err
+		}
+	}
+
+	log.Info("Terminating ECSService %s", a.ReapableDescriptionTiny())
+	api := ecs.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.DeleteService(&ecs.DeleteServiceInput{
+		Cluster: aws.String(a.ClusterName),
+		Service: aws.String(a.Name),
+	})
+	if err != nil {
+		log.Error("could not delete ECSService %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+func (a *ECSService) scaleToCount(count int64) (bool, error) {
+	log.Info("Scaling ECSService %s to count %d.", a.ReapableDescriptionTiny(), count)
+	api := ecs.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.UpdateService(&ecs.UpdateServiceInput{
+		Cluster:      aws.String(a.ClusterName),
+		Service:      aws.String(a.Name),
+		DesiredCount: aws.Int64(count),
+	})
+	if err != nil {
+		log.Error("could not update ECSService %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// scales the service to 0 tasks, like AutoScalingGroups
+func (a *ECSService) Stop() (bool, error) {
+	return a.scaleToCount(0)
+}
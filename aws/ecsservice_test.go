@@ -0,0 +1,50 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestECSServiceFilter(t *testing.T) {
+	a := NewECSService("us-west-2", "web", &ecs.Service{
+		ServiceName:  aws.String("frontend"),
+		Status:       aws.String("ACTIVE"),
+		DesiredCount: aws.Int64(2),
+		RunningCount: aws.Int64(0),
+		CreatedAt:    aws.Time(time.Now().Add(-48 * time.Hour)),
+	})
+
+	if a.ID() != "web/frontend" {
+		t.Errorf("ID() = %s, want web/frontend", a.ID())
+	}
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"Status", []string{"ACTIVE"}, true},
+		{"Status", []string{"DRAINING"}, false},
+		{"Cluster", []string{"web"}, true},
+		{"Cluster", []string{"api"}, false},
+		{"DesiredCountGreaterThan", []string{"1"}, true},
+		{"DesiredCountLessThan", []string{"2"}, false},
+		{"DesiredCountEqualTo", []string{"2"}, true},
+		{"RunningCountGreaterThan", []string{"0"}, false},
+		{"RunningCountLessThan", []string{"1"}, true},
+		{"RunningCountEqualTo", []string{"0"}, true},
+		{"CreatedInTheLast", []string{"24h"}, false},
+		{"CreatedNotInTheLast", []string{"24h"}, true},
+		{"Named", []string{"frontend"}, true},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
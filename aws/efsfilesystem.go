@@ -0,0 +1,450 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/efs"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// mountTargetDeletionTimeout is how long Terminate waits for mount targets to be deleted
+const mountTargetDeletionTimeout = 5 * time.Minute
+
+// EFSFileSystem is a Reapable, Filterable
+// embeds AWS API's efs.FileSystemDescription
+type EFSFileSystem struct {
+	Resource
+	efs.FileSystemDescription
+}
+
+// NewEFSFileSystem creates an EFSFileSystem from the AWS API's efs.FileSystemDescription
+func NewEFSFileSystem(region string, fs *efs.FileSystemDescription, tags []*efs.Tag) *EFSFileSystem {
+	a := EFSFileSystem{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*fs.FileSystemId),
+			Tags:   make(map[string]string),
+		},
+		FileSystemDescription: *fs,
+	}
+
+	if fs.Name != nil {
+		a.Resource.Name = *fs.Name
+	}
+
+	for _, tag := range tags {
+		a.Resource.Tags[*tag.Key] = *tag.Value
+	}
+
+	if a.Tagged("aws:cloudformation:stack-name") {
+		a.Dependency = true
+		a.IsInCloudformation = true
+	}
+
+	if a.Tagged(reaperTag) {
+		// restore previously tagged state
+		a.reaperState = state.NewStateWithTag(a.Tag(reaperTag))
+	} else {
+		// initial state
+		a.reaperState = state.NewState()
+	}
+
+	return &a
+}
+
+// Size returns the EFSFileSystem's last metered size in bytes
+func (a *EFSFileSystem) Size() int64 {
+	if a.SizeInBytes == nil || a.SizeInBytes.Value == nil {
+		return 0
+	}
+	return *a.SizeInBytes.Value
+}
+
+// MountTargets returns the EFSFileSystem's number of mount targets
+func (a *EFSFileSystem) MountTargets() int64 {
+	if a.NumberOfMountTargets == nil {
+		return 0
+	}
+	return *a.NumberOfMountTargets
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *EFSFileSystem) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableEFSFileSystemEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *EFSFileSystem) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableEFSFileSystemEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *EFSFileSystem) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableEFSFileSystemEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *EFSFileSystem) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableEFSFileSystemEventHTMLShort)
+	return
+}
+
+type eFSFileSystemEventData struct {
+	Config        *Config
+	EFSFileSystem *EFSFileSystem
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+	IgnoreLink1   string
+	IgnoreLink3   string
+	IgnoreLink7   string
+}
+
+func (a *EFSFileSystem) getTemplateData() (interface{}, error) {
+	ignore1, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(1*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore3, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(3*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore7, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &eFSFileSystemEventData{
+		Config:        config,
+		EFSFileSystem: a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+		IgnoreLink1:   ignore1,
+		IgnoreLink3:   ignore3,
+		IgnoreLink7:   ignore7,
+	}, nil
+}
+
+const reapableEFSFileSystemEventHTML = `
+<html>
+<body>
+	<p>EFSFileSystem <a href="{{ .EFSFileSystem.AWSConsoleURL }}">{{ if .EFSFileSystem.Resource.Name }}"{{ .EFSFileSystem.Resource.Name }}" {{ end }}{{ .EFSFileSystem.ID }} in {{.EFSFileSystem.Region}}</a> is scheduled to be deleted.</p>
+
+	<p>
+		It stores {{ .EFSFileSystem.Size }} bytes and has {{ .EFSFileSystem.MountTargets }} mount targets, which will be deleted with it.
+	</p>
+
+	<p>
+		You can ignore this message and your EFSFileSystem will advance to the next state after <strong>{{.EFSFileSystem.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>. If you do not take action it will be deleted!
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+			<li><a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a></li>
+			<li><a href="{{ .IgnoreLink3 }}">Ignore it for 3 more days</a></li>
+			<li><a href="{{ .IgnoreLink7}}">Ignore it for 7 more days</a></li>
+		</ul>
+	</p>
+
+	<p>
+		If you want the Reaper to ignore this EFSFileSystem tag it with {{ .Config.WhitelistTag }} with any value, or click <a href="{{ .WhitelistLink }}">here</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableEFSFileSystemEventHTMLShort = `
+<html>
+<body>
+	<p>EFSFileSystem <a href="{{ .EFSFileSystem.AWSConsoleURL }}">{{ if .EFSFileSystem.Resource.Name }}"{{ .EFSFileSystem.Resource.Name }}" {{ end }}{{ .EFSFileSystem.ID }}</a> in {{.EFSFileSystem.Region}} ({{ .EFSFileSystem.Size }} bytes) is scheduled to be deleted after <strong>{{.EFSFileSystem.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a>,
+		<a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a>,
+		<a href="{{ .IgnoreLink3 }}">3 days</a>,
+		<a href="{{ .IgnoreLink7}}"> 7 days</a>, or
+		<a href="{{ .WhitelistLink }}">Whitelist</a> it.
+	</p>
+</body>
+</html>
+`
+
+const reapableEFSFileSystemEventTextShort = `%%%
+EFSFileSystem {{if .EFSFileSystem.Resource.Name}}"{{.EFSFileSystem.Resource.Name}}" {{end}}[{{.EFSFileSystem.ID}}]({{.EFSFileSystem.AWSConsoleURL}}) in region: [{{.EFSFileSystem.Region}}](https://{{.EFSFileSystem.Region}}.console.aws.amazon.com/efs/home?region={{.EFSFileSystem.Region}}).{{if .EFSFileSystem.Owned}} Owned by {{.EFSFileSystem.Owner}}.{{end}}\n
+[Whitelist]({{ .WhitelistLink }}) or [Delete]({{ .TerminateLink }}) this EFSFileSystem.
+%%%`
+
+const reapableEFSFileSystemEventText = `%%%
+Reaper has discovered an EFSFileSystem qualified as reapable: {{if .EFSFileSystem.Resource.Name}}"{{.EFSFileSystem.Resource.Name}}" {{end}}[{{.EFSFileSystem.ID}}]({{.EFSFileSystem.AWSConsoleURL}}) in region: [{{.EFSFileSystem.Region}}](https://{{.EFSFileSystem.Region}}.console.aws.amazon.com/efs/home?region={{.EFSFileSystem.Region}}).\n
+{{if .EFSFileSystem.Owned}}Owned by {{.EFSFileSystem.Owner}}.\n{{end}}
+{{.EFSFileSystem.Size}} bytes, {{.EFSFileSystem.MountTargets}} mount targets.\n
+{{ if .EFSFileSystem.AWSConsoleURL}}{{.EFSFileSystem.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.EFSFileSystem.AWSConsoleURL}})\n
+[Whitelist]({{ .WhitelistLink }}) this EFSFileSystem.
+[Delete]({{ .TerminateLink }}) this EFSFileSystem.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *EFSFileSystem) Save(s *state.State) (bool, error) {
+	log.Info("Saving %s", a.ReapableDescriptionTiny())
+	return tagEFSFileSystem(a.Region(), a.ID(), reaperTag, s.String())
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *EFSFileSystem) Unsave() (bool, error) {
+	log.Info("Unsaving %s", a.ReapableDescriptionTiny())
+	return untagEFSFileSystem(a.Region(), a.ID(), reaperTag)
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+func (a *EFSFileSystem) Whitelist() (bool, error) {
+	log.Info("Whitelisting EFSFileSystem %s", a.ReapableDescriptionTiny())
+	return tagEFSFileSystem(a.Region(), a.ID(), config.WhitelistTag, "true")
+}
+
+func untagEFSFileSystem(region reapable.Region, id reapable.ID, key string) (bool, error) {
+	api := efs.New(sess, aws.NewConfig().WithRegion(region.String()))
+	_, err := api.DeleteTags(&efs.DeleteTagsInput{
+		FileSystemId: aws.String(id.String()),
+		TagKeys:      []*string{aws.String(key)},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func tagEFSFileSystem(region reapable.Region, id reapable.ID, key, value string) (bool, error) {
+	api := efs.New(sess, aws.NewConfig().WithRegion(region.String()))
+	_, err := api.CreateTags(&efs.CreateTagsInput{
+		FileSystemId: aws.String(id.String()),
+		Tags: []*efs.Tag{
+			&efs.Tag{
+				Key:   aws.String(key),
+				Value: aws.String(value),
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *EFSFileSystem) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "LifeCycleState":
+		if a.LifeCycleState != nil && *a.LifeCycleState == filter.Arguments[0] {
+			matched = true
+		}
+	case "MountTargetsCountGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.MountTargets() > i {
+			matched = true
+		}
+	case "MountTargetsCountLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.MountTargets() < i {
+			matched = true
+		}
+	case "MountTargetsCountEqualTo":
+		if i, err := filter.Int64Value(0); err == nil && a.MountTargets() == i {
+			matched = true
+		}
+	case "SizeGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.Size() > i {
+			matched = true
+		}
+	case "SizeLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.Size() < i {
+			matched = true
+		}
+	case "CreatedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CreationTime != nil && time.Since(*a.CreationTime) < d {
+			matched = true
+		}
+	case "CreatedNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CreationTime != nil && time.Since(*a.CreationTime) > d {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Resource.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Resource.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Resource.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Resource.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering EFSFileSystems.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *EFSFileSystem) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/efs/home?region=%s#/filesystems/%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+// EFS only deletes file systems without mount targets, so they are deleted first
+func (a *EFSFileSystem) Terminate() (bool, error) {
+	api := efs.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	resp, err := api.DescribeMountTargets(&efs.DescribeMountTargetsInput{
+		FileSystemId: aws.String(a.ID().String()),
+	})
+	if err != nil {
+		log.Error("could not describe mount targets of EFSFileSystem %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	for _, target := range resp.MountTargets {
+		log.Info("Deleting mount target %s of EFSFileSystem %s", *target.MountTargetId, a.ReapableDescriptionTiny())
+		_, err := api.DeleteMountTarget(&efs.DeleteMountTargetInput{MountTargetId: target.MountTargetId})
+		if err != nil {
+			log.Error("could not delete mount target %s of EFSFileSystem %s", *target.MountTargetId, a.ReapableDescriptionTiny())
+			return false, err
+		}
+	}
+
+	// mount targets are deleted asynchronously
+	if len(resp.MountTargets) > 0 {
+		if err := waitForNoMountTargets(api, a.ID()); err != nil {
+			log.Error("mount targets of EFSFileSystem %s were not deleted", a.ReapableDescriptionTiny())
+			return false, err
+		}
+	}
+
+	log.Info("Terminating EFSFileSystem %s", a.ReapableDescriptionTiny())
+	_, err = api.DeleteFileSystem(&efs.DeleteFileSystemInput{
+		FileSystemId: aws.String(a.ID().String()),
+	})
+	if err != nil {
+		log.Error("could not delete EFSFileSystem %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// waitForNoMountTargets polls an EFS file system until it has no mount targets
+func waitForNoMountTargets(api *efs.EFS, id reapable.ID) error {
+	deadline := time.Now().Add(mountTargetDeletionTimeout)
+	for time.Now().Before(deadline) {
+		resp, err := api.DescribeFileSystems(&efs.DescribeFileSystemsInput{
+			FileSystemId: aws.String(id.String()),
+		})
+		if err != nil {
+			return err
+		}
+		if len(resp.FileSystems) == 0 || *resp.FileSystems[0].NumberOfMountTargets == 0 {
+			return nil
+		}
+		time.Sleep(10 * time.Second)
+	}
+	return fmt.Errorf("EFSFileSystem %s still has mount targets after %s", id, mountTargetDeletionTimeout)
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because EFS file systems cannot be stopped
+func (a *EFSFileSystem) Stop() (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,47 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/efs"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestEFSFileSystemFilter(t *testing.T) {
+	a := NewEFSFileSystem("us-west-2", &efs.FileSystemDescription{
+		FileSystemId:         aws.String("fs-1234"),
+		Name:                 aws.String("shared"),
+		LifeCycleState:       aws.String("available"),
+		NumberOfMountTargets: aws.Int64(0),
+		SizeInBytes:          &efs.FileSystemSize{Value: aws.Int64(6144)},
+		CreationTime:         aws.Time(time.Now().Add(-48 * time.Hour)),
+	}, []*efs.Tag{
+		{Key: aws.String("Owner"), Value: aws.String("bob")},
+	})
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"LifeCycleState", []string{"available"}, true},
+		{"LifeCycleState", []string{"deleting"}, false},
+		{"MountTargetsCountEqualTo", []string{"0"}, true},
+		{"MountTargetsCountGreaterThan", []string{"0"}, false},
+		{"MountTargetsCountLessThan", []string{"1"}, true},
+		{"SizeGreaterThan", []string{"6144"}, false},
+		{"SizeLessThan", []string{"8192"}, true},
+		{"CreatedInTheLast", []string{"24h"}, false},
+		{"CreatedNotInTheLast", []string{"24h"}, true},
+		{"Named", []string{"shared"}, true},
+		{"Tagged", []string{"Owner"}, true},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
@@ -0,0 +1,403 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticsearchservice"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// ElasticsearchDomain is a Reapable, Filterable
+// embeds AWS API's elasticsearchservice.ElasticsearchDomainStatus
+type ElasticsearchDomain struct {
+	Resource
+	elasticsearchservice.ElasticsearchDomainStatus
+
+	// the domain status has no creation time, its cluster config status does
+	CreationDate *time.Time
+}
+
+// NewElasticsearchDomain creates an ElasticsearchDomain from the AWS API's elasticsearchservice.ElasticsearchDomainStatus
+func NewElasticsearchDomain(region string, domain *elasticsearchservice.ElasticsearchDomainStatus, creationDate *time.Time, tags []*elasticsearchservice.Tag) *ElasticsearchDomain {
+	a := ElasticsearchDomain{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*domain.DomainName),
+			Name:   *domain.DomainName,
+			Tags:   make(map[string]string),
+		},
+		ElasticsearchDomainStatus: *domain,
+		CreationDate:              creationDate,
+	}
+
+	for _, tag := range tags {
+		a.Resource.Tags[*tag.Key] = *tag.Value
+	}
+
+	if a.Tagged("aws:cloudformation:stack-name") {
+		a.Dependency = true
+		a.IsInCloudformation = true
+	}
+
+	if a.Tagged(reaperTag) {
+		// restore previously tagged state
+		a.reaperState = state.NewStateWithTag(a.Tag(reaperTag))
+	} else {
+		// initial state
+		a.reaperState = state.NewState()
+	}
+
+	return &a
+}
+
+// InstanceType returns the ElasticsearchDomain's data instance type
+func (a *ElasticsearchDomain) InstanceType() string {
+	if a.ElasticsearchClusterConfig == nil || a.ElasticsearchClusterConfig.InstanceType == nil {
+		return ""
+	}
+	return *a.ElasticsearchClusterConfig.InstanceType
+}
+
+// InstanceCount returns the ElasticsearchDomain's number of data instances
+func (a *ElasticsearchDomain) InstanceCount() int64 {
+	if a.ElasticsearchClusterConfig == nil || a.ElasticsearchClusterConfig.InstanceCount == nil {
+		return 0
+	}
+	return *a.ElasticsearchClusterConfig.InstanceCount
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *ElasticsearchDomain) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableElasticsearchDomainEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *ElasticsearchDomain) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableElasticsearchDomainEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *ElasticsearchDomain) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableElasticsearchDomainEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *ElasticsearchDomain) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableElasticsearchDomainEventHTMLShort)
+	return
+}
+
+type elasticsearchDomainEventData struct {
+	Config              *Config
+	ElasticsearchDomain *ElasticsearchDomain
+	TerminateLink       string
+	StopLink            string
+	WhitelistLink       string
+	IgnoreLink1         string
+	IgnoreLink3         string
+	IgnoreLink7         string
+}
+
+func (a *ElasticsearchDomain) getTemplateData() (interface{}, error) {
+	ignore1, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(1*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore3, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(3*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore7, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &elasticsearchDomainEventData{
+		Config:              config,
+		ElasticsearchDomain: a,
+		TerminateLink:       terminate,
+		StopLink:            stop,
+		WhitelistLink:       whitelist,
+		IgnoreLink1:         ignore1,
+		IgnoreLink3:         ignore3,
+		IgnoreLink7:         ignore7,
+	}, nil
+}
+
+const reapableElasticsearchDomainEventHTML = `
+<html>
+<body>
+	<p>ElasticsearchDomain <a href="{{ .ElasticsearchDomain.AWSConsoleURL }}">{{ .ElasticsearchDomain.ID }} in {{.ElasticsearchDomain.Region}}</a> is scheduled to be deleted.</p>
+
+	<p>
+		It is served at {{ if .ElasticsearchDomain.Endpoint }}<strong>{{ .ElasticsearchDomain.Endpoint }}</strong>{{ else }}no endpoint yet{{ end }} by {{ .ElasticsearchDomain.InstanceCount }} {{ .ElasticsearchDomain.InstanceType }} instances. Its indexes will be deleted with it.
+	</p>
+
+	<p>
+		You can ignore this message and your ElasticsearchDomain will advance to the next state after <strong>{{.ElasticsearchDomain.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>. If you do not take action it will be deleted!
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+			<li><a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a></li>
+			<li><a href="{{ .IgnoreLink3 }}">Ignore it for 3 more days</a></li>
+			<li><a href="{{ .IgnoreLink7}}">Ignore it for 7 more days</a></li>
+		</ul>
+	</p>
+
+	<p>
+		If you want the Reaper to ignore this ElasticsearchDomain tag it with {{ .Config.WhitelistTag }} with any value, or click <a href="{{ .WhitelistLink }}">here</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableElasticsearchDomainEventHTMLShort = `
+<html>
+<body>
+	<p>ElasticsearchDomain <a href="{{ .ElasticsearchDomain.AWSConsoleURL }}">{{ .ElasticsearchDomain.ID }}</a> in {{.ElasticsearchDomain.Region}}{{ if .ElasticsearchDomain.Endpoint }} ({{ .ElasticsearchDomain.Endpoint }}){{ end }} is scheduled to be deleted after <strong>{{.ElasticsearchDomain.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a>,
+		<a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a>,
+		<a href="{{ .IgnoreLink3 }}">3 days</a>,
+		<a href="{{ .IgnoreLink7}}"> 7 days</a>, or
+		<a href="{{ .WhitelistLink }}">Whitelist</a> it.
+	</p>
+</body>
+</html>
+`
+
+const reapableElasticsearchDomainEventTextShort = `%%%
+ElasticsearchDomain [{{.ElasticsearchDomain.ID}}]({{.ElasticsearchDomain.AWSConsoleURL}}) in region: [{{.ElasticsearchDomain.Region}}](https://{{.ElasticsearchDomain.Region}}.console.aws.amazon.com/es/home?region={{.ElasticsearchDomain.Region}}).{{if .ElasticsearchDomain.Owned}} Owned by {{.ElasticsearchDomain.Owner}}.{{end}}\n
+{{if .ElasticsearchDomain.Endpoint}}Endpoint: {{.ElasticsearchDomain.Endpoint}}.\n{{end}}
+[Whitelist]({{ .WhitelistLink }}) or [Delete]({{ .TerminateLink }}) this ElasticsearchDomain.
+%%%`
+
+const reapableElasticsearchDomainEventText = `%%%
+Reaper has discovered an ElasticsearchDomain qualified as reapable: [{{.ElasticsearchDomain.ID}}]({{.ElasticsearchDomain.AWSConsoleURL}}) in region: [{{.ElasticsearchDomain.Region}}](https://{{.ElasticsearchDomain.Region}}.console.aws.amazon.com/es/home?region={{.ElasticsearchDomain.Region}}).\n
+{{if .ElasticsearchDomain.Owned}}Owned by {{.ElasticsearchDomain.Owner}}.\n{{end}}
+{{if .ElasticsearchDomain.Endpoint}}Endpoint: {{.ElasticsearchDomain.Endpoint}}.\n{{end}}
+{{.ElasticsearchDomain.InstanceCount}} {{.ElasticsearchDomain.InstanceType}} instances.\n
+{{ if .ElasticsearchDomain.AWSConsoleURL}}{{.ElasticsearchDomain.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.ElasticsearchDomain.AWSConsoleURL}})\n
+[Whitelist]({{ .WhitelistLink }}) this ElasticsearchDomain.
+[Delete]({{ .TerminateLink }}) this ElasticsearchDomain.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *ElasticsearchDomain) Save(s *state.State) (bool, error) {
+	log.Info("Saving %s", a.ReapableDescriptionTiny())
+	return tagElasticsearchDomain(a.Region(), *a.ARN, reaperTag, s.RestrictedString())
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *ElasticsearchDomain) Unsave() (bool, error) {
+	log.Info("Unsaving %s", a.ReapableDescriptionTiny())
+	return untagElasticsearchDomain(a.Region(), *a.ARN, reaperTag)
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+func (a *ElasticsearchDomain) Whitelist() (bool, error) {
+	log.Info("Whitelisting ElasticsearchDomain %s", a.ReapableDescriptionTiny())
+	return tagElasticsearchDomain(a.Region(), *a.ARN, config.WhitelistTag, "true")
+}
+
+func untagElasticsearchDomain(region reapable.Region, arn, key string) (bool, error) {
+	api := elasticsearchservice.New(sess, aws.NewConfig().WithRegion(region.String()))
+	_, err := api.RemoveTags(&elasticsearchservice.RemoveTagsInput{
+		ARN:     aws.String(arn),
+		TagKeys: []*string{aws.String(key)},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func tagElasticsearchDomain(region reapable.Region, arn, key, value string) (bool, error) {
+	api := elasticsearchservice.New(sess, aws.NewConfig().WithRegion(region.String()))
+	_, err := api.AddTags(&elasticsearchservice.AddTagsInput{
+		ARN: aws.String(arn),
+		TagList: []*elasticsearchservice.Tag{
+			&elasticsearchservice.Tag{
+				Key:   aws.String(key),
+				Value: aws.String(value),
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *ElasticsearchDomain) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "InstanceType":
+		if a.InstanceType() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotInstanceType":
+		if a.InstanceType() != filter.Arguments[0] {
+			matched = true
+		}
+	case "InstanceCountGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.InstanceCount() > i {
+			matched = true
+		}
+	case "InstanceCountLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.InstanceCount() < i {
+			matched = true
+		}
+	case "InstanceCountEqualTo":
+		if i, err := filter.Int64Value(0); err == nil && a.InstanceCount() == i {
+			matched = true
+		}
+	case "CreatedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CreationDate != nil && time.Since(*a.CreationDate) < d {
+			matched = true
+		}
+	case "CreatedNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CreationDate != nil && time.Since(*a.CreationDate) > d {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering ElasticsearchDomains.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *ElasticsearchDomain) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/es/home?region=%s#domain:resource=%s;action=dashboard",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+func (a *ElasticsearchDomain) Terminate() (bool, error) {
+	log.Info("Terminating ElasticsearchDomain %s", a.ReapableDescriptionTiny())
+	api := elasticsearchservice.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.DeleteElasticsearchDomain(&elasticsearchservice.DeleteElasticsearchDomainInput{
+		DomainName: aws.String(a.ID().String()),
+	})
+	if err != nil {
+		log.Error("could not delete ElasticsearchDomain %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because Elasticsearch domains cannot be stopped
+func (a *ElasticsearchDomain) Stop() (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,49 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticsearchservice"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestElasticsearchDomainFilter(t *testing.T) {
+	a := NewElasticsearchDomain("us-west-2", &elasticsearchservice.ElasticsearchDomainStatus{
+		DomainName: aws.String("search"),
+		ARN:        aws.String("arn:aws:es:us-west-2:123456789012:domain/search"),
+		ElasticsearchClusterConfig: &elasticsearchservice.ElasticsearchClusterConfig{
+			InstanceType:  aws.String("m3.medium.elasticsearch"),
+			InstanceCount: aws.Int64(2),
+		},
+	}, aws.Time(time.Now().Add(-48*time.Hour)), nil)
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"InstanceType", []string{"m3.medium.elasticsearch"}, true},
+		{"NotInstanceType", []string{"m3.medium.elasticsearch"}, false},
+		{"InstanceCountGreaterThan", []string{"1"}, true},
+		{"InstanceCountLessThan", []string{"2"}, false},
+		{"InstanceCountEqualTo", []string{"2"}, true},
+		{"CreatedInTheLast", []string{"24h"}, false},
+		{"CreatedNotInTheLast", []string{"24h"}, true},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+
+	// the creation date is unknown when the domain's configuration could not be described
+	b := NewElasticsearchDomain("us-west-2", &elasticsearchservice.ElasticsearchDomainStatus{
+		DomainName: aws.String("unknown"),
+	}, nil, nil)
+	if b.Filter(*filters.NewFilter("CreatedNotInTheLast", []string{"1h"})) {
+		t.Error("CreatedNotInTheLast matched a domain without a creation date")
+	}
+}
@@ -0,0 +1,433 @@
+package aws
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/emr"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// ErrTerminationProtected is returned when terminating an EMRCluster with termination protection
+// TerminateJobFlows would otherwise succeed without terminating it
+var ErrTerminationProtected = errors.New("EMRCluster has termination protection enabled and cannot be terminated")
+
+// EMRCluster is a Reapable, Filterable
+// embeds AWS API's emr.Cluster
+type EMRCluster struct {
+	Resource
+	emr.Cluster
+
+	// when the EMRCluster entered its current state
+	StateChangeTime time.Time
+
+	SecurityGroupIDs []reapable.ID
+}
+
+// NewEMRCluster creates an EMRCluster from the AWS API's emr.Cluster
+// stateChangeTime is when it entered its current state, which EMR only reports through steps
+func NewEMRCluster(region string, cluster *emr.Cluster, stateChangeTime time.Time) *EMRCluster {
+	a := EMRCluster{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*cluster.Id),
+			Name:   *cluster.Name,
+			Tags:   make(map[string]string),
+		},
+		Cluster:         *cluster,
+		StateChangeTime: stateChangeTime,
+	}
+
+	for _, tag := range cluster.Tags {
+		a.Resource.Tags[*tag.Key] = *tag.Value
+	}
+
+	if attributes := cluster.Ec2InstanceAttributes; attributes != nil {
+		groups := append([]*string{
+			attributes.EmrManagedMasterSecurityGroup,
+			attributes.EmrManagedSlaveSecurityGroup,
+			attributes.ServiceAccessSecurityGroup,
+		}, attributes.AdditionalMasterSecurityGroups...)
+		groups = append(groups, attributes.AdditionalSlaveSecurityGroups...)
+		for _, group := range groups {
+			if group != nil {
+				a.SecurityGroupIDs = append(a.SecurityGroupIDs, reapable.ID(*group))
+			}
+		}
+	}
+
+	if a.Tagged("aws:cloudformation:stack-name") {
+		a.Dependency = true
+		a.IsInCloudformation = true
+	}
+
+	if a.Tagged(reaperTag) {
+		// restore previously tagged state
+		a.reaperState = state.NewStateWithTag(a.Tag(reaperTag))
+	} else {
+		// initial state
+		a.reaperState = state.NewState()
+	}
+
+	return &a
+}
+
+// State returns the EMRCluster's state: STARTING, BOOTSTRAPPING, RUNNING, WAITING...
+func (a *EMRCluster) State() string {
+	if a.Status == nil || a.Status.State == nil {
+		return ""
+	}
+	return *a.Status.State
+}
+
+// TerminationProtected returns whether the EMRCluster has termination protection enabled
+func (a *EMRCluster) TerminationProtected() bool {
+	return a.Cluster.TerminationProtected != nil && *a.Cluster.TerminationProtected
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *EMRCluster) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableEMRClusterEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *EMRCluster) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableEMRClusterEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *EMRCluster) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableEMRClusterEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *EMRCluster) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableEMRClusterEventHTMLShort)
+	return
+}
+
+type eMRClusterEventData struct {
+	Config        *Config
+	EMRCluster    *EMRCluster
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+	IgnoreLink1   string
+	IgnoreLink3   string
+	IgnoreLink7   string
+}
+
+func (a *EMRCluster) getTemplateData() (interface{}, error) {
+	ignore1, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(1*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore3, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(3*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore7, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &eMRClusterEventData{
+		Config:        config,
+		EMRCluster:    a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+		IgnoreLink1:   ignore1,
+		IgnoreLink3:   ignore3,
+		IgnoreLink7:   ignore7,
+	}, nil
+}
+
+const reapableEMRClusterEventHTML = `
+<html>
+<body>
+	<p>EMRCluster <a href="{{ .EMRCluster.AWSConsoleURL }}">"{{ .EMRCluster.Resource.Name }}" {{ .EMRCluster.ID }} in {{.EMRCluster.Region}}</a> has been {{ .EMRCluster.State }} since {{ .EMRCluster.StateChangeTime.UTC.Format "Jan 2, 2006 at 3:04pm (MST)" }}{{ if .EMRCluster.TerminationProtected }}. It has termination protection enabled, so Reaper cannot terminate it.{{ else }} and is scheduled to be terminated.{{ end }}</p>
+
+	<p>
+		It has used {{ .EMRCluster.NormalizedInstanceHours }} normalized instance hours.
+	</p>
+
+	<p>
+		{{ if .EMRCluster.TerminationProtected }}Please terminate it yourself if it is no longer needed, or whitelist it.{{ else }}You can ignore this message and your EMRCluster will advance to the next state after <strong>{{.EMRCluster.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>. If you do not take action it will be terminated!{{ end }}
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			{{ if not .EMRCluster.TerminationProtected }}<li><a href="{{ .TerminateLink }}">Terminate it now</a></li>{{ end }}
+			<li><a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a></li>
+			<li><a href="{{ .IgnoreLink3 }}">Ignore it for 3 more days</a></li>
+			<li><a href="{{ .IgnoreLink7}}">Ignore it for 7 more days</a></li>
+		</ul>
+	</p>
+
+	<p>
+		If you want the Reaper to ignore this EMRCluster tag it with {{ .Config.WhitelistTag }} with any value, or click <a href="{{ .WhitelistLink }}">here</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableEMRClusterEventHTMLShort = `
+<html>
+<body>
+	<p>EMRCluster <a href="{{ .EMRCluster.AWSConsoleURL }}">"{{ .EMRCluster.Resource.Name }}" {{ .EMRCluster.ID }}</a> in {{.EMRCluster.Region}} ({{ .EMRCluster.State }} since {{ .EMRCluster.StateChangeTime.UTC.Format "Jan 2, 2006 at 3:04pm (MST)" }}) {{ if .EMRCluster.TerminationProtected }}has termination protection enabled, so Reaper cannot terminate it{{ else }}is scheduled to be terminated after <strong>{{.EMRCluster.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>{{ end }}.
+		<br />
+		{{ if not .EMRCluster.TerminationProtected }}<a href="{{ .TerminateLink }}">Terminate</a>,{{ end }}
+		<a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a>,
+		<a href="{{ .IgnoreLink3 }}">3 days</a>,
+		<a href="{{ .IgnoreLink7}}"> 7 days</a>, or
+		<a href="{{ .WhitelistLink }}">Whitelist</a> it.
+	</p>
+</body>
+</html>
+`
+
+const reapableEMRClusterEventTextShort = `%%%
+EMRCluster "{{.EMRCluster.Resource.Name}}" [{{.EMRCluster.ID}}]({{.EMRCluster.AWSConsoleURL}}) in region: [{{.EMRCluster.Region}}](https://{{.EMRCluster.Region}}.console.aws.amazon.com/elasticmapreduce/home?region={{.EMRCluster.Region}}).{{if .EMRCluster.Owned}} Owned by {{.EMRCluster.Owner}}.{{end}}\n
+{{.EMRCluster.State}} since {{.EMRCluster.StateChangeTime.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}.{{if .EMRCluster.TerminationProtected}} Termination protected, not actionable.{{end}}\n
+[Whitelist]({{ .WhitelistLink }}){{if not .EMRCluster.TerminationProtected}} or [Terminate]({{ .TerminateLink }}){{end}} this EMRCluster.
+%%%`
+
+const reapableEMRClusterEventText = `%%%
+Reaper has discovered an EMRCluster qualified as reapable: "{{.EMRCluster.Resource.Name}}" [{{.EMRCluster.ID}}]({{.EMRCluster.AWSConsoleURL}}) in region: [{{.EMRCluster.Region}}](https://{{.EMRCluster.Region}}.console.aws.amazon.com/elasticmapreduce/home?region={{.EMRCluster.Region}}).\n
+{{if .EMRCluster.Owned}}Owned by {{.EMRCluster.Owner}}.\n{{end}}
+{{.EMRCluster.State}} since {{.EMRCluster.StateChangeTime.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}, {{.EMRCluster.NormalizedInstanceHours}} normalized instance hours.\n
+{{if .EMRCluster.TerminationProtected}}It has termination protection enabled, so Reaper cannot terminate it.\n{{end}}
+{{ if .EMRCluster.AWSConsoleURL}}{{.EMRCluster.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.EMRCluster.AWSConsoleURL}})\n
+[Whitelist]({{ .WhitelistLink }}) this EMRCluster.
+{{if not .EMRCluster.TerminationProtected}}[Terminate]({{ .TerminateLink }}) this EMRCluster.{{end}}
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *EMRCluster) Save(s *state.State) (bool, error) {
+	log.Info("Saving %s", a.ReapableDescriptionTiny())
+	return tagEMRCluster(a.Region(), a.ID(), reaperTag, s.String())
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *EMRCluster) Unsave() (bool, error) {
+	log.Info("Unsaving %s", a.ReapableDescriptionTiny())
+	return untagEMRCluster(a.Region(), a.ID(), reaperTag)
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+func (a *EMRCluster) Whitelist() (bool, error) {
+	log.Info("Whitelisting EMRCluster %s", a.ReapableDescriptionTiny())
+	return tagEMRCluster(a.Region(), a.ID(), config.WhitelistTag, "true")
+}
+
+func untagEMRCluster(region reapable.Region, id reapable.ID, key string) (bool, error) {
+	api := emr.New(sess, aws.NewConfig().WithRegion(region.String()))
+	_, err := api.RemoveTags(&emr.RemoveTagsInput{
+		ResourceId: aws.String(id.String()),
+		TagKeys:    []*string{aws.String(key)},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func tagEMRCluster(region reapable.Region, id reapable.ID, key, value string) (bool, error) {
+	api := emr.New(sess, aws.NewConfig().WithRegion(region.String()))
+	_, err := api.AddTags(&emr.AddTagsInput{
+		ResourceId: aws.String(id.String()),
+		Tags: []*emr.Tag{
+			&emr.Tag{
+				Key:   aws.String(key),
+				Value: aws.String(value),
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *EMRCluster) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "State":
+		// one of:
+		// STARTING
+		// BOOTSTRAPPING
+		// RUNNING
+		// WAITING
+		if a.State() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotState":
+		if a.State() != filter.Arguments[0] {
+			matched = true
+		}
+	case "TerminationProtected":
+		if b, err := filter.BoolValue(0); err == nil && a.TerminationProtected() == b {
+			matched = true
+		}
+	case "StateTimeInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && !a.StateChangeTime.IsZero() && time.Since(a.StateChangeTime) < d {
+			matched = true
+		}
+	case "StateTimeNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && !a.StateChangeTime.IsZero() && time.Since(a.StateChangeTime) > d {
+			matched = true
+		}
+	case "NormalizedInstanceHoursGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.NormalizedInstanceHours != nil && *a.NormalizedInstanceHours > i {
+			matched = true
+		}
+	case "NormalizedInstanceHoursLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.NormalizedInstanceHours != nil && *a.NormalizedInstanceHours < i {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Resource.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Resource.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Resource.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Resource.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering EMRClusters.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *EMRCluster) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/elasticmapreduce/home?region=%s#cluster-details:%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+// termination protected clusters are not actionable
+func (a *EMRCluster) Terminate() (bool, error) {
+	if a.TerminationProtected() {
+		log.Error("could not terminate EMRCluster %s: %s", a.ReapableDescriptionTiny(), ErrTerminationProtected.Error())
+		return false, ErrTerminationProtected
+	}
+
+	log.Info("Terminating EMRCluster %s", a.ReapableDescriptionTiny())
+	api := emr.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.TerminateJobFlows(&emr.TerminateJobFlowsInput{
+		JobFlowIds: []*string{aws.String(a.ID().String())},
+	})
+	if err != nil {
+		log.Error("could not terminate EMRCluster %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because EMR clusters cannot be stopped
+func (a *EMRCluster) Stop() (bool, error) {
+	return false, nil
+}
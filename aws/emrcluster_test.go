@@ -0,0 +1,117 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/emr"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestEMRClusterFilter(t *testing.T) {
+	a := NewEMRCluster("us-west-2", &emr.Cluster{
+		Id:                      aws.String("j-1234"),
+		Name:                    aws.String("spark"),
+		Status:                  &emr.ClusterStatus{State: aws.String("WAITING")},
+		TerminationProtected:    aws.Bool(true),
+		NormalizedInstanceHours: aws.Int64(64),
+	}, time.Now().Add(-48*time.Hour))
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"State", []string{"WAITING"}, true},
+		{"State", []string{"RUNNING"}, false},
+		{"NotState", []string{"RUNNING"}, true},
+		{"TerminationProtected", []string{"true"}, true},
+		{"StateTimeInTheLast", []string{"24h"}, false},
+		{"StateTimeNotInTheLast", []string{"24h"}, true},
+		{"NormalizedInstanceHoursGreaterThan", []string{"32"}, true},
+		{"NormalizedInstanceHoursLessThan", []string{"32"}, false},
+		// named by its name, identified by its id
+		{"Named", []string{"spark"}, true},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
+
+func TestEMRClusterStateChangeTime(t *testing.T) {
+	created, ready := time.Unix(1460000000, 0), time.Unix(1460000600, 0)
+	firstStep, lastStep := time.Unix(1460001000, 0), time.Unix(1460002000, 0)
+
+	var listed []string
+	s, closeServer := testSession(func(w http.ResponseWriter, r *http.Request) {
+		var input struct{ StepStates []string }
+		json.NewDecoder(r.Body).Decode(&input)
+		listed = input.StepStates
+		if len(input.StepStates) == 1 && input.StepStates[0] == emr.StepStateRunning {
+			fmt.Fprintf(w, `{"Steps": [{"Status": {"Timeline": {"StartDateTime": %d}}}]}`, lastStep.Unix())
+			return
+		}
+		// steps are listed most recent first
+		fmt.Fprintf(w, `{"Steps": [{"Status": {"Timeline": {"EndDateTime": %d}}}, {"Status": {"Timeline": {"EndDateTime": %d}}}, {"Status": {}}]}`,
+			lastStep.Unix(), firstStep.Unix())
+	})
+	defer closeServer()
+	api := emr.New(s)
+
+	for _, test := range []struct {
+		state           string
+		stateChangeTime time.Time
+		listed          bool
+	}{
+		// since its last step ended
+		{emr.ClusterStateWaiting, lastStep, true},
+		// since its current step started
+		{emr.ClusterStateRunning, lastStep, true},
+		// other states are only known to have started after the cluster was ready
+		{emr.ClusterStateTerminating, ready, false},
+	} {
+		listed = nil
+		cluster := &emr.Cluster{
+			Id: aws.String("j-1234"),
+			Status: &emr.ClusterStatus{
+				State: aws.String(test.state),
+				Timeline: &emr.ClusterTimeline{
+					CreationDateTime: aws.Time(created),
+					ReadyDateTime:    aws.Time(ready),
+				},
+			},
+		}
+		if stateChangeTime := emrClusterStateChangeTime(api, "us-west-2", cluster); !stateChangeTime.Equal(test.stateChangeTime) {
+			t.Errorf("%s: emrClusterStateChangeTime = %s, want %s", test.state, stateChangeTime, test.stateChangeTime)
+		}
+		if (listed != nil) != test.listed {
+			t.Errorf("%s: steps listed = %t, want %t", test.state, listed != nil, test.listed)
+		}
+	}
+}
+
+func TestEMRClusterStateChangeTimeWithoutSteps(t *testing.T) {
+	s, closeServer := testSession(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"__type": "ThrottlingException"}`, http.StatusBadRequest)
+	})
+	defer closeServer()
+
+	// a cluster whose steps cannot be listed has been in its state since it was ready
+	ready := time.Unix(1460000600, 0)
+	cluster := &emr.Cluster{
+		Id: aws.String("j-1234"),
+		Status: &emr.ClusterStatus{
+			State:    aws.String(emr.ClusterStateWaiting),
+			Timeline: &emr.ClusterTimeline{ReadyDateTime: aws.Time(ready)},
+		},
+	}
+	if stateChangeTime := emrClusterStateChangeTime(emr.New(s), "us-west-2", cluster); !stateChangeTime.Equal(ready) {
+		t.Errorf("emrClusterStateChangeTime = %s, want %s", stateChangeTime, ready)
+	}
+}
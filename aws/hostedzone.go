@@ -0,0 +1,453 @@
+package aws
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// a new hosted zone holds its SOA and NS records
+const hostedZoneDefaultRecordCount = 2
+
+// ErrHostedZoneNotEmpty is returned when terminating a HostedZone
+// with records other than its SOA and NS records
+var ErrHostedZoneNotEmpty = errors.New("HostedZone has records other than its SOA and NS records")
+
+// HostedZone is a Reapable, Filterable
+// embeds AWS API's route53.HostedZone
+// Route53 is global, so HostedZones are reported in the first configured region
+type HostedZone struct {
+	Resource
+	route53.HostedZone
+
+	// VPCs associated with a private HostedZone
+	VPCs []*route53.VPC
+	// whether any associated VPC still exists
+	VPCExists bool
+}
+
+// NewHostedZone creates a HostedZone from the AWS API's route53.HostedZone
+func NewHostedZone(region string, zone *route53.HostedZone, vpcs []*route53.VPC, vpcExists bool, tags []*route53.Tag) *HostedZone {
+	a := HostedZone{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(strings.TrimPrefix(*zone.Id, "/hostedzone/")),
+			Name:   *zone.Name,
+			Tags:   make(map[string]string),
+		},
+		HostedZone: *zone,
+		VPCs:       vpcs,
+		VPCExists:  vpcExists,
+	}
+
+	for _, tag := range tags {
+		a.Resource.Tags[*tag.Key] = *tag.Value
+	}
+
+	if a.Tagged("aws:cloudformation:stack-name") {
+		a.Dependency = true
+		a.IsInCloudformation = true
+	}
+
+	if a.Tagged(reaperTag) {
+		// restore previously tagged state
+		a.reaperState = state.NewStateWithTag(a.Tag(reaperTag))
+	} else {
+		// initial state
+		a.reaperState = state.NewState()
+	}
+
+	return &a
+}
+
+// Private returns whether the HostedZone is private to its VPCs
+func (a *HostedZone) Private() bool {
+	return a.Config != nil && a.Config.PrivateZone != nil && *a.Config.PrivateZone
+}
+
+// RecordCount returns the number of record sets in the HostedZone, including its SOA and NS records
+func (a *HostedZone) RecordCount() int64 {
+	if a.ResourceRecordSetCount == nil {
+		return 0
+	}
+	return *a.ResourceRecordSetCount
+}
+
+// Empty returns whether the HostedZone holds only its SOA and NS records
+func (a *HostedZone) Empty() bool {
+	return a.RecordCount() <= hostedZoneDefaultRecordCount
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *HostedZone) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableHostedZoneEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *HostedZone) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableHostedZoneEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *HostedZone) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableHostedZoneEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *HostedZone) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableHostedZoneEventHTMLShort)
+	return
+}
+
+type hostedZoneEventData struct {
+	Config        *Config
+	HostedZone    *HostedZone
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+	IgnoreLink1   string
+	IgnoreLink3   string
+	IgnoreLink7   string
+}
+
+func (a *HostedZone) getTemplateData() (interface{}, error) {
+	ignore1, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(1*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore3, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(3*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore7, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hostedZoneEventData{
+		Config:        config,
+		HostedZone:    a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+		IgnoreLink1:   ignore1,
+		IgnoreLink3:   ignore3,
+		IgnoreLink7:   ignore7,
+	}, nil
+}
+
+const reapableHostedZoneEventHTML = `
+<html>
+<body>
+	<p>{{ if .HostedZone.Private }}Private{{ else }}Public{{ end }} HostedZone <a href="{{ .HostedZone.AWSConsoleURL }}">{{ .HostedZone.Resource.Name }} ({{ .HostedZone.ID }})</a> is scheduled to be deleted.</p>
+
+	<p>
+		It holds {{ .HostedZone.RecordCount }} record sets. {{ if .HostedZone.Empty }}It only holds its SOA and NS records.{{ else }}It will not be deleted until its records other than its SOA and NS records are deleted.{{ end }}
+		{{ if .HostedZone.Private }}{{ if .HostedZone.VPCExists }}It is associated with {{ len .HostedZone.VPCs }} VPCs.{{ else }}None of the VPCs it is associated with exist.{{ end }}{{ end }}
+	</p>
+
+	<p>
+		You can ignore this message and your HostedZone will advance to the next state after <strong>{{.HostedZone.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>. If you do not take action it will be deleted!
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+			<li><a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a></li>
+			<li><a href="{{ .IgnoreLink3 }}">Ignore it for 3 more days</a></li>
+			<li><a href="{{ .IgnoreLink7}}">Ignore it for 7 more days</a></li>
+		</ul>
+	</p>
+
+	<p>
+		If you want the Reaper to ignore this HostedZone tag it with {{ .Config.WhitelistTag }} with any value, or click <a href="{{ .WhitelistLink }}">here</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableHostedZoneEventHTMLShort = `
+<html>
+<body>
+	<p>HostedZone <a href="{{ .HostedZone.AWSConsoleURL }}">{{ .HostedZone.Resource.Name }}</a> ({{ .HostedZone.RecordCount }} record sets) is scheduled to be deleted after <strong>{{.HostedZone.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a>,
+		<a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a>,
+		<a href="{{ .IgnoreLink3 }}">3 days</a>,
+		<a href="{{ .IgnoreLink7}}"> 7 days</a>, or
+		<a href="{{ .WhitelistLink }}">Whitelist</a> it.
+	</p>
+</body>
+</html>
+`
+
+const reapableHostedZoneEventTextShort = `%%%
+HostedZone [{{.HostedZone.Resource.Name}}]({{.HostedZone.AWSConsoleURL}}) ({{.HostedZone.RecordCount}} record sets).{{if .HostedZone.Owned}} Owned by {{.HostedZone.Owner}}.{{end}}\n
+[Whitelist]({{ .WhitelistLink }}) or [Delete]({{ .TerminateLink }}) this HostedZone.
+%%%`
+
+const reapableHostedZoneEventText = `%%%
+Reaper has discovered a HostedZone qualified as reapable: [{{.HostedZone.Resource.Name}}]({{.HostedZone.AWSConsoleURL}}) ({{.HostedZone.ID}}).\n
+{{if .HostedZone.Owned}}Owned by {{.HostedZone.Owner}}.\n{{end}}
+{{if .HostedZone.Private}}Private{{else}}Public{{end}}, {{.HostedZone.RecordCount}} record sets.\n
+{{ if .HostedZone.AWSConsoleURL}}{{.HostedZone.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.HostedZone.AWSConsoleURL}})\n
+[Whitelist]({{ .WhitelistLink }}) this HostedZone.
+[Delete]({{ .TerminateLink }}) this HostedZone.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *HostedZone) Save(s *state.State) (bool, error) {
+	log.Info("Saving %s", a.ReapableDescriptionTiny())
+	return tagHostedZone(a.ID(), reaperTag, s.String())
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *HostedZone) Unsave() (bool, error) {
+	log.Info("Unsaving %s", a.ReapableDescriptionTiny())
+	return untagHostedZone(a.ID(), reaperTag)
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+func (a *HostedZone) Whitelist() (bool, error) {
+	log.Info("Whitelisting HostedZone %s", a.ReapableDescriptionTiny())
+	return tagHostedZone(a.ID(), config.WhitelistTag, "true")
+}
+
+func untagHostedZone(id reapable.ID, key string) (bool, error) {
+	api := route53.New(sess, aws.NewConfig().WithRegion("us-east-1"))
+	_, err := api.ChangeTagsForResource(&route53.ChangeTagsForResourceInput{
+		ResourceId:    aws.String(id.String()),
+		ResourceType:  aws.String(route53.TagResourceTypeHostedzone),
+		RemoveTagKeys: []*string{aws.String(key)},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func tagHostedZone(id reapable.ID, key, value string) (bool, error) {
+	api := route53.New(sess, aws.NewConfig().WithRegion("us-east-1"))
+	_, err := api.ChangeTagsForResource(&route53.ChangeTagsForResourceInput{
+		ResourceId:   aws.String(id.String()),
+		ResourceType: aws.String(route53.TagResourceTypeHostedzone),
+		AddTags: []*route53.Tag{
+			&route53.Tag{
+				Key:   aws.String(key),
+				Value: aws.String(value),
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *HostedZone) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "Private":
+		if b, err := filter.BoolValue(0); err == nil && a.Private() == b {
+			matched = true
+		}
+	case "Empty":
+		if b, err := filter.BoolValue(0); err == nil && a.Empty() == b {
+			matched = true
+		}
+	case "VPCExists":
+		if b, err := filter.BoolValue(0); err == nil && a.VPCExists == b {
+			matched = true
+		}
+	case "RecordCountGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.RecordCount() > i {
+			matched = true
+		}
+	case "RecordCountLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.RecordCount() < i {
+			matched = true
+		}
+	case "RecordCountEqualTo":
+		if i, err := filter.Int64Value(0); err == nil && a.RecordCount() == i {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Resource.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Resource.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Resource.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Resource.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering HostedZones.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *HostedZone) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://console.aws.amazon.com/route53/home#resource-record-sets:%s",
+		url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// hasRecords returns whether the HostedZone holds records other than its SOA and NS records
+// the record count from ListHostedZones can be stale, so the records are listed again
+func (a *HostedZone) hasRecords(api *route53.Route53) (bool, error) {
+	hasRecords := false
+	err := api.ListResourceRecordSetsPages(&route53.ListResourceRecordSetsInput{
+		HostedZoneId: aws.String(a.ID().String()),
+	}, func(resp *route53.ListResourceRecordSetsOutput, lastPage bool) bool {
+		if hasRecordsBesidesDefault(*a.HostedZone.Name, resp.ResourceRecordSets) {
+			hasRecords = true
+			return false
+		}
+		// if we are at the last page, we should not continue
+		// the return value of this func is "shouldContinue"
+		return !lastPage
+	})
+	return hasRecords, err
+}
+
+// hasRecordsBesidesDefault returns whether records hold anything other than the SOA and NS records
+// that Route53 creates at the apex of the zone named zoneName
+func hasRecordsBesidesDefault(zoneName string, records []*route53.ResourceRecordSet) bool {
+	for _, record := range records {
+		if *record.Name == zoneName && (*record.Type == route53.RRTypeSoa || *record.Type == route53.RRTypeNs) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+// only HostedZones without records other than their SOA and NS records are deleted
+func (a *HostedZone) Terminate() (bool, error) {
+	log.Info("Terminating HostedZone %s", a.ReapableDescriptionTiny())
+	api := route53.New(sess, aws.NewConfig().WithRegion("us-east-1"))
+	hasRecords, err := a.hasRecords(api)
+	if err != nil {
+		log.Error("could not list the records of HostedZone %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	if hasRecords {
+		log.Error("could not terminate HostedZone %s: %s", a.ReapableDescriptionTiny(), ErrHostedZoneNotEmpty.Error())
+		return false, ErrHostedZoneNotEmpty
+	}
+
+	_, err = api.DeleteHostedZone(&route53.DeleteHostedZoneInput{
+		Id: aws.String(a.ID().String()),
+	})
+	if err != nil {
+		log.Error("could not delete HostedZone %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because hosted zones cannot be stopped
+func (a *HostedZone) Stop() (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,77 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func record(name, recordType string) *route53.ResourceRecordSet {
+	return &route53.ResourceRecordSet{Name: aws.String(name), Type: aws.String(recordType)}
+}
+
+func TestHasRecordsBesidesDefault(t *testing.T) {
+	for _, test := range []struct {
+		description string
+		records     []*route53.ResourceRecordSet
+		hasRecords  bool
+	}{
+		{"no records", nil, false},
+		{"apex SOA and NS", []*route53.ResourceRecordSet{
+			record("example.com.", route53.RRTypeSoa),
+			record("example.com.", route53.RRTypeNs),
+		}, false},
+		{"apex A", []*route53.ResourceRecordSet{
+			record("example.com.", route53.RRTypeSoa),
+			record("example.com.", route53.RRTypeNs),
+			record("example.com.", route53.RRTypeA),
+		}, true},
+		// delegations are NS records below the apex
+		{"delegated subdomain", []*route53.ResourceRecordSet{
+			record("example.com.", route53.RRTypeNs),
+			record("sub.example.com.", route53.RRTypeNs),
+		}, true},
+		{"subdomain CNAME", []*route53.ResourceRecordSet{
+			record("www.example.com.", route53.RRTypeCname),
+		}, true},
+	} {
+		if hasRecords := hasRecordsBesidesDefault("example.com.", test.records); hasRecords != test.hasRecords {
+			t.Errorf("%s: hasRecordsBesidesDefault = %t, want %t", test.description, hasRecords, test.hasRecords)
+		}
+	}
+}
+
+func TestHostedZoneFilter(t *testing.T) {
+	a := NewHostedZone("us-east-1", &route53.HostedZone{
+		Id:                     aws.String("/hostedzone/Z1234"),
+		Name:                   aws.String("internal.example.com."),
+		Config:                 &route53.HostedZoneConfig{PrivateZone: aws.Bool(true)},
+		ResourceRecordSetCount: aws.Int64(2),
+	}, []*route53.VPC{{VPCId: aws.String("vpc-1"), VPCRegion: aws.String("us-west-2")}}, false, nil)
+
+	if a.ID() != "Z1234" {
+		t.Errorf("ID() = %s, want Z1234", a.ID())
+	}
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"Private", []string{"true"}, true},
+		{"Empty", []string{"true"}, true},
+		{"VPCExists", []string{"false"}, true},
+		{"RecordCountGreaterThan", []string{"2"}, false},
+		{"RecordCountLessThan", []string{"3"}, true},
+		{"RecordCountEqualTo", []string{"2"}, true},
+		{"Named", []string{"internal.example.com."}, true},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
@@ -10,9 +10,9 @@ import (
 )
 
 // MakeTerminateLink creates a tokenized link for terminating
-func makeTerminateLink(region reapable.Region, id reapable.ID, tokenSecret, apiURL string) (string, error) {
+func makeTerminateLink(a reapable.Reapable, tokenSecret, apiURL string) (string, error) {
 	term, err := token.Tokenize(tokenSecret,
-		token.NewTerminateJob(region.String(), id.String()))
+		token.NewTerminateJob(a.Region().String(), reapable.KindOf(a).String(), a.ID().String()))
 
 	if err != nil {
 		return "", err
@@ -22,10 +22,10 @@ func makeTerminateLink(region reapable.Region, id reapable.ID, tokenSecret, apiU
 }
 
 // MakeIgnoreLink creates a tokenized link for ignoring for a duration
-func makeIgnoreLink(region reapable.Region, id reapable.ID, tokenSecret, apiURL string,
+func makeIgnoreLink(a reapable.Reapable, tokenSecret, apiURL string,
 	duration time.Duration) (string, error) {
 	delay, err := token.Tokenize(tokenSecret,
-		token.NewDelayJob(region.String(), id.String(),
+		token.NewDelayJob(a.Region().String(), reapable.KindOf(a).String(), a.ID().String(),
 			duration))
 
 	if err != nil {
@@ -38,9 +38,9 @@ func makeIgnoreLink(region reapable.Region, id reapable.ID, tokenSecret, apiURL
 }
 
 // MakeWhitelistLink creates a tokenized link for whitelisting
-func makeWhitelistLink(region reapable.Region, id reapable.ID, tokenSecret, apiURL string) (string, error) {
+func makeWhitelistLink(a reapable.Reapable, tokenSecret, apiURL string) (string, error) {
 	whitelist, err := token.Tokenize(tokenSecret,
-		token.NewWhitelistJob(region.String(), id.String()))
+		token.NewWhitelistJob(a.Region().String(), reapable.KindOf(a).String(), a.ID().String()))
 	if err != nil {
 		log.Error("Error creating whitelist link: ", err)
 		return "", err
@@ -50,9 +50,9 @@ func makeWhitelistLink(region reapable.Region, id reapable.ID, tokenSecret, apiU
 }
 
 // MakeStopLink creates a tokenized link for stopping
-func makeStopLink(region reapable.Region, id reapable.ID, tokenSecret, apiURL string) (string, error) {
+func makeStopLink(a reapable.Reapable, tokenSecret, apiURL string) (string, error) {
 	stop, err := token.Tokenize(tokenSecret,
-		token.NewStopJob(region.String(), id.String()))
+		token.NewStopJob(a.Region().String(), reapable.KindOf(a).String(), a.ID().String()))
 	if err != nil {
 		log.Error("Error creating ScaleToZero link: ", err)
 		return "", err
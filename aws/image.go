@@ -0,0 +1,389 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// Image is a Reapable, Filterable
+// embeds AWS API's ec2.Image
+type Image struct {
+	Resource
+	ec2.Image
+
+	// ec2.Image.CreationDate is a string
+	CreationTime time.Time
+	// most recent LaunchTime of an instance launched from the Image
+	LastLaunchTime time.Time
+
+	// EBS snapshots that back the Image
+	SnapshotIDs []reapable.ID
+}
+
+// NewImage creates an Image from the AWS API's ec2.Image
+func NewImage(region string, image *ec2.Image) *Image {
+	a := Image{
+		Resource: Resource{
+			id:     reapable.ID(*image.ImageId),
+			region: reapable.Region(region),
+			Tags:   make(map[string]string),
+		},
+		Image: *image,
+	}
+
+	if image.Name != nil {
+		a.Resource.Name = *image.Name
+	}
+
+	if image.CreationDate != nil {
+		t, err := time.Parse(time.RFC3339, *image.CreationDate)
+		if err != nil {
+			log.Error("could not parse CreationDate %s for Image %s", *image.CreationDate, *image.ImageId)
+		}
+		a.CreationTime = t
+	}
+
+	for _, mapping := range image.BlockDeviceMappings {
+		if mapping.Ebs != nil && mapping.Ebs.SnapshotId != nil {
+			a.SnapshotIDs = append(a.SnapshotIDs, reapable.ID(*mapping.Ebs.SnapshotId))
+		}
+	}
+
+	for _, tag := range image.Tags {
+		a.Resource.Tags[*tag.Key] = *tag.Value
+	}
+
+	if a.Tagged("aws:cloudformation:stack-name") {
+		a.Dependency = true
+		a.IsInCloudformation = true
+	}
+
+	if a.Tagged(reaperTag) {
+		// restore previously tagged state
+		a.reaperState = state.NewStateWithTag(a.Tag(reaperTag))
+	} else {
+		// initial state
+		a.reaperState = state.NewState()
+	}
+
+	return &a
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *Image) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableImageEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *Image) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableImageEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *Image) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableImageEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *Image) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableImageEventHTMLShort)
+	return
+}
+
+type imageEventData struct {
+	Config        *Config
+	Image         *Image
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+	IgnoreLink1   string
+	IgnoreLink3   string
+	IgnoreLink7   string
+}
+
+func (a *Image) getTemplateData() (interface{}, error) {
+	ignore1, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(1*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore3, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(3*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore7, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &imageEventData{
+		Config:        config,
+		Image:         a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+		IgnoreLink1:   ignore1,
+		IgnoreLink3:   ignore3,
+		IgnoreLink7:   ignore7,
+	}, nil
+}
+
+const reapableImageEventHTML = `
+<html>
+<body>
+	<p>AMI <a href="{{ .Image.AWSConsoleURL }}">{{ if .Image.Resource.Name }}"{{.Image.Resource.Name}}" {{ end }}{{.Image.ID}} in {{.Image.Region}}</a> is scheduled to be deregistered.</p>
+
+	<p>
+		{{ if .Image.LastLaunchTime.IsZero }}No existing instance was launched from this AMI.{{ else }}The most recent instance launched from this AMI was launched at {{.Image.LastLaunchTime.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}.{{ end }}
+		{{ if .Image.SnapshotIDs }}Its backing snapshots ({{ range $i, $id := .Image.SnapshotIDs }}{{ if $i }}, {{ end }}{{ $id }}{{ end }}) will be deleted with it.{{ end }}
+	</p>
+
+	<p>
+		You can ignore this message and your AMI will advance to the next state after <strong>{{.Image.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>. If you do not take action it will be deregistered!
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Deregister it now</a></li>
+			<li><a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a></li>
+			<li><a href="{{ .IgnoreLink3 }}">Ignore it for 3 more days</a></li>
+			<li><a href="{{ .IgnoreLink7}}">Ignore it for 7 more days</a></li>
+		</ul>
+	</p>
+
+	<p>
+		If you want the Reaper to ignore this AMI tag it with {{ .Config.WhitelistTag }} with any value, or click <a href="{{ .WhitelistLink }}">here</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableImageEventHTMLShort = `
+<html>
+<body>
+	<p>AMI <a href="{{ .Image.AWSConsoleURL }}">{{ if .Image.Resource.Name }}"{{.Image.Resource.Name}}" {{ end }}{{.Image.ID}}</a> in {{.Image.Region}} is scheduled to be deregistered after <strong>{{.Image.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>.
+		<br />
+		<a href="{{ .TerminateLink }}">Deregister</a>,
+		<a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a>,
+		<a href="{{ .IgnoreLink3 }}">3 days</a>,
+		<a href="{{ .IgnoreLink7}}"> 7 days</a>, or
+		<a href="{{ .WhitelistLink }}">Whitelist</a> it.
+	</p>
+</body>
+</html>
+`
+
+const reapableImageEventTextShort = `%%%
+AMI {{if .Image.Resource.Name}}"{{.Image.Resource.Name}}" {{end}}[{{.Image.ID}}]({{.Image.AWSConsoleURL}}) in region: [{{.Image.Region}}](https://{{.Image.Region}}.console.aws.amazon.com/ec2/v2/home?region={{.Image.Region}}).{{if .Image.Owned}} Owned by {{.Image.Owner}}.\n{{end}}
+[Whitelist]({{ .WhitelistLink }}) or [Deregister]({{ .TerminateLink }}) this AMI.
+%%%`
+
+const reapableImageEventText = `%%%
+Reaper has discovered an AMI qualified as reapable: {{if .Image.Resource.Name}}"{{.Image.Resource.Name}}" {{end}}[{{.Image.ID}}]({{.Image.AWSConsoleURL}}) in region: [{{.Image.Region}}](https://{{.Image.Region}}.console.aws.amazon.com/ec2/v2/home?region={{.Image.Region}}).\n
+{{if .Image.Owned}}Owned by {{.Image.Owner}}.\n{{end}}
+State: {{.Image.State}}.\n
+{{ if not .Image.LastLaunchTime.IsZero }}Last launched: {{.Image.LastLaunchTime.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}.\n{{end}}
+{{ if .Image.AWSConsoleURL}}{{.Image.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.Image.AWSConsoleURL}})\n
+[Whitelist]({{ .WhitelistLink }}) this AMI.
+[Deregister]({{ .TerminateLink }}) this AMI.
+%%%`
+
+// Filter is part of the filter.Filterable interface
+func (a *Image) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "State":
+		// one of:
+		// pending
+		// available
+		// invalid
+		// deregistered
+		// transient
+		// failed
+		// error
+		if a.State != nil && *a.State == filter.Arguments[0] {
+			matched = true
+		}
+	case "NameMatches":
+		if m, err := regexp.MatchString(filter.Arguments[0], a.Resource.Name); err == nil && m {
+			matched = true
+		}
+	case "NotNameMatches":
+		if m, err := regexp.MatchString(filter.Arguments[0], a.Resource.Name); err == nil && !m {
+			matched = true
+		}
+	case "CreatedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && !a.CreationTime.IsZero() && time.Since(a.CreationTime) < d {
+			matched = true
+		}
+	case "CreatedNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && !a.CreationTime.IsZero() && time.Since(a.CreationTime) > d {
+			matched = true
+		}
+	case "LaunchedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && !a.LastLaunchTime.IsZero() && time.Since(a.LastLaunchTime) < d {
+			matched = true
+		}
+	case "NotLaunchedInTheLast":
+		// images that were never launched were not launched in any duration
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && (a.LastLaunchTime.IsZero() || time.Since(a.LastLaunchTime) > d) {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Resource.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Resource.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Resource.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Resource.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering Images.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *Image) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/ec2/v2/home?region=%s#Images:visibility=owned-by-me;imageId=%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+// Terminate deregisters the Image and deletes its backing snapshots
+func (a *Image) Terminate() (bool, error) {
+	log.Info("Terminating Image %s", a.ReapableDescriptionTiny())
+	api := ec2.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.DeregisterImage(&ec2.DeregisterImageInput{
+		ImageId: aws.String(a.ID().String()),
+	})
+	if err != nil {
+		log.Error("could not deregister Image %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+
+	// snapshots can only be deleted once the image is deregistered
+	errorStrings := []string{}
+	for _, snapshotID := range a.SnapshotIDs {
+		_, err := api.DeleteSnapshot(&ec2.DeleteSnapshotInput{
+			SnapshotId: aws.String(snapshotID.String()),
+		})
+		if err != nil {
+			errorStrings = append(errorStrings, fmt.Sprintf("%s: %s", snapshotID, err.Error()))
+		}
+	}
+	if len(errorStrings) > 0 {
+		return false, fmt.Errorf("Image %s was deregistered, but its snapshots could not be deleted: %s",
+			a.ReapableDescriptionTiny(), strings.Join(errorStrings, ", "))
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because there is no concept of stopping an image
+func (a *Image) Stop() (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,49 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestImageFilter(t *testing.T) {
+	a := NewImage("us-west-2", &ec2.Image{
+		ImageId:      aws.String("ami-1234"),
+		Name:         aws.String("web-2016"),
+		State:        aws.String("available"),
+		CreationDate: aws.String(time.Now().Add(-48 * time.Hour).Format(time.RFC3339)),
+		Tags: []*ec2.Tag{
+			{Key: aws.String("aws:cloudformation:stack-name"), Value: aws.String("web")},
+		},
+	})
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"State", []string{"available"}, true},
+		{"State", []string{"failed"}, false},
+		{"NameMatches", []string{"^web-"}, true},
+		{"NotNameMatches", []string{"^web-"}, false},
+		{"CreatedInTheLast", []string{"72h"}, true},
+		{"CreatedNotInTheLast", []string{"24h"}, true},
+		{"CreatedNotInTheLast", []string{"72h"}, false},
+		// never launched
+		{"LaunchedInTheLast", []string{"24h"}, false},
+		{"NotLaunchedInTheLast", []string{"24h"}, true},
+		{"InCloudformation", []string{"true"}, true},
+		{"IsDependency", []string{"true"}, true},
+		{"Region", []string{"us-east-1", "us-west-2"}, true},
+		{"NotRegion", []string{"us-west-2"}, false},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// KeyPair is a Reapable, Filterable
+// embeds AWS API's ec2.KeyPairInfo
+type KeyPair struct {
+	Resource
+	ec2.KeyPairInfo
+}
+
+// NewKeyPair creates a KeyPair from the AWS API's ec2.KeyPairInfo
+// key pairs cannot be tagged, so they always start in the initial state
+// and their notifications go to the DefaultOwner
+func NewKeyPair(region string, keyPair *ec2.KeyPairInfo) *KeyPair {
+	a := KeyPair{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*keyPair.KeyName),
+			Name:   *keyPair.KeyName,
+			Tags:   make(map[string]string),
+		},
+		KeyPairInfo: *keyPair,
+	}
+
+	// initial state
+	a.reaperState = state.NewState()
+
+	return &a
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *KeyPair) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableKeyPairEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *KeyPair) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableKeyPairEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *KeyPair) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableKeyPairEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *KeyPair) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableKeyPairEventHTMLShort)
+	return
+}
+
+type keyPairEventData struct {
+	Config        *Config
+	KeyPair       *KeyPair
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+}
+
+func (a *KeyPair) getTemplateData() (interface{}, error) {
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyPairEventData{
+		Config:        config,
+		KeyPair:       a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+	}, nil
+}
+
+const reapableKeyPairEventHTML = `
+<html>
+<body>
+	<p>KeyPair <a href="{{ .KeyPair.AWSConsoleURL }}">{{ .KeyPair.ID }} in {{.KeyPair.Region}}</a> is not used by any Instance or LaunchConfiguration and qualifies as reapable.</p>
+
+	<p>
+		Its fingerprint is {{ .KeyPair.KeyFingerprint }}. Key pairs cannot be tagged, so this notification is sent to the default owner.
+	</p>
+
+	<p>
+		Key pairs cannot be tagged, so the Reaper cannot keep track of this KeyPair: you will be notified again on every run, and it will not be deleted unless you delete it below.
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+		</ul>
+	</p>
+</body>
+</html>
+`
+
+const reapableKeyPairEventHTMLShort = `
+<html>
+<body>
+	<p>KeyPair <a href="{{ .KeyPair.AWSConsoleURL }}">{{ .KeyPair.ID }}</a> in {{.KeyPair.Region}} qualifies as reapable, and will not be deleted unless you delete it.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableKeyPairEventTextShort = `%%%
+KeyPair [{{.KeyPair.ID}}]({{.KeyPair.AWSConsoleURL}}) in region: [{{.KeyPair.Region}}](https://{{.KeyPair.Region}}.console.aws.amazon.com/ec2/v2/home?region={{.KeyPair.Region}}).{{if .KeyPair.Owned}} Owned by {{.KeyPair.Owner}}.{{end}}\n
+[Delete]({{ .TerminateLink }}) this KeyPair.
+%%%`
+
+const reapableKeyPairEventText = `%%%
+Reaper has discovered a KeyPair qualified as reapable: [{{.KeyPair.ID}}]({{.KeyPair.AWSConsoleURL}}) in region: [{{.KeyPair.Region}}](https://{{.KeyPair.Region}}.console.aws.amazon.com/ec2/v2/home?region={{.KeyPair.Region}}).\n
+{{if .KeyPair.Owned}}Owned by {{.KeyPair.Owner}}.\n{{end}}
+Fingerprint: {{.KeyPair.KeyFingerprint}}.\n
+{{ if .KeyPair.AWSConsoleURL}}{{.KeyPair.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.KeyPair.AWSConsoleURL}})\n
+[Delete]({{ .TerminateLink }}) this KeyPair.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because key pairs cannot be tagged
+func (a *KeyPair) Save(s *state.State) (bool, error) {
+	return false, nil
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because key pairs cannot be tagged
+func (a *KeyPair) Unsave() (bool, error) {
+	return false, nil
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+// no op because key pairs cannot be tagged
+func (a *KeyPair) Whitelist() (bool, error) {
+	return false, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *KeyPair) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "Fingerprint":
+		if a.KeyFingerprint != nil && *a.KeyFingerprint == filter.Arguments[0] {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering KeyPairs.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *KeyPair) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/ec2/v2/home?region=%s#KeyPairs:keyName=%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+func (a *KeyPair) Terminate() (bool, error) {
+	log.Info("Terminating KeyPair %s", a.ReapableDescriptionTiny())
+	api := ec2.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.DeleteKeyPair(&ec2.DeleteKeyPairInput{
+		KeyName: aws.String(a.ID().String()),
+	})
+	if err != nil {
+		log.Error("could not delete KeyPair %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because key pairs cannot be stopped
+func (a *KeyPair) Stop() (bool, error) {
+	return false, nil
+}
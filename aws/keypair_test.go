@@ -0,0 +1,36 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestKeyPairFilter(t *testing.T) {
+	a := NewKeyPair("us-west-2", &ec2.KeyPairInfo{
+		KeyName:        aws.String("deploy"),
+		KeyFingerprint: aws.String("1f:51:ae:28:bf:89:e9:d8:1f:25:5d:37:2d:7d:b8:ca:9f:f5:f1:6f"),
+	})
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"Fingerprint", []string{"1f:51:ae:28:bf:89:e9:d8:1f:25:5d:37:2d:7d:b8:ca:9f:f5:f1:6f"}, true},
+		{"Fingerprint", []string{"00:00"}, false},
+		{"Named", []string{"deploy"}, true},
+		{"NameContains", []string{"dep"}, true},
+		// key pairs cannot be tagged
+		{"Tagged", []string{"Owner"}, false},
+		{"ReaperState", []string{"InitialState"}, true},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
@@ -0,0 +1,374 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// lambdaLastModifiedFormat is the layout of lambda.FunctionConfiguration's LastModified
+const lambdaLastModifiedFormat = "2006-01-02T15:04:05.999-0700"
+
+// LambdaFunction is a Reapable, Filterable
+// embeds AWS API's lambda.FunctionConfiguration
+type LambdaFunction struct {
+	Resource
+	lambda.FunctionConfiguration
+
+	LastModifiedTime    time.Time
+	EventSourceMappings int
+	SecurityGroupIDs    []reapable.ID
+
+	// invocation sums from CloudWatch, by duration
+	invocations map[time.Duration]float64
+}
+
+// NewLambdaFunction creates a LambdaFunction from the AWS API's lambda.FunctionConfiguration
+// functions cannot be tagged, so they always start in the initial state
+// functions with event source mappings are dependencies
+func NewLambdaFunction(region string, function *lambda.FunctionConfiguration, eventSourceMappings int) *LambdaFunction {
+	a := LambdaFunction{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*function.FunctionName),
+			Name:   *function.FunctionName,
+			Tags:   make(map[string]string),
+		},
+		FunctionConfiguration: *function,
+		EventSourceMappings:   eventSourceMappings,
+		invocations:           make(map[time.Duration]float64),
+	}
+
+	if function.LastModified != nil {
+		t, err := time.Parse(lambdaLastModifiedFormat, *function.LastModified)
+		if err != nil {
+			log.Error("Error parsing LastModified of LambdaFunction %s: %s", a.ID(), err.Error())
+		} else {
+			a.LastModifiedTime = t
+		}
+	}
+
+	if function.VpcConfig != nil {
+		for _, groupID := range function.VpcConfig.SecurityGroupIds {
+			a.SecurityGroupIDs = append(a.SecurityGroupIDs, reapable.ID(*groupID))
+		}
+	}
+
+	if eventSourceMappings > 0 {
+		a.Dependency = true
+	}
+
+	// initial state
+	a.reaperState = state.NewState()
+
+	return &a
+}
+
+// Invocations returns how many times the LambdaFunction was invoked in the last d
+// from the AWS/Lambda Invocations metric in CloudWatch
+func (a *LambdaFunction) Invocations(d time.Duration) (float64, error) {
+	if sum, ok := a.invocations[d]; ok {
+		return sum, nil
+	}
+
+	sum, err := metricSum(a.Region(), "AWS/Lambda", "Invocations", "FunctionName", a.ID().String(), d)
+	if err != nil {
+		return 0, err
+	}
+	a.invocations[d] = sum
+	return sum, nil
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *LambdaFunction) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableLambdaFunctionEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *LambdaFunction) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableLambdaFunctionEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *LambdaFunction) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableLambdaFunctionEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *LambdaFunction) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableLambdaFunctionEventHTMLShort)
+	return
+}
+
+type lambdaFunctionEventData struct {
+	Config         *Config
+	LambdaFunction *LambdaFunction
+	TerminateLink  string
+	StopLink       string
+	WhitelistLink  string
+}
+
+func (a *LambdaFunction) getTemplateData() (interface{}, error) {
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lambdaFunctionEventData{
+		Config:         config,
+		LambdaFunction: a,
+		TerminateLink:  terminate,
+		StopLink:       stop,
+		WhitelistLink:  whitelist,
+	}, nil
+}
+
+const reapableLambdaFunctionEventHTML = `
+<html>
+<body>
+	<p>LambdaFunction <a href="{{ .LambdaFunction.AWSConsoleURL }}">{{ .LambdaFunction.ID }} in {{.LambdaFunction.Region}}</a> qualifies as reapable.</p>
+
+	<p>
+		It runs {{ .LambdaFunction.Runtime }} and was last modified {{ .LambdaFunction.LastModifiedTime.UTC.Format "Jan 2, 2006 at 3:04pm (MST)" }}.
+	</p>
+
+	<p>
+		Lambda functions cannot be tagged, so the Reaper cannot keep track of this LambdaFunction: you will be notified again on every run, and it will not be deleted unless you delete it below.
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+		</ul>
+	</p>
+</body>
+</html>
+`
+
+const reapableLambdaFunctionEventHTMLShort = `
+<html>
+<body>
+	<p>LambdaFunction <a href="{{ .LambdaFunction.AWSConsoleURL }}">{{ .LambdaFunction.ID }}</a> in {{.LambdaFunction.Region}} qualifies as reapable, and will not be deleted unless you delete it.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableLambdaFunctionEventTextShort = `%%%
+LambdaFunction [{{.LambdaFunction.ID}}]({{.LambdaFunction.AWSConsoleURL}}) in region: [{{.LambdaFunction.Region}}](https://{{.LambdaFunction.Region}}.console.aws.amazon.com/lambda/home?region={{.LambdaFunction.Region}}).{{if .LambdaFunction.Owned}} Owned by {{.LambdaFunction.Owner}}.{{end}}\n
+[Delete]({{ .TerminateLink }}) this LambdaFunction.
+%%%`
+
+const reapableLambdaFunctionEventText = `%%%
+Reaper has discovered a LambdaFunction qualified as reapable: [{{.LambdaFunction.ID}}]({{.LambdaFunction.AWSConsoleURL}}) in region: [{{.LambdaFunction.Region}}](https://{{.LambdaFunction.Region}}.console.aws.amazon.com/lambda/home?region={{.LambdaFunction.Region}}).\n
+{{if .LambdaFunction.Owned}}Owned by {{.LambdaFunction.Owner}}.\n{{end}}
+Runtime: {{.LambdaFunction.Runtime}}, code size: {{.LambdaFunction.CodeSize}} bytes, last modified {{.LambdaFunction.LastModifiedTime.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}.\n
+{{ if .LambdaFunction.AWSConsoleURL}}{{.LambdaFunction.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.LambdaFunction.AWSConsoleURL}})\n
+[Delete]({{ .TerminateLink }}) this LambdaFunction.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because lambda functions cannot be tagged
+func (a *LambdaFunction) Save(s *state.State) (bool, error) {
+	return false, nil
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because lambda functions cannot be tagged
+func (a *LambdaFunction) Unsave() (bool, error) {
+	return false, nil
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+// no op because lambda functions cannot be tagged
+func (a *LambdaFunction) Whitelist() (bool, error) {
+	return false, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *LambdaFunction) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "Runtime":
+		if a.Runtime != nil && *a.Runtime == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotRuntime":
+		if a.Runtime != nil && *a.Runtime != filter.Arguments[0] {
+			matched = true
+		}
+	case "CodeSizeGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.CodeSize != nil && *a.CodeSize > i {
+			matched = true
+		}
+	case "CodeSizeLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.CodeSize != nil && *a.CodeSize < i {
+			matched = true
+		}
+	case "LastModifiedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && !a.LastModifiedTime.IsZero() && time.Since(a.LastModifiedTime) < d {
+			matched = true
+		}
+	case "LastModifiedNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && !a.LastModifiedTime.IsZero() && time.Since(a.LastModifiedTime) > d {
+			matched = true
+		}
+	case "InvokedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err != nil {
+			break
+		}
+		invocations, err := a.Invocations(d)
+		if err != nil {
+			log.Error("Error getting invocations of LambdaFunction %s: %s", a.ReapableDescriptionTiny(), err.Error())
+		} else if invocations > 0 {
+			matched = true
+		}
+	case "NotInvokedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err != nil {
+			break
+		}
+		invocations, err := a.Invocations(d)
+		if err != nil {
+			log.Error("Error getting invocations of LambdaFunction %s: %s", a.ReapableDescriptionTiny(), err.Error())
+		} else if invocations == 0 {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering LambdaFunctions.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *LambdaFunction) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/lambda/home?region=%s#/functions/%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+func (a *LambdaFunction) Terminate() (bool, error) {
+	log.Info("Terminating LambdaFunction %s", a.ReapableDescriptionTiny())
+	api := lambda.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.DeleteFunction(&lambda.DeleteFunctionInput{
+		FunctionName: aws.String(a.ID().String()),
+	})
+	if err != nil {
+		log.Error("could not delete LambdaFunction %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because lambda functions cannot be stopped
+func (a *LambdaFunction) Stop() (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,47 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestLambdaFunctionFilter(t *testing.T) {
+	a := NewLambdaFunction("us-west-2", &lambda.FunctionConfiguration{
+		FunctionName: aws.String("resize"),
+		Runtime:      aws.String("nodejs4.3"),
+		CodeSize:     aws.Int64(1024),
+		LastModified: aws.String(time.Now().Add(-48 * time.Hour).Format(lambdaLastModifiedFormat)),
+	}, 1)
+	// avoids getting invocations from CloudWatch
+	a.invocations[24*time.Hour] = 0
+	a.invocations[72*time.Hour] = 3
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"Runtime", []string{"nodejs4.3"}, true},
+		{"NotRuntime", []string{"python2.7"}, true},
+		{"CodeSizeGreaterThan", []string{"1024"}, false},
+		{"CodeSizeLessThan", []string{"2048"}, true},
+		{"LastModifiedInTheLast", []string{"24h"}, false},
+		{"LastModifiedNotInTheLast", []string{"24h"}, true},
+		{"InvokedInTheLast", []string{"24h"}, false},
+		{"NotInvokedInTheLast", []string{"24h"}, true},
+		{"InvokedInTheLast", []string{"72h"}, true},
+		{"InvokedInTheLast", []string{"a while"}, false},
+		// event source mappings make it a dependency
+		{"IsDependency", []string{"true"}, true},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// LaunchConfiguration is a Reapable, Filterable
+// embeds AWS API's autoscaling.LaunchConfiguration
+type LaunchConfiguration struct {
+	Resource
+	autoscaling.LaunchConfiguration
+}
+
+// NewLaunchConfiguration creates a LaunchConfiguration from the AWS API's autoscaling.LaunchConfiguration
+// launch configurations cannot be tagged, so they always start in the initial state
+func NewLaunchConfiguration(region string, lc *autoscaling.LaunchConfiguration) *LaunchConfiguration {
+	a := LaunchConfiguration{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*lc.LaunchConfigurationName),
+			Name:   *lc.LaunchConfigurationName,
+			Tags:   make(map[string]string),
+		},
+		LaunchConfiguration: *lc,
+	}
+
+	// initial state
+	a.reaperState = state.NewState()
+
+	return &a
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *LaunchConfiguration) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableLaunchConfigurationEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *LaunchConfiguration) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableLaunchConfigurationEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *LaunchConfiguration) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableLaunchConfigurationEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *LaunchConfiguration) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableLaunchConfigurationEventHTMLShort)
+	return
+}
+
+type launchConfigurationEventData struct {
+	Config              *Config
+	LaunchConfiguration *LaunchConfiguration
+	TerminateLink       string
+	StopLink            string
+	WhitelistLink       string
+}
+
+func (a *LaunchConfiguration) getTemplateData() (interface{}, error) {
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &launchConfigurationEventData{
+		Config:              config,
+		LaunchConfiguration: a,
+		TerminateLink:       terminate,
+		StopLink:            stop,
+		WhitelistLink:       whitelist,
+	}, nil
+}
+
+const reapableLaunchConfigurationEventHTML = `
+<html>
+<body>
+	<p>LaunchConfiguration <a href="{{ .LaunchConfiguration.AWSConsoleURL }}">{{ .LaunchConfiguration.ID }} in {{.LaunchConfiguration.Region}}</a> is not used by any AutoScalingGroup and qualifies as reapable.</p>
+
+	<p>
+		It launches {{ .LaunchConfiguration.InstanceType }} instances from {{ .LaunchConfiguration.ImageId }}.
+	</p>
+
+	<p>
+		Launch configurations cannot be tagged, so the Reaper cannot keep track of this LaunchConfiguration: you will be notified again on every run, and it will not be deleted unless you delete it below.
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+		</ul>
+	</p>
+</body>
+</html>
+`
+
+const reapableLaunchConfigurationEventHTMLShort = `
+<html>
+<body>
+	<p>LaunchConfiguration <a href="{{ .LaunchConfiguration.AWSConsoleURL }}">{{ .LaunchConfiguration.ID }}</a> in {{.LaunchConfiguration.Region}} qualifies as reapable, and will not be deleted unless you delete it.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableLaunchConfigurationEventTextShort = `%%%
+LaunchConfiguration [{{.LaunchConfiguration.ID}}]({{.LaunchConfiguration.AWSConsoleURL}}) in region: [{{.LaunchConfiguration.Region}}](https://{{.LaunchConfiguration.Region}}.console.aws.amazon.com/ec2/autoscaling/home?region={{.LaunchConfiguration.Region}}).{{if .LaunchConfiguration.Owned}} Owned by {{.LaunchConfiguration.Owner}}.{{end}}\n
+[Delete]({{ .TerminateLink }}) this LaunchConfiguration.
+%%%`
+
+const reapableLaunchConfigurationEventText = `%%%
+Reaper has discovered a LaunchConfiguration qualified as reapable: [{{.LaunchConfiguration.ID}}]({{.LaunchConfiguration.AWSConsoleURL}}) in region: [{{.LaunchConfiguration.Region}}](https://{{.LaunchConfiguration.Region}}.console.aws.amazon.com/ec2/autoscaling/home?region={{.LaunchConfiguration.Region}}).\n
+{{if .LaunchConfiguration.Owned}}Owned by {{.LaunchConfiguration.Owner}}.\n{{end}}
+Instance type: {{.LaunchConfiguration.InstanceType}}, AMI: {{.LaunchConfiguration.ImageId}}.\n
+{{ if .LaunchConfiguration.AWSConsoleURL}}{{.LaunchConfiguration.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.LaunchConfiguration.AWSConsoleURL}})\n
+[Delete]({{ .TerminateLink }}) this LaunchConfiguration.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because launch configurations cannot be tagged
+func (a *LaunchConfiguration) Save(s *state.State) (bool, error) {
+	return false, nil
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because launch configurations cannot be tagged
+func (a *LaunchConfiguration) Unsave() (bool, error) {
+	return false, nil
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+// no op because launch configurations cannot be tagged
+func (a *LaunchConfiguration) Whitelist() (bool, error) {
+	return false, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *LaunchConfiguration) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "InstanceType":
+		if a.InstanceType != nil && *a.InstanceType == filter.Arguments[0] {
+			matched = true
+		}
+	case "ImageID":
+		if a.ImageId != nil && *a.ImageId == filter.Arguments[0] {
+			matched = true
+		}
+	case "CreatedTimeInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CreatedTime != nil && time.Since(*a.CreatedTime) < d {
+			matched = true
+		}
+	case "CreatedTimeNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CreatedTime != nil && time.Since(*a.CreatedTime) > d {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering LaunchConfigurations.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *LaunchConfiguration) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/ec2/autoscaling/home?region=%s#LaunchConfigurations:id=%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+func (a *LaunchConfiguration) Terminate() (bool, error) {
+	log.Info("Terminating LaunchConfiguration %s", a.ReapableDescriptionTiny())
+	api := autoscaling.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.DeleteLaunchConfiguration(&autoscaling.DeleteLaunchConfigurationInput{
+		LaunchConfigurationName: aws.String(a.ID().String()),
+	})
+	if err != nil {
+		log.Error("could not delete LaunchConfiguration %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because there is no concept of stopping a launch configuration
+func (a *LaunchConfiguration) Stop() (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,40 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestLaunchConfigurationFilter(t *testing.T) {
+	a := NewLaunchConfiguration("us-west-2", &autoscaling.LaunchConfiguration{
+		LaunchConfigurationName: aws.String("web-1"),
+		InstanceType:            aws.String("m4.large"),
+		ImageId:                 aws.String("ami-1234"),
+		CreatedTime:             aws.Time(time.Now().Add(-48 * time.Hour)),
+	})
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"InstanceType", []string{"m4.large"}, true},
+		{"InstanceType", []string{"t2.micro"}, false},
+		{"ImageID", []string{"ami-1234"}, true},
+		{"CreatedTimeInTheLast", []string{"24h"}, false},
+		{"CreatedTimeNotInTheLast", []string{"24h"}, true},
+		{"NameContains", []string{"web"}, true},
+		// launch configurations cannot be tagged
+		{"NotTagged", []string{"Owner"}, true},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
@@ -0,0 +1,428 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elb"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// LoadBalancer is a Reapable, Filterable
+// embeds AWS API's elb.LoadBalancerDescription
+type LoadBalancer struct {
+	Resource
+	elb.LoadBalancerDescription
+
+	// maps registered instance ids to their state:
+	// InService, OutOfService or Unknown, empty if it could not be described
+	InstanceStates map[reapable.ID]string
+	// whether the state of every registered instance was described
+	InstanceHealthKnown bool
+}
+
+// NewLoadBalancer creates a LoadBalancer from the AWS API's elb.LoadBalancerDescription
+// tags and instance states are described separately by the ELB API
+func NewLoadBalancer(region string, lb *elb.LoadBalancerDescription, tags []*elb.Tag, instanceStates []*elb.InstanceState) *LoadBalancer {
+	a := LoadBalancer{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*lb.LoadBalancerName),
+			Name:   *lb.LoadBalancerName,
+			Tags:   make(map[string]string),
+		},
+		LoadBalancerDescription: *lb,
+		InstanceStates:          make(map[reapable.ID]string),
+	}
+
+	for _, instance := range lb.Instances {
+		if instance.InstanceId != nil {
+			a.InstanceStates[reapable.ID(*instance.InstanceId)] = ""
+		}
+	}
+
+	for _, instanceState := range instanceStates {
+		if instanceState.InstanceId != nil && instanceState.State != nil {
+			a.InstanceStates[reapable.ID(*instanceState.InstanceId)] = *instanceState.State
+		}
+	}
+
+	a.InstanceHealthKnown = true
+	for _, s := range a.InstanceStates {
+		if s == "" {
+			a.InstanceHealthKnown = false
+		}
+	}
+
+	for _, tag := range tags {
+		a.Resource.Tags[*tag.Key] = *tag.Value
+	}
+
+	if a.Tagged("aws:cloudformation:stack-name") {
+		a.Dependency = true
+		a.IsInCloudformation = true
+	}
+
+	if a.Tagged(reaperTag) {
+		// restore previously tagged state
+		a.reaperState = state.NewStateWithTag(a.Tag(reaperTag))
+	} else {
+		// initial state
+		a.reaperState = state.NewState()
+	}
+
+	return &a
+}
+
+// RegisteredInstanceCount returns the number of instances registered with the LoadBalancer
+func (a *LoadBalancer) RegisteredInstanceCount() int {
+	return len(a.InstanceStates)
+}
+
+// HealthyInstanceCount returns the number of registered instances that are InService
+func (a *LoadBalancer) HealthyInstanceCount() int {
+	healthy := 0
+	for _, s := range a.InstanceStates {
+		if s == "InService" {
+			healthy++
+		}
+	}
+	return healthy
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *LoadBalancer) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableLoadBalancerEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *LoadBalancer) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableLoadBalancerEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *LoadBalancer) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableLoadBalancerEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *LoadBalancer) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableLoadBalancerEventHTMLShort)
+	return
+}
+
+type loadBalancerEventData struct {
+	Config        *Config
+	LoadBalancer  *LoadBalancer
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+	IgnoreLink1   string
+	IgnoreLink3   string
+	IgnoreLink7   string
+}
+
+func (a *LoadBalancer) getTemplateData() (interface{}, error) {
+	ignore1, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(1*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore3, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(3*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore7, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &loadBalancerEventData{
+		Config:        config,
+		LoadBalancer:  a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+		IgnoreLink1:   ignore1,
+		IgnoreLink3:   ignore3,
+		IgnoreLink7:   ignore7,
+	}, nil
+}
+
+const reapableLoadBalancerEventHTML = `
+<html>
+<body>
+	<p>LoadBalancer <a href="{{ .LoadBalancer.AWSConsoleURL }}">"{{.LoadBalancer.Name}}" in {{.LoadBalancer.Region}}</a> is scheduled to be deleted.</p>
+
+	<p>
+		It has {{.LoadBalancer.RegisteredInstanceCount}} registered instances{{ if .LoadBalancer.InstanceHealthKnown }}, {{.LoadBalancer.HealthyInstanceCount}} of which are healthy{{ else }}, whose health could not be described{{ end }}.{{ if .LoadBalancer.DNSName }} Its DNS name is {{.LoadBalancer.DNSName}}.{{ end }}
+	</p>
+
+	<p>
+		You can ignore this message and your LoadBalancer will advance to the next state after <strong>{{.LoadBalancer.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>. If you do not take action it will be deleted!
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+			<li><a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a></li>
+			<li><a href="{{ .IgnoreLink3 }}">Ignore it for 3 more days</a></li>
+			<li><a href="{{ .IgnoreLink7}}">Ignore it for 7 more days</a></li>
+		</ul>
+	</p>
+
+	<p>
+		If you want the Reaper to ignore this LoadBalancer tag it with {{ .Config.WhitelistTag }} with any value, or click <a href="{{ .WhitelistLink }}">here</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableLoadBalancerEventHTMLShort = `
+<html>
+<body>
+	<p>LoadBalancer <a href="{{ .LoadBalancer.AWSConsoleURL }}">"{{.LoadBalancer.Name}}"</a> in {{.LoadBalancer.Region}} ({{.LoadBalancer.HealthyInstanceCount}}/{{.LoadBalancer.RegisteredInstanceCount}} healthy instances) is scheduled to be deleted after <strong>{{.LoadBalancer.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a>,
+		<a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a>,
+		<a href="{{ .IgnoreLink3 }}">3 days</a>,
+		<a href="{{ .IgnoreLink7}}"> 7 days</a>, or
+		<a href="{{ .WhitelistLink }}">Whitelist</a> it.
+	</p>
+</body>
+</html>
+`
+
+const reapableLoadBalancerEventTextShort = `%%%
+LoadBalancer [{{.LoadBalancer.Name}}]({{.LoadBalancer.AWSConsoleURL}}) in region: [{{.LoadBalancer.Region}}](https://{{.LoadBalancer.Region}}.console.aws.amazon.com/ec2/v2/home?region={{.LoadBalancer.Region}}).{{if .LoadBalancer.Owned}} Owned by {{.LoadBalancer.Owner}}.{{end}}\n
+Healthy instances: {{.LoadBalancer.HealthyInstanceCount}}/{{.LoadBalancer.RegisteredInstanceCount}}.\n
+[Whitelist]({{ .WhitelistLink }}) or [Delete]({{ .TerminateLink }}) this LoadBalancer.
+%%%`
+
+const reapableLoadBalancerEventText = `%%%
+Reaper has discovered a LoadBalancer qualified as reapable: [{{.LoadBalancer.Name}}]({{.LoadBalancer.AWSConsoleURL}}) in region: [{{.LoadBalancer.Region}}](https://{{.LoadBalancer.Region}}.console.aws.amazon.com/ec2/v2/home?region={{.LoadBalancer.Region}}).\n
+{{if .LoadBalancer.Owned}}Owned by {{.LoadBalancer.Owner}}.\n{{end}}
+{{if .LoadBalancer.DNSName}}DNS name: {{.LoadBalancer.DNSName}}.\n{{end}}
+Registered instances: {{.LoadBalancer.RegisteredInstanceCount}}, healthy instances: {{.LoadBalancer.HealthyInstanceCount}}.\n
+{{ if .LoadBalancer.AWSConsoleURL}}{{.LoadBalancer.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.LoadBalancer.AWSConsoleURL}})\n
+[Whitelist]({{ .WhitelistLink }}) this LoadBalancer.
+[Delete]({{ .TerminateLink }}) this LoadBalancer.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *LoadBalancer) Save(s *state.State) (bool, error) {
+	log.Info("Saving %s", a.ReapableDescriptionTiny())
+	return tagLoadBalancer(a.Region(), a.ID(), reaperTag, s.String())
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *LoadBalancer) Unsave() (bool, error) {
+	log.Info("Unsaving %s", a.ReapableDescriptionTiny())
+	return untagLoadBalancer(a.Region(), a.ID(), reaperTag)
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+func (a *LoadBalancer) Whitelist() (bool, error) {
+	log.Info("Whitelisting LoadBalancer %s", a.ReapableDescriptionTiny())
+	return tagLoadBalancer(a.Region(), a.ID(), config.WhitelistTag, "true")
+}
+
+func untagLoadBalancer(region reapable.Region, id reapable.ID, key string) (bool, error) {
+	api := elb.New(sess, aws.NewConfig().WithRegion(region.String()))
+	_, err := api.RemoveTags(&elb.RemoveTagsInput{
+		LoadBalancerNames: []*string{aws.String(id.String())},
+		Tags: []*elb.TagKeyOnly{
+			&elb.TagKeyOnly{
+				Key: aws.String(key),
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func tagLoadBalancer(region reapable.Region, id reapable.ID, key, value string) (bool, error) {
+	api := elb.New(sess, aws.NewConfig().WithRegion(region.String()))
+	_, err := api.AddTags(&elb.AddTagsInput{
+		LoadBalancerNames: []*string{aws.String(id.String())},
+		Tags: []*elb.Tag{
+			&elb.Tag{
+				Key:   aws.String(key),
+				Value: aws.String(value),
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *LoadBalancer) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "NoRegisteredInstances":
+		if b, err := filter.BoolValue(0); err == nil && (a.RegisteredInstanceCount() == 0) == b {
+			matched = true
+		}
+	case "AllInstancesUnhealthy":
+		// a LoadBalancer without instances has no unhealthy instances,
+		// and one whose instances' health could not be described is not known to
+		if b, err := filter.BoolValue(0); err == nil && a.InstanceHealthKnown &&
+			(a.RegisteredInstanceCount() > 0 && a.HealthyInstanceCount() == 0) == b {
+			matched = true
+		}
+	case "Scheme":
+		// one of:
+		// internet-facing
+		// internal
+		if a.Scheme != nil && *a.Scheme == filter.Arguments[0] {
+			matched = true
+		}
+	case "CreatedTimeInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CreatedTime != nil && time.Since(*a.CreatedTime) < d {
+			matched = true
+		}
+	case "CreatedTimeNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CreatedTime != nil && time.Since(*a.CreatedTime) > d {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering LoadBalancers.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *LoadBalancer) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/ec2/v2/home?region=%s#LoadBalancers:search=%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+func (a *LoadBalancer) Terminate() (bool, error) {
+	log.Info("Terminating LoadBalancer %s", a.ReapableDescriptionTiny())
+	api := elb.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.DeleteLoadBalancer(&elb.DeleteLoadBalancerInput{
+		LoadBalancerName: aws.String(a.ID().String()),
+	})
+	if err != nil {
+		log.Error("could not delete LoadBalancer %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because there is no concept of stopping a load balancer
+func (a *LoadBalancer) Stop() (bool, error) {
+	return false, nil
+}
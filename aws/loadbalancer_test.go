@@ -0,0 +1,93 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elb"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestLoadBalancerFilter(t *testing.T) {
+	a := NewLoadBalancer("us-west-2", &elb.LoadBalancerDescription{
+		LoadBalancerName: aws.String("web"),
+		Scheme:           aws.String("internet-facing"),
+		CreatedTime:      aws.Time(time.Now().Add(-48 * time.Hour)),
+		Instances: []*elb.Instance{
+			{InstanceId: aws.String("i-1")},
+			{InstanceId: aws.String("i-2")},
+		},
+	}, []*elb.Tag{
+		{Key: aws.String("Owner"), Value: aws.String("bob")},
+	}, []*elb.InstanceState{
+		{InstanceId: aws.String("i-1"), State: aws.String("OutOfService")},
+		{InstanceId: aws.String("i-2"), State: aws.String("Unknown")},
+	})
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"NoRegisteredInstances", []string{"false"}, true},
+		{"AllInstancesUnhealthy", []string{"true"}, true},
+		{"Scheme", []string{"internet-facing"}, true},
+		{"Scheme", []string{"internal"}, false},
+		{"CreatedTimeInTheLast", []string{"72h"}, true},
+		{"CreatedTimeNotInTheLast", []string{"72h"}, false},
+		{"TagNotEqual", []string{"Owner", "bob"}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
+
+func TestLoadBalancerWithoutInstancesFilter(t *testing.T) {
+	a := NewLoadBalancer("us-west-2", &elb.LoadBalancerDescription{
+		LoadBalancerName: aws.String("empty"),
+	}, nil, nil)
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"NoRegisteredInstances", []string{"true"}, true},
+		// a LoadBalancer without instances has no unhealthy instances
+		{"AllInstancesUnhealthy", []string{"true"}, false},
+		// unknown creation time
+		{"CreatedTimeNotInTheLast", []string{"1h"}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
+
+func TestLoadBalancerWithUnknownHealthFilter(t *testing.T) {
+	// DescribeInstanceHealth failed, or did not return every instance
+	for _, instanceStates := range [][]*elb.InstanceState{
+		nil,
+		{{InstanceId: aws.String("i-1"), State: aws.String("OutOfService")}},
+	} {
+		a := NewLoadBalancer("us-west-2", &elb.LoadBalancerDescription{
+			LoadBalancerName: aws.String("web"),
+			Instances: []*elb.Instance{
+				{InstanceId: aws.String("i-1")},
+				{InstanceId: aws.String("i-2")},
+			},
+		}, nil, instanceStates)
+
+		if a.InstanceHealthKnown {
+			t.Errorf("%d instance states: InstanceHealthKnown = true", len(instanceStates))
+		}
+		for _, b := range []string{"true", "false"} {
+			if a.Filter(*filters.NewFilter("AllInstancesUnhealthy", []string{b})) {
+				t.Errorf("%d instance states: AllInstancesUnhealthy(%s) matched", len(instanceStates), b)
+			}
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// LogGroup is a Reapable, Filterable
+// embeds AWS API's cloudwatchlogs.LogGroup
+type LogGroup struct {
+	Resource
+	cloudwatchlogs.LogGroup
+
+	CreationTimeUTC time.Time
+	// zero when no event was ever logged
+	LastEventTime time.Time
+	// whether its log streams could be described, so LastEventTime is known
+	LastEventTimeKnown bool
+}
+
+// NewLogGroup creates a LogGroup from the AWS API's cloudwatchlogs.LogGroup
+// lastEventTime is the latest event of its log streams, if lastEventTimeKnown
+// log groups cannot be tagged, so they always start in the initial state
+func NewLogGroup(region string, group *cloudwatchlogs.LogGroup, lastEventTime time.Time, lastEventTimeKnown bool) *LogGroup {
+	a := LogGroup{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*group.LogGroupName),
+			Name:   *group.LogGroupName,
+			Tags:   make(map[string]string),
+		},
+		LogGroup:           *group,
+		LastEventTime:      lastEventTime,
+		LastEventTimeKnown: lastEventTimeKnown,
+	}
+
+	if group.CreationTime != nil {
+		a.CreationTimeUTC = millisecondsToTime(*group.CreationTime)
+	}
+
+	// initial state
+	a.reaperState = state.NewState()
+
+	return &a
+}
+
+// millisecondsToTime converts CloudWatch Logs' milliseconds since the epoch to a time.Time
+func millisecondsToTime(ms int64) time.Time {
+	return time.Unix(ms/1000, (ms%1000)*int64(time.Millisecond)).UTC()
+}
+
+// Retention returns the LogGroup's retention in days, 0 if events never expire
+func (a *LogGroup) Retention() int64 {
+	if a.RetentionInDays == nil {
+		return 0
+	}
+	return *a.RetentionInDays
+}
+
+// Stored returns the LogGroup's stored bytes
+func (a *LogGroup) Stored() int64 {
+	if a.StoredBytes == nil {
+		return 0
+	}
+	return *a.StoredBytes
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *LogGroup) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableLogGroupEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *LogGroup) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableLogGroupEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *LogGroup) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableLogGroupEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *LogGroup) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableLogGroupEventHTMLShort)
+	return
+}
+
+type logGroupEventData struct {
+	Config        *Config
+	LogGroup      *LogGroup
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+}
+
+func (a *LogGroup) getTemplateData() (interface{}, error) {
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logGroupEventData{
+		Config:        config,
+		LogGroup:      a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+	}, nil
+}
+
+const reapableLogGroupEventHTML = `
+<html>
+<body>
+	<p>LogGroup <a href="{{ .LogGroup.AWSConsoleURL }}">{{ .LogGroup.ID }} in {{.LogGroup.Region}}</a> qualifies as reapable.</p>
+
+	<p>
+		It stores {{ .LogGroup.Stored }} bytes{{ if .LogGroup.Retention }} for {{ .LogGroup.Retention }} days{{ else }} forever{{ end }}. {{ if not .LogGroup.LastEventTimeKnown }}Its last event is unknown.{{ else if .LogGroup.LastEventTime.IsZero }}It has no events.{{ else }}Its last event was logged {{ .LogGroup.LastEventTime.UTC.Format "Jan 2, 2006 at 3:04pm (MST)" }}.{{ end }}
+	</p>
+
+	<p>
+		Log groups cannot be tagged, so the Reaper cannot keep track of this LogGroup: you will be notified again on every run, and it will not be deleted unless you delete it below.
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+			<li><a href="{{ .StopLink }}">Expire its events after {{ .Config.LogGroupRetentionInDays }} days</a></li>
+		</ul>
+	</p>
+</body>
+</html>
+`
+
+const reapableLogGroupEventHTMLShort = `
+<html>
+<body>
+	<p>LogGroup <a href="{{ .LogGroup.AWSConsoleURL }}">{{ .LogGroup.ID }}</a> in {{.LogGroup.Region}} ({{ .LogGroup.Stored }} bytes) qualifies as reapable, and will not be deleted unless you delete it.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a> or
+		<a href="{{ .StopLink }}">Set retention</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableLogGroupEventTextShort = `%%%
+LogGroup [{{.LogGroup.ID}}]({{.LogGroup.AWSConsoleURL}}) in region: [{{.LogGroup.Region}}](https://{{.LogGroup.Region}}.console.aws.amazon.com/cloudwatch/home?region={{.LogGroup.Region}}#logs:).{{if .LogGroup.Owned}} Owned by {{.LogGroup.Owner}}.{{end}}\n
+[Set retention]({{ .StopLink }}) or [Delete]({{ .TerminateLink }}) this LogGroup.
+%%%`
+
+const reapableLogGroupEventText = `%%%
+Reaper has discovered a LogGroup qualified as reapable: [{{.LogGroup.ID}}]({{.LogGroup.AWSConsoleURL}}) in region: [{{.LogGroup.Region}}](https://{{.LogGroup.Region}}.console.aws.amazon.com/cloudwatch/home?region={{.LogGroup.Region}}#logs:).\n
+{{if .LogGroup.Owned}}Owned by {{.LogGroup.Owner}}.\n{{end}}
+{{.LogGroup.Stored}} bytes, retention: {{if .LogGroup.Retention}}{{.LogGroup.Retention}} days{{else}}never expire{{end}}, last event: {{if not .LogGroup.LastEventTimeKnown}}unknown{{else if .LogGroup.LastEventTime.IsZero}}none{{else}}{{.LogGroup.LastEventTime.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}{{end}}.\n
+{{ if .LogGroup.AWSConsoleURL}}{{.LogGroup.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.LogGroup.AWSConsoleURL}})\n
+[Set retention]({{ .StopLink }}) of this LogGroup to {{ .Config.LogGroupRetentionInDays }} days.
+[Delete]({{ .TerminateLink }}) this LogGroup.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because log groups cannot be tagged
+func (a *LogGroup) Save(s *state.State) (bool, error) {
+	return false, nil
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because log groups cannot be tagged
+func (a *LogGroup) Unsave() (bool, error) {
+	return false, nil
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+// no op because log groups cannot be tagged
+func (a *LogGroup) Whitelist() (bool, error) {
+	return false, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *LogGroup) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "NamePrefix":
+		if strings.HasPrefix(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNamePrefix":
+		if !strings.HasPrefix(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "HasRetention":
+		if b, err := filter.BoolValue(0); err == nil && (a.Retention() > 0) == b {
+			matched = true
+		}
+	case "RetentionInDaysGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.Retention() > i {
+			matched = true
+		}
+	case "RetentionInDaysLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.Retention() > 0 && a.Retention() < i {
+			matched = true
+		}
+	case "StoredBytesGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.Stored() > i {
+			matched = true
+		}
+	case "StoredBytesLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.Stored() < i {
+			matched = true
+		}
+	case "LastEventInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && !a.LastEventTime.IsZero() && time.Since(a.LastEventTime) < d {
+			matched = true
+		}
+	case "LastEventNotInTheLast":
+		// log groups without events match, unless their log streams could not be described
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.LastEventTimeKnown && time.Since(a.LastEventTime) > d {
+			matched = true
+		}
+	case "CreatedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && !a.CreationTimeUTC.IsZero() && time.Since(a.CreationTimeUTC) < d {
+			matched = true
+		}
+	case "CreatedNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && !a.CreationTimeUTC.IsZero() && time.Since(a.CreationTimeUTC) > d {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering LogGroups.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *LogGroup) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/cloudwatch/home?region=%s#logStream:group=%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+func (a *LogGroup) Terminate() (bool, error) {
+	log.Info("Terminating LogGroup %s", a.ReapableDescriptionTiny())
+	api := cloudwatchlogs.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.DeleteLogGroup(&cloudwatchlogs.DeleteLogGroupInput{
+		LogGroupName: aws.String(a.ID().String()),
+	})
+	if err != nil {
+		log.Error("could not delete LogGroup %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// sets the LogGroup's retention to LogGroupRetentionInDays, unless it is already shorter
+// stopping reduces what the LogGroup costs instead of shutting it down
+func (a *LogGroup) Stop() (bool, error) {
+	if a.Retention() > 0 && a.Retention() <= config.LogGroupRetentionInDays {
+		log.Info("LogGroup %s already expires its events after %d days", a.ReapableDescriptionTiny(), a.Retention())
+		return false, nil
+	}
+
+	log.Info("Stopping LogGroup %s", a.ReapableDescriptionTiny())
+	api := cloudwatchlogs.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.PutRetentionPolicy(&cloudwatchlogs.PutRetentionPolicyInput{
+		LogGroupName:    aws.String(a.ID().String()),
+		RetentionInDays: aws.Int64(config.LogGroupRetentionInDays),
+	})
+	if err != nil {
+		log.Error("could not set the retention of LogGroup %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,79 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestLogGroupFilter(t *testing.T) {
+	created := time.Now().Add(-48 * time.Hour)
+	a := NewLogGroup("us-west-2", &cloudwatchlogs.LogGroup{
+		LogGroupName:    aws.String("/aws/lambda/resize"),
+		RetentionInDays: aws.Int64(30),
+		StoredBytes:     aws.Int64(2048),
+		CreationTime:    aws.Int64(created.UnixNano() / int64(time.Millisecond)),
+	}, time.Now().Add(-time.Hour), true)
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"NamePrefix", []string{"/aws/lambda/"}, true},
+		{"NotNamePrefix", []string{"/aws/lambda/"}, false},
+		{"HasRetention", []string{"true"}, true},
+		{"RetentionInDaysGreaterThan", []string{"14"}, true},
+		{"RetentionInDaysLessThan", []string{"30"}, false},
+		{"StoredBytesGreaterThan", []string{"1024"}, true},
+		{"StoredBytesLessThan", []string{"1024"}, false},
+		{"LastEventInTheLast", []string{"24h"}, true},
+		{"LastEventNotInTheLast", []string{"24h"}, false},
+		{"CreatedInTheLast", []string{"24h"}, false},
+		{"CreatedNotInTheLast", []string{"24h"}, true},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
+
+func TestLogGroupWithoutEventsFilter(t *testing.T) {
+	a := NewLogGroup("us-west-2", &cloudwatchlogs.LogGroup{
+		LogGroupName: aws.String("empty"),
+	}, time.Time{}, true)
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"HasRetention", []string{"false"}, true},
+		// events that never expire are not retained for less than any number of days
+		{"RetentionInDaysLessThan", []string{"30"}, false},
+		// log groups without events match
+		{"LastEventNotInTheLast", []string{"24h"}, true},
+		{"LastEventInTheLast", []string{"24h"}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
+
+func TestLogGroupWithUnknownEventsFilter(t *testing.T) {
+	// DescribeLogStreams failed
+	a := NewLogGroup("us-west-2", &cloudwatchlogs.LogGroup{
+		LogGroupName: aws.String("throttled"),
+	}, time.Time{}, false)
+
+	for _, function := range []string{"LastEventInTheLast", "LastEventNotInTheLast"} {
+		if a.Filter(*filters.NewFilter(function, []string{"24h"})) {
+			t.Errorf("%s matched a LogGroup whose last event is unknown", function)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// MetricAlarm is a Reapable, Filterable
+// embeds AWS API's cloudwatch.MetricAlarm
+type MetricAlarm struct {
+	Resource
+	cloudwatch.MetricAlarm
+
+	// dimensions identifying resources that no longer exist
+	// set from the inventory of resources described by the reaper
+	DeletedDimensions []*cloudwatch.Dimension
+}
+
+// NewMetricAlarm creates a MetricAlarm from the AWS API's cloudwatch.MetricAlarm
+// alarms cannot be tagged, so they always start in the initial state
+func NewMetricAlarm(region string, alarm *cloudwatch.MetricAlarm) *MetricAlarm {
+	a := MetricAlarm{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*alarm.AlarmName),
+			Name:   *alarm.AlarmName,
+			Tags:   make(map[string]string),
+		},
+		MetricAlarm: *alarm,
+	}
+
+	// initial state
+	a.reaperState = state.NewState()
+
+	return &a
+}
+
+// DimensionDeleted returns whether any of the MetricAlarm's dimensions identifies a resource that no longer exists
+func (a *MetricAlarm) DimensionDeleted() bool {
+	return len(a.DeletedDimensions) > 0
+}
+
+// DimensionsString returns the MetricAlarm's dimensions as name=value pairs
+func (a *MetricAlarm) DimensionsString() string {
+	var dimensions []string
+	for _, dimension := range a.Dimensions {
+		dimensions = append(dimensions, fmt.Sprintf("%s=%s", *dimension.Name, *dimension.Value))
+	}
+	return strings.Join(dimensions, ", ")
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *MetricAlarm) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableMetricAlarmEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *MetricAlarm) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableMetricAlarmEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *MetricAlarm) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableMetricAlarmEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *MetricAlarm) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableMetricAlarmEventHTMLShort)
+	return
+}
+
+type metricAlarmEventData struct {
+	Config        *Config
+	MetricAlarm   *MetricAlarm
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+}
+
+func (a *MetricAlarm) getTemplateData() (interface{}, error) {
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &metricAlarmEventData{
+		Config:        config,
+		MetricAlarm:   a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+	}, nil
+}
+
+const reapableMetricAlarmEventHTML = `
+<html>
+<body>
+	<p>MetricAlarm <a href="{{ .MetricAlarm.AWSConsoleURL }}">{{ .MetricAlarm.ID }} in {{.MetricAlarm.Region}}</a> qualifies as reapable.</p>
+
+	<p>
+		It watches {{ .MetricAlarm.Namespace }} {{ .MetricAlarm.MetricName }}{{ if .MetricAlarm.Dimensions }} for {{ .MetricAlarm.DimensionsString }}{{ end }} and has been {{ .MetricAlarm.StateValue }}{{ if .MetricAlarm.StateUpdatedTimestamp }} since {{ .MetricAlarm.StateUpdatedTimestamp.UTC.Format "Jan 2, 2006" }}{{ end }}.
+		{{ if .MetricAlarm.DimensionDeleted }}The resource it watches no longer exists.{{ end }}
+	</p>
+
+	<p>
+		Alarms cannot be tagged, so the Reaper cannot keep track of this MetricAlarm: you will be notified again on every run, and it will not be deleted unless you delete it below.
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+		</ul>
+	</p>
+</body>
+</html>
+`
+
+const reapableMetricAlarmEventHTMLShort = `
+<html>
+<body>
+	<p>MetricAlarm <a href="{{ .MetricAlarm.AWSConsoleURL }}">{{ .MetricAlarm.ID }}</a> ({{ .MetricAlarm.StateValue }}) in {{.MetricAlarm.Region}} qualifies as reapable, and will not be deleted unless you delete it.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableMetricAlarmEventTextShort = `%%%
+MetricAlarm [{{.MetricAlarm.ID}}]({{.MetricAlarm.AWSConsoleURL}}) ({{.MetricAlarm.StateValue}}) in region: [{{.MetricAlarm.Region}}](https://{{.MetricAlarm.Region}}.console.aws.amazon.com/cloudwatch/home?region={{.MetricAlarm.Region}}).{{if .MetricAlarm.Owned}} Owned by {{.MetricAlarm.Owner}}.{{end}}\n
+[Delete]({{ .TerminateLink }}) this MetricAlarm.
+%%%`
+
+const reapableMetricAlarmEventText = `%%%
+Reaper has discovered a MetricAlarm qualified as reapable: [{{.MetricAlarm.ID}}]({{.MetricAlarm.AWSConsoleURL}}) in region: [{{.MetricAlarm.Region}}](https://{{.MetricAlarm.Region}}.console.aws.amazon.com/cloudwatch/home?region={{.MetricAlarm.Region}}).\n
+{{if .MetricAlarm.Owned}}Owned by {{.MetricAlarm.Owner}}.\n{{end}}
+Metric: {{.MetricAlarm.Namespace}} {{.MetricAlarm.MetricName}}{{if .MetricAlarm.Dimensions}} ({{.MetricAlarm.DimensionsString}}){{end}}, state: {{.MetricAlarm.StateValue}}.\n
+{{if .MetricAlarm.DimensionDeleted}}The resource it watches no longer exists.\n{{end}}
+{{ if .MetricAlarm.AWSConsoleURL}}{{.MetricAlarm.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.MetricAlarm.AWSConsoleURL}})\n
+[Delete]({{ .TerminateLink }}) this MetricAlarm.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because alarms cannot be tagged
+func (a *MetricAlarm) Save(s *state.State) (bool, error) {
+	return false, nil
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because alarms cannot be tagged
+func (a *MetricAlarm) Unsave() (bool, error) {
+	return false, nil
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+// no op because alarms cannot be tagged
+func (a *MetricAlarm) Whitelist() (bool, error) {
+	return false, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *MetricAlarm) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "State":
+		// one of:
+		// OK
+		// ALARM
+		// INSUFFICIENT_DATA
+		if a.StateValue != nil && *a.StateValue == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotState":
+		if a.StateValue != nil && *a.StateValue != filter.Arguments[0] {
+			matched = true
+		}
+	case "StateTimeInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.StateUpdatedTimestamp != nil && time.Since(*a.StateUpdatedTimestamp) < d {
+			matched = true
+		}
+	case "StateTimeNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.StateUpdatedTimestamp != nil && time.Since(*a.StateUpdatedTimestamp) > d {
+			matched = true
+		}
+	case "DimensionDeleted":
+		if b, err := filter.BoolValue(0); err == nil && a.DimensionDeleted() == b {
+			matched = true
+		}
+	case "Namespace":
+		if a.Namespace != nil && *a.Namespace == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamespace":
+		if a.Namespace != nil && *a.Namespace != filter.Arguments[0] {
+			matched = true
+		}
+	case "ActionsEnabled":
+		if b, err := filter.BoolValue(0); err == nil && a.ActionsEnabled != nil && *a.ActionsEnabled == b {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering MetricAlarms.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *MetricAlarm) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/cloudwatch/home?region=%s#alarm:alarmFilter=ANY;name=%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+func (a *MetricAlarm) Terminate() (bool, error) {
+	log.Info("Terminating MetricAlarm %s", a.ReapableDescriptionTiny())
+	api := cloudwatch.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.DeleteAlarms(&cloudwatch.DeleteAlarmsInput{
+		AlarmNames: []*string{aws.String(a.ID().String())},
+	})
+	if err != nil {
+		log.Error("could not delete MetricAlarm %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because alarms cannot be stopped
+func (a *MetricAlarm) Stop() (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,48 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestMetricAlarmFilter(t *testing.T) {
+	dimension := &cloudwatch.Dimension{Name: aws.String("InstanceId"), Value: aws.String("i-1")}
+	a := NewMetricAlarm("us-west-2", &cloudwatch.MetricAlarm{
+		AlarmName:             aws.String("cpu"),
+		Namespace:             aws.String("AWS/EC2"),
+		StateValue:            aws.String("INSUFFICIENT_DATA"),
+		StateUpdatedTimestamp: aws.Time(time.Now().Add(-48 * time.Hour)),
+		ActionsEnabled:        aws.Bool(true),
+		Dimensions:            []*cloudwatch.Dimension{dimension},
+	})
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"State", []string{"INSUFFICIENT_DATA"}, true},
+		{"NotState", []string{"INSUFFICIENT_DATA"}, false},
+		{"StateTimeInTheLast", []string{"24h"}, false},
+		{"StateTimeNotInTheLast", []string{"24h"}, true},
+		{"DimensionDeleted", []string{"false"}, true},
+		{"Namespace", []string{"AWS/EC2"}, true},
+		{"NotNamespace", []string{"AWS/EC2"}, false},
+		{"ActionsEnabled", []string{"true"}, true},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+
+	a.DeletedDimensions = append(a.DeletedDimensions, dimension)
+	if !a.Filter(*filters.NewFilter("DimensionDeleted", []string{"true"})) {
+		t.Error("DimensionDeleted(true) did not match a MetricAlarm with a deleted dimension")
+	}
+}
@@ -0,0 +1,416 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// natGatewayDeletionTimeout is how long Terminate waits for a NAT gateway to be deleted
+// before releasing its addresses
+const natGatewayDeletionTimeout = 5 * time.Minute
+
+// NatGateway is a Reapable, Filterable
+// embeds AWS API's ec2.NatGateway
+type NatGateway struct {
+	Resource
+	ec2.NatGateway
+
+	// route tables with a route to the NatGateway
+	RouteTableIDs []reapable.ID
+
+	// bytes processed from CloudWatch, by duration
+	processedBytes map[time.Duration]float64
+}
+
+// NewNatGateway creates a NatGateway from the AWS API's ec2.NatGateway
+// NAT gateways cannot be tagged, so they always start in the initial state
+func NewNatGateway(region string, gateway *ec2.NatGateway, routeTableIDs []reapable.ID) *NatGateway {
+	a := NatGateway{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*gateway.NatGatewayId),
+			Name:   *gateway.NatGatewayId,
+			Tags:   make(map[string]string),
+		},
+		NatGateway:     *gateway,
+		RouteTableIDs:  routeTableIDs,
+		processedBytes: make(map[time.Duration]float64),
+	}
+
+	// initial state
+	a.reaperState = state.NewState()
+
+	return &a
+}
+
+// Routed returns whether any route table has a route to the NatGateway
+func (a *NatGateway) Routed() bool {
+	return len(a.RouteTableIDs) > 0
+}
+
+// PublicIPs returns the NatGateway's Elastic IP addresses
+func (a *NatGateway) PublicIPs() []string {
+	var ips []string
+	for _, address := range a.NatGatewayAddresses {
+		if address.PublicIp != nil {
+			ips = append(ips, *address.PublicIp)
+		}
+	}
+	return ips
+}
+
+// ProcessedBytes returns how many bytes the NatGateway sent in the last d
+// from the AWS/NATGateway BytesOutToDestination and BytesOutToSource metrics in CloudWatch
+func (a *NatGateway) ProcessedBytes(d time.Duration) (float64, error) {
+	if sum, ok := a.processedBytes[d]; ok {
+		return sum, nil
+	}
+
+	var sum float64
+	for _, metric := range []string{"BytesOutToDestination", "BytesOutToSource"} {
+		s, err := metricSum(a.Region(), "AWS/NATGateway", metric, "NatGatewayId", a.ID().String(), d)
+		if err != nil {
+			return 0, err
+		}
+		sum += s
+	}
+	a.processedBytes[d] = sum
+	return sum, nil
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *NatGateway) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableNatGatewayEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *NatGateway) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableNatGatewayEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *NatGateway) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableNatGatewayEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *NatGateway) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableNatGatewayEventHTMLShort)
+	return
+}
+
+type natGatewayEventData struct {
+	Config        *Config
+	NatGateway    *NatGateway
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+}
+
+func (a *NatGateway) getTemplateData() (interface{}, error) {
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &natGatewayEventData{
+		Config:        config,
+		NatGateway:    a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+	}, nil
+}
+
+const reapableNatGatewayEventHTML = `
+<html>
+<body>
+	<p>NatGateway <a href="{{ .NatGateway.AWSConsoleURL }}">{{ .NatGateway.ID }} in {{.NatGateway.Region}}</a> qualifies as reapable.</p>
+
+	<p>
+		It is in {{ .NatGateway.VpcId }}, and {{ if .NatGateway.Routed }}is routed to by {{ len .NatGateway.RouteTableIDs }} route tables{{ else }}no route table routes to it{{ end }}.
+		{{ if .Config.ReleaseNatGatewayAddresses }}Its Elastic IP addresses {{ .NatGateway.PublicIPs }} will be released with it.{{ else }}Its Elastic IP addresses {{ .NatGateway.PublicIPs }} will be kept.{{ end }}
+	</p>
+
+	<p>
+		NAT gateways cannot be tagged, so the Reaper cannot keep track of this NatGateway: you will be notified again on every run, and it will not be deleted unless you delete it below.
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+		</ul>
+	</p>
+</body>
+</html>
+`
+
+const reapableNatGatewayEventHTMLShort = `
+<html>
+<body>
+	<p>NatGateway <a href="{{ .NatGateway.AWSConsoleURL }}">{{ .NatGateway.ID }}</a> in {{.NatGateway.Region}} qualifies as reapable, and will not be deleted unless you delete it.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableNatGatewayEventTextShort = `%%%
+NatGateway [{{.NatGateway.ID}}]({{.NatGateway.AWSConsoleURL}}) in region: [{{.NatGateway.Region}}](https://{{.NatGateway.Region}}.console.aws.amazon.com/vpc/home?region={{.NatGateway.Region}}).{{if .NatGateway.Owned}} Owned by {{.NatGateway.Owner}}.{{end}}\n
+[Delete]({{ .TerminateLink }}) this NatGateway.
+%%%`
+
+const reapableNatGatewayEventText = `%%%
+Reaper has discovered a NatGateway qualified as reapable: [{{.NatGateway.ID}}]({{.NatGateway.AWSConsoleURL}}) in region: [{{.NatGateway.Region}}](https://{{.NatGateway.Region}}.console.aws.amazon.com/vpc/home?region={{.NatGateway.Region}}).\n
+{{if .NatGateway.Owned}}Owned by {{.NatGateway.Owner}}.\n{{end}}
+VPC: {{.NatGateway.VpcId}}, subnet: {{.NatGateway.SubnetId}}, route tables: {{len .NatGateway.RouteTableIDs}}, Elastic IPs: {{.NatGateway.PublicIPs}}.\n
+{{ if .NatGateway.AWSConsoleURL}}{{.NatGateway.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.NatGateway.AWSConsoleURL}})\n
+[Delete]({{ .TerminateLink }}) this NatGateway.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because NAT gateways cannot be tagged
+func (a *NatGateway) Save(s *state.State) (bool, error) {
+	return false, nil
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+// no op because NAT gateways cannot be tagged
+func (a *NatGateway) Unsave() (bool, error) {
+	return false, nil
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+// no op because NAT gateways cannot be tagged
+func (a *NatGateway) Whitelist() (bool, error) {
+	return false, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *NatGateway) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "State":
+		// one of:
+		// pending
+		// failed
+		// available
+		// deleting
+		if a.State != nil && *a.State == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotState":
+		if a.State != nil && *a.State != filter.Arguments[0] {
+			matched = true
+		}
+	case "Routed":
+		if b, err := filter.BoolValue(0); err == nil && a.Routed() == b {
+			matched = true
+		}
+	case "VPC":
+		if a.VpcId != nil && *a.VpcId == filter.Arguments[0] {
+			matched = true
+		}
+	case "CreatedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CreateTime != nil && time.Since(*a.CreateTime) < d {
+			matched = true
+		}
+	case "CreatedNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.CreateTime != nil && time.Since(*a.CreateTime) > d {
+			matched = true
+		}
+	case "ProcessedBytesInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err != nil {
+			break
+		}
+		processed, err := a.ProcessedBytes(d)
+		if err != nil {
+			log.Error("Error getting processed bytes of NatGateway %s: %s", a.ReapableDescriptionTiny(), err.Error())
+		} else if processed > 0 {
+			matched = true
+		}
+	case "NoProcessedBytesInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err != nil {
+			break
+		}
+		processed, err := a.ProcessedBytes(d)
+		if err != nil {
+			log.Error("Error getting processed bytes of NatGateway %s: %s", a.ReapableDescriptionTiny(), err.Error())
+		} else if processed == 0 {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering NatGateways.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *NatGateway) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/vpc/home?region=%s#NatGateways:search=%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+// releases the NatGateway's Elastic IP addresses if ReleaseNatGatewayAddresses is set
+func (a *NatGateway) Terminate() (bool, error) {
+	log.Info("Terminating NatGateway %s", a.ReapableDescriptionTiny())
+	api := ec2.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.DeleteNatGateway(&ec2.DeleteNatGatewayInput{
+		NatGatewayId: aws.String(a.ID().String()),
+	})
+	if err != nil {
+		log.Error("could not delete NatGateway %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+
+	if !config.ReleaseNatGatewayAddresses {
+		return true, nil
+	}
+
+	// addresses stay associated until the gateway is deleted
+	if err := waitForNatGatewayDeletion(api, a.ID()); err != nil {
+		log.Error("could not release the addresses of NatGateway %s", a.ReapableDescriptionTiny())
+		return true, err
+	}
+	for _, address := range a.NatGatewayAddresses {
+		if address.AllocationId == nil {
+			continue
+		}
+		log.Info("Releasing address %s of NatGateway %s", *address.AllocationId, a.ReapableDescriptionTiny())
+		_, err := api.ReleaseAddress(&ec2.ReleaseAddressInput{AllocationId: address.AllocationId})
+		if err != nil {
+			log.Error("could not release address %s of NatGateway %s", *address.AllocationId, a.ReapableDescriptionTiny())
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// waitForNatGatewayDeletion polls a NAT gateway until it is deleted
+func waitForNatGatewayDeletion(api *ec2.EC2, id reapable.ID) error {
+	deadline := time.Now().Add(natGatewayDeletionTimeout)
+	for time.Now().Before(deadline) {
+		resp, err := api.DescribeNatGateways(&ec2.DescribeNatGatewaysInput{
+			NatGatewayIds: []*string{aws.String(id.String())},
+		})
+		if err != nil {
+			return err
+		}
+		if len(resp.NatGateways) == 0 || *resp.NatGateways[0].State == ec2.NatGatewayStateDeleted {
+			return nil
+		}
+		time.Sleep(10 * time.Second)
+	}
+	return fmt.Errorf("NatGateway %s was not deleted after %s", id, natGatewayDeletionTimeout)
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because NAT gateways cannot be stopped
+func (a *NatGateway) Stop() (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,131 @@
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+)
+
+func TestNatGatewayFilter(t *testing.T) {
+	a := NewNatGateway("us-west-2", &ec2.NatGateway{
+		NatGatewayId: aws.String("nat-1"),
+		VpcId:        aws.String("vpc-1"),
+		State:        aws.String("available"),
+		CreateTime:   aws.Time(time.Now().Add(-48 * time.Hour)),
+	}, nil)
+	// avoids getting processed bytes from CloudWatch
+	a.processedBytes[24*time.Hour] = 0
+	a.processedBytes[72*time.Hour] = 4096
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"State", []string{"available"}, true},
+		{"NotState", []string{"available"}, false},
+		{"Routed", []string{"false"}, true},
+		{"VPC", []string{"vpc-1"}, true},
+		{"VPC", []string{"vpc-2"}, false},
+		{"CreatedInTheLast", []string{"24h"}, false},
+		{"CreatedNotInTheLast", []string{"24h"}, true},
+		{"ProcessedBytesInTheLast", []string{"24h"}, false},
+		{"NoProcessedBytesInTheLast", []string{"24h"}, true},
+		{"ProcessedBytesInTheLast", []string{"72h"}, true},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+
+	routed := NewNatGateway("us-west-2", &ec2.NatGateway{NatGatewayId: aws.String("nat-2")}, []reapable.ID{"rtb-1"})
+	if !routed.Filter(*filters.NewFilter("Routed", []string{"true"})) {
+		t.Error("Routed(true) did not match a NatGateway with a route table")
+	}
+}
+
+// fakeNatGatewayEC2 answers the calls NatGateway.Terminate makes, and records them
+// DescribeNatGateways fails if describeFails is set
+type fakeNatGatewayEC2 struct {
+	calls         []string
+	released      []string
+	describeFails bool
+}
+
+func (f *fakeNatGatewayEC2) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	action := r.Form.Get("Action")
+	f.calls = append(f.calls, action)
+	switch action {
+	case "DeleteNatGateway":
+		fmt.Fprint(w, `<DeleteNatGatewayResponse><natGatewayId>nat-1</natGatewayId></DeleteNatGatewayResponse>`)
+	case "DescribeNatGateways":
+		if f.describeFails {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `<Response><Errors><Error><Code>InternalError</Code><Message>failed</Message></Error></Errors></Response>`)
+			return
+		}
+		fmt.Fprint(w, `<DescribeNatGatewaysResponse><natGatewaySet><item>`+
+			`<natGatewayId>nat-1</natGatewayId><state>deleted</state>`+
+			`</item></natGatewaySet></DescribeNatGatewaysResponse>`)
+	case "ReleaseAddress":
+		f.released = append(f.released, r.Form.Get("AllocationId"))
+		fmt.Fprint(w, `<ReleaseAddressResponse><return>true</return></ReleaseAddressResponse>`)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func TestNatGatewayTerminate(t *testing.T) {
+	defer func(c *Config, s *session.Session) { config, sess = c, s }(config, sess)
+
+	for _, test := range []struct {
+		releaseAddresses bool
+		describeFails    bool
+		err              bool
+		calls            []string
+		released         []string
+	}{
+		{false, false, false, []string{"DeleteNatGateway"}, nil},
+		// addresses are released once the NatGateway is deleted
+		{true, false, false, []string{"DeleteNatGateway", "DescribeNatGateways", "ReleaseAddress"}, []string{"eipalloc-1"}},
+		// addresses are kept if the deletion could not be awaited
+		{true, true, true, []string{"DeleteNatGateway", "DescribeNatGateways"}, nil},
+	} {
+		f := &fakeNatGatewayEC2{describeFails: test.describeFails}
+		var closeServer func()
+		sess, closeServer = testSession(f.ServeHTTP)
+		config = &Config{ReleaseNatGatewayAddresses: test.releaseAddresses}
+
+		a := NewNatGateway("us-west-2", &ec2.NatGateway{
+			NatGatewayId: aws.String("nat-1"),
+			NatGatewayAddresses: []*ec2.NatGatewayAddress{
+				{AllocationId: aws.String("eipalloc-1")},
+				// addresses without an allocation are not released
+				{},
+			},
+		}, nil)
+		terminated, err := a.Terminate()
+		closeServer()
+
+		if !terminated || (err != nil) != test.err {
+			t.Errorf("%+v: Terminate() = %t, %v", test, terminated, err)
+		}
+		if strings.Join(f.calls, ",") != strings.Join(test.calls, ",") {
+			t.Errorf("%+v: calls = %v, want %v", test, f.calls, test.calls)
+		}
+		if strings.Join(f.released, ",") != strings.Join(test.released, ",") {
+			t.Errorf("%+v: released = %v, want %v", test, f.released, test.released)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// NetworkInterface is a Reapable, Filterable
+// embeds AWS API's ec2.NetworkInterface
+type NetworkInterface struct {
+	Resource
+	ec2.NetworkInterface
+
+	SecurityGroupIDs []reapable.ID
+}
+
+// NewNetworkInterface creates a NetworkInterface from the AWS API's ec2.NetworkInterface
+func NewNetworkInterface(region string, eni *ec2.NetworkInterface) *NetworkInterface {
+	a := NetworkInterface{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*eni.NetworkInterfaceId),
+			Tags:   make(map[string]string),
+		},
+		NetworkInterface: *eni,
+	}
+
+	for _, tag := range eni.TagSet {
+		a.Resource.Tags[*tag.Key] = *tag.Value
+	}
+
+	a.Name = a.Resource.Tag("Name")
+
+	for _, group := range eni.Groups {
+		if group.GroupId != nil {
+			a.SecurityGroupIDs = append(a.SecurityGroupIDs, reapable.ID(*group.GroupId))
+		}
+	}
+
+	if a.Tagged("aws:cloudformation:stack-name") {
+		a.Dependency = true
+		a.IsInCloudformation = true
+	}
+
+	if a.Tagged(reaperTag) {
+		// restore previously tagged state
+		a.reaperState = state.NewStateWithTag(a.Tag(reaperTag))
+	} else {
+		// initial state
+		a.reaperState = state.NewState()
+	}
+
+	return &a
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *NetworkInterface) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableNetworkInterfaceEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *NetworkInterface) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableNetworkInterfaceEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *NetworkInterface) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableNetworkInterfaceEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *NetworkInterface) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableNetworkInterfaceEventHTMLShort)
+	return
+}
+
+type networkInterfaceEventData struct {
+	Config           *Config
+	NetworkInterface *NetworkInterface
+	TerminateLink    string
+	StopLink         string
+	WhitelistLink    string
+	IgnoreLink1      string
+	IgnoreLink3      string
+	IgnoreLink7      string
+}
+
+func (a *NetworkInterface) getTemplateData() (interface{}, error) {
+	ignore1, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(1*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore3, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(3*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore7, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &networkInterfaceEventData{
+		Config:           config,
+		NetworkInterface: a,
+		TerminateLink:    terminate,
+		StopLink:         stop,
+		WhitelistLink:    whitelist,
+		IgnoreLink1:      ignore1,
+		IgnoreLink3:      ignore3,
+		IgnoreLink7:      ignore7,
+	}, nil
+}
+
+const reapableNetworkInterfaceEventHTML = `
+<html>
+<body>
+	<p>NetworkInterface <a href="{{ .NetworkInterface.AWSConsoleURL }}">{{ if .NetworkInterface.Name }}"{{.NetworkInterface.Name}}" {{ end }}{{ .NetworkInterface.ID }} in {{.NetworkInterface.Region}}</a> is scheduled to be deleted.</p>
+
+	<p>
+		Its status is {{ .NetworkInterface.Status }}.{{ if .NetworkInterface.Description }} Its description is "{{ .NetworkInterface.Description }}".{{ end }}
+	</p>
+
+	<p>
+		You can ignore this message and your NetworkInterface will advance to the next state after <strong>{{.NetworkInterface.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>. If you do not take action it will be deleted!
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+			<li><a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a></li>
+			<li><a href="{{ .IgnoreLink3 }}">Ignore it for 3 more days</a></li>
+			<li><a href="{{ .IgnoreLink7}}">Ignore it for 7 more days</a></li>
+		</ul>
+	</p>
+
+	<p>
+		If you want the Reaper to ignore this NetworkInterface tag it with {{ .Config.WhitelistTag }} with any value, or click <a href="{{ .WhitelistLink }}">here</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableNetworkInterfaceEventHTMLShort = `
+<html>
+<body>
+	<p>NetworkInterface <a href="{{ .NetworkInterface.AWSConsoleURL }}">{{ if .NetworkInterface.Name }}"{{.NetworkInterface.Name}}" {{ end }}{{ .NetworkInterface.ID }}</a> in {{.NetworkInterface.Region}} is scheduled to be deleted after <strong>{{.NetworkInterface.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a>,
+		<a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a>,
+		<a href="{{ .IgnoreLink3 }}">3 days</a>,
+		<a href="{{ .IgnoreLink7}}"> 7 days</a>, or
+		<a href="{{ .WhitelistLink }}">Whitelist</a> it.
+	</p>
+</body>
+</html>
+`
+
+const reapableNetworkInterfaceEventTextShort = `%%%
+NetworkInterface {{if .NetworkInterface.Name}}"{{.NetworkInterface.Name}}" {{end}}[{{.NetworkInterface.ID}}]({{.NetworkInterface.AWSConsoleURL}}) in region: [{{.NetworkInterface.Region}}](https://{{.NetworkInterface.Region}}.console.aws.amazon.com/ec2/v2/home?region={{.NetworkInterface.Region}}).{{if .NetworkInterface.Owned}} Owned by {{.NetworkInterface.Owner}}.{{end}}\n
+Status: {{.NetworkInterface.Status}}.\n
+[Whitelist]({{ .WhitelistLink }}) or [Delete]({{ .TerminateLink }}) this NetworkInterface.
+%%%`
+
+const reapableNetworkInterfaceEventText = `%%%
+Reaper has discovered a NetworkInterface qualified as reapable: {{if .NetworkInterface.Name}}"{{.NetworkInterface.Name}}" {{end}}[{{.NetworkInterface.ID}}]({{.NetworkInterface.AWSConsoleURL}}) in region: [{{.NetworkInterface.Region}}](https://{{.NetworkInterface.Region}}.console.aws.amazon.com/ec2/v2/home?region={{.NetworkInterface.Region}}).\n
+{{if .NetworkInterface.Owned}}Owned by {{.NetworkInterface.Owner}}.\n{{end}}
+Status: {{.NetworkInterface.Status}}.\n
+{{if .NetworkInterface.Description}}Description: {{.NetworkInterface.Description}}.\n{{end}}
+{{ if .NetworkInterface.AWSConsoleURL}}{{.NetworkInterface.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.NetworkInterface.AWSConsoleURL}})\n
+[Whitelist]({{ .WhitelistLink }}) this NetworkInterface.
+[Delete]({{ .TerminateLink }}) this NetworkInterface.
+%%%`
+
+// Filter is part of the filter.Filterable interface
+func (a *NetworkInterface) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "Status":
+		// one of:
+		// available
+		// attaching
+		// in-use
+		// detaching
+		if a.Status != nil && *a.Status == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotStatus":
+		if a.Status == nil || *a.Status != filter.Arguments[0] {
+			matched = true
+		}
+	case "RequesterManaged":
+		if b, err := filter.BoolValue(0); err == nil && a.RequesterManaged != nil && *a.RequesterManaged == b {
+			matched = true
+		}
+	case "DescriptionContains":
+		if a.Description != nil && strings.Contains(*a.Description, filter.Arguments[0]) {
+			matched = true
+		}
+	case "AttachTimeInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.Attachment != nil && a.Attachment.AttachTime != nil && time.Since(*a.Attachment.AttachTime) < d {
+			matched = true
+		}
+	case "AttachTimeNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.Attachment != nil && a.Attachment.AttachTime != nil && time.Since(*a.Attachment.AttachTime) > d {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering NetworkInterfaces.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *NetworkInterface) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/ec2/v2/home?region=%s#NIC:search=%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+// attached interfaces cannot be deleted
+func (a *NetworkInterface) Terminate() (bool, error) {
+	log.Info("Terminating NetworkInterface %s", a.ReapableDescriptionTiny())
+	api := ec2.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.DeleteNetworkInterface(&ec2.DeleteNetworkInterfaceInput{
+		NetworkInterfaceId: aws.String(a.ID().String()),
+	})
+	if err != nil {
+		log.Error("could not delete NetworkInterface %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because there is no concept of stopping a network interface
+func (a *NetworkInterface) Stop() (bool, error) {
+	return false, nil
+}
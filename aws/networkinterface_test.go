@@ -0,0 +1,60 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestNetworkInterfaceFilter(t *testing.T) {
+	a := NewNetworkInterface("us-west-2", &ec2.NetworkInterface{
+		NetworkInterfaceId: aws.String("eni-1"),
+		Status:             aws.String("in-use"),
+		RequesterManaged:   aws.Bool(true),
+		Description:        aws.String("ELB web"),
+		Attachment:         &ec2.NetworkInterfaceAttachment{AttachTime: aws.Time(time.Now().Add(-48 * time.Hour))},
+		TagSet: []*ec2.Tag{
+			{Key: aws.String("Name"), Value: aws.String("web")},
+		},
+	})
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"Status", []string{"in-use"}, true},
+		{"Status", []string{"available"}, false},
+		{"NotStatus", []string{"available"}, true},
+		{"RequesterManaged", []string{"true"}, true},
+		{"DescriptionContains", []string{"ELB"}, true},
+		{"DescriptionContains", []string{"NAT"}, false},
+		{"AttachTimeInTheLast", []string{"24h"}, false},
+		{"AttachTimeNotInTheLast", []string{"24h"}, true},
+		// named by its Name tag
+		{"Named", []string{"web"}, true},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
+
+func TestDetachedNetworkInterfaceFilter(t *testing.T) {
+	a := NewNetworkInterface("us-west-2", &ec2.NetworkInterface{
+		NetworkInterfaceId: aws.String("eni-2"),
+		Status:             aws.String("available"),
+	})
+
+	// detached NetworkInterfaces have no age
+	for _, function := range []string{"AttachTimeInTheLast", "AttachTimeNotInTheLast"} {
+		if a.Filter(*filters.NewFilter(function, []string{"24h"})) {
+			t.Errorf("%s(24h) matched a detached NetworkInterface", function)
+		}
+	}
+}
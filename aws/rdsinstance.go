@@ -0,0 +1,425 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// RDSInstance is a Reapable, Filterable
+// embeds AWS API's rds.DBInstance
+type RDSInstance struct {
+	Resource
+	rds.DBInstance
+
+	// ARN is used to tag the RDSInstance
+	ARN string
+}
+
+// NewRDSInstance creates an RDSInstance from the AWS API's rds.DBInstance
+// tags are listed separately by the RDS API
+func NewRDSInstance(region string, db *rds.DBInstance, tags []*rds.Tag) *RDSInstance {
+	a := RDSInstance{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*db.DBInstanceIdentifier),
+			Name:   *db.DBInstanceIdentifier,
+			Tags:   make(map[string]string),
+		},
+		DBInstance: *db,
+		ARN:        rdsInstanceARN(region, *db.DBInstanceIdentifier),
+	}
+
+	for _, tag := range tags {
+		a.Resource.Tags[*tag.Key] = *tag.Value
+	}
+
+	if a.Tagged("aws:cloudformation:stack-name") {
+		a.Dependency = true
+		a.IsInCloudformation = true
+	}
+
+	// read replicas are lost with their source
+	if len(db.ReadReplicaDBInstanceIdentifiers) > 0 {
+		a.Dependency = true
+	}
+
+	if a.Tagged(reaperTag) {
+		// restore previously tagged state
+		a.reaperState = state.NewStateWithTag(a.Tag(reaperTag))
+	} else {
+		// initial state
+		a.reaperState = state.NewState()
+	}
+
+	return &a
+}
+
+func rdsInstanceARN(region, id string) string {
+	return fmt.Sprintf("arn:aws:rds:%s:%s:db:%s", region, accountID(), id)
+}
+
+// TakesFinalSnapshot returns whether a final snapshot is taken when the RDSInstance is terminated
+// AWS does not allow final snapshots of read replicas or of members of an Aurora cluster,
+// whose data outlives them in their source or cluster
+func (a *RDSInstance) TakesFinalSnapshot() bool {
+	return a.ReadReplicaSourceDBInstanceIdentifier == nil && a.DBClusterIdentifier == nil
+}
+
+// FinalSnapshotIdentifier returns the name of the snapshot taken when the RDSInstance is terminated
+// it is known in advance so that owners can be told where to restore from
+func (a *RDSInstance) FinalSnapshotIdentifier() string {
+	return finalSnapshotIdentifier(a.ID(), a.InstanceCreateTime)
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *RDSInstance) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableRDSInstanceEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *RDSInstance) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableRDSInstanceEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *RDSInstance) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableRDSInstanceEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *RDSInstance) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableRDSInstanceEventHTMLShort)
+	return
+}
+
+type rDSInstanceEventData struct {
+	Config        *Config
+	RDSInstance   *RDSInstance
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+	IgnoreLink1   string
+	IgnoreLink3   string
+	IgnoreLink7   string
+}
+
+func (a *RDSInstance) getTemplateData() (interface{}, error) {
+	ignore1, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(1*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore3, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(3*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore7, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rDSInstanceEventData{
+		Config:        config,
+		RDSInstance:   a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+		IgnoreLink1:   ignore1,
+		IgnoreLink3:   ignore3,
+		IgnoreLink7:   ignore7,
+	}, nil
+}
+
+const reapableRDSInstanceEventHTML = `
+<html>
+<body>
+	<p>RDSInstance <a href="{{ .RDSInstance.AWSConsoleURL }}">{{ .RDSInstance.ID }} in {{.RDSInstance.Region}}</a> ({{.RDSInstance.Engine}}, {{.RDSInstance.DBInstanceClass}}) is scheduled to be deleted.</p>
+
+	<p>
+		{{ if .RDSInstance.TakesFinalSnapshot }}A final snapshot named <strong>{{ .RDSInstance.FinalSnapshotIdentifier }}</strong> will be taken before it is deleted. You can restore the database from it in the AWS Console.{{ else }}No final snapshot will be taken, because AWS does not allow it for read replicas or members of an Aurora cluster.{{ end }}
+	</p>
+
+	<p>
+		You can ignore this message and your RDSInstance will advance to the next state after <strong>{{.RDSInstance.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>. If you do not take action it will be deleted!
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+			<li><a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a></li>
+			<li><a href="{{ .IgnoreLink3 }}">Ignore it for 3 more days</a></li>
+			<li><a href="{{ .IgnoreLink7}}">Ignore it for 7 more days</a></li>
+		</ul>
+	</p>
+
+	<p>
+		If you want the Reaper to ignore this RDSInstance tag it with {{ .Config.WhitelistTag }} with any value, or click <a href="{{ .WhitelistLink }}">here</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableRDSInstanceEventHTMLShort = `
+<html>
+<body>
+	<p>RDSInstance <a href="{{ .RDSInstance.AWSConsoleURL }}">{{ .RDSInstance.ID }}</a> in {{.RDSInstance.Region}} is scheduled to be deleted after <strong>{{.RDSInstance.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>{{ if .RDSInstance.TakesFinalSnapshot }}, with a final snapshot named {{ .RDSInstance.FinalSnapshotIdentifier }}{{ end }}.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a>,
+		<a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a>,
+		<a href="{{ .IgnoreLink3 }}">3 days</a>,
+		<a href="{{ .IgnoreLink7}}"> 7 days</a>, or
+		<a href="{{ .WhitelistLink }}">Whitelist</a> it.
+	</p>
+</body>
+</html>
+`
+
+const reapableRDSInstanceEventTextShort = `%%%
+RDSInstance [{{.RDSInstance.ID}}]({{.RDSInstance.AWSConsoleURL}}) in region: [{{.RDSInstance.Region}}](https://{{.RDSInstance.Region}}.console.aws.amazon.com/rds/home?region={{.RDSInstance.Region}}).{{if .RDSInstance.Owned}} Owned by {{.RDSInstance.Owner}}.{{end}}\n
+{{if .RDSInstance.TakesFinalSnapshot}}Final snapshot: {{.RDSInstance.FinalSnapshotIdentifier}}.\n{{end}}
+[Whitelist]({{ .WhitelistLink }}) or [Delete]({{ .TerminateLink }}) this RDSInstance.
+%%%`
+
+const reapableRDSInstanceEventText = `%%%
+Reaper has discovered an RDSInstance qualified as reapable: [{{.RDSInstance.ID}}]({{.RDSInstance.AWSConsoleURL}}) in region: [{{.RDSInstance.Region}}](https://{{.RDSInstance.Region}}.console.aws.amazon.com/rds/home?region={{.RDSInstance.Region}}).\n
+{{if .RDSInstance.Owned}}Owned by {{.RDSInstance.Owner}}.\n{{end}}
+Engine: {{.RDSInstance.Engine}}, class: {{.RDSInstance.DBInstanceClass}}.\n
+{{if .RDSInstance.TakesFinalSnapshot}}A final snapshot named {{.RDSInstance.FinalSnapshotIdentifier}} will be taken before it is deleted.\n{{else}}No final snapshot will be taken.\n{{end}}
+{{ if .RDSInstance.AWSConsoleURL}}{{.RDSInstance.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.RDSInstance.AWSConsoleURL}})\n
+[Whitelist]({{ .WhitelistLink }}) this RDSInstance.
+[Delete]({{ .TerminateLink }}) this RDSInstance.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *RDSInstance) Save(s *state.State) (bool, error) {
+	log.Info("Saving %s", a.ReapableDescriptionTiny())
+	return tagRDSResource(a.Region(), a.ARN, reaperTag, s.RestrictedString())
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *RDSInstance) Unsave() (bool, error) {
+	log.Info("Unsaving %s", a.ReapableDescriptionTiny())
+	return untagRDSResource(a.Region(), a.ARN, reaperTag)
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+func (a *RDSInstance) Whitelist() (bool, error) {
+	log.Info("Whitelisting RDSInstance %s", a.ReapableDescriptionTiny())
+	return tagRDSResource(a.Region(), a.ARN, config.WhitelistTag, "true")
+}
+
+func untagRDSResource(region reapable.Region, arn, key string) (bool, error) {
+	api := rds.New(sess, aws.NewConfig().WithRegion(region.String()))
+	_, err := api.RemoveTagsFromResource(&rds.RemoveTagsFromResourceInput{
+		ResourceName: aws.String(arn),
+		TagKeys:      []*string{aws.String(key)},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func tagRDSResource(region reapable.Region, arn, key, value string) (bool, error) {
+	api := rds.New(sess, aws.NewConfig().WithRegion(region.String()))
+	_, err := api.AddTagsToResource(&rds.AddTagsToResourceInput{
+		ResourceName: aws.String(arn),
+		Tags: []*rds.Tag{
+			&rds.Tag{
+				Key:   aws.String(key),
+				Value: aws.String(value),
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *RDSInstance) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "Engine":
+		if a.Engine != nil && *a.Engine == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotEngine":
+		if a.Engine == nil || *a.Engine != filter.Arguments[0] {
+			matched = true
+		}
+	case "InstanceClass":
+		if a.DBInstanceClass != nil && *a.DBInstanceClass == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotInstanceClass":
+		if a.DBInstanceClass == nil || *a.DBInstanceClass != filter.Arguments[0] {
+			matched = true
+		}
+	case "Status":
+		if a.DBInstanceStatus != nil && *a.DBInstanceStatus == filter.Arguments[0] {
+			matched = true
+		}
+	case "MultiAZ":
+		if b, err := filter.BoolValue(0); err == nil && a.MultiAZ != nil && *a.MultiAZ == b {
+			matched = true
+		}
+	case "IsReadReplica":
+		if b, err := filter.BoolValue(0); err == nil && (a.ReadReplicaSourceDBInstanceIdentifier != nil) == b {
+			matched = true
+		}
+	case "CreatedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.InstanceCreateTime != nil && time.Since(*a.InstanceCreateTime) < d {
+			matched = true
+		}
+	case "CreatedNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.InstanceCreateTime != nil && time.Since(*a.InstanceCreateTime) > d {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering RDSInstances.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *RDSInstance) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/rds/home?region=%s#dbinstance:id=%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+// a final snapshot named FinalSnapshotIdentifier is taken when allowed
+func (a *RDSInstance) Terminate() (bool, error) {
+	log.Info("Terminating RDSInstance %s", a.ReapableDescriptionTiny())
+	api := rds.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	input := &rds.DeleteDBInstanceInput{
+		DBInstanceIdentifier: aws.String(a.ID().String()),
+	}
+	if a.TakesFinalSnapshot() {
+		input.FinalDBSnapshotIdentifier = aws.String(a.FinalSnapshotIdentifier())
+	} else {
+		input.SkipFinalSnapshot = aws.Bool(true)
+	}
+	_, err := api.DeleteDBInstance(input)
+	if err != nil {
+		log.Error("could not delete RDSInstance %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because DB instances cannot be stopped
+func (a *RDSInstance) Stop() (bool, error) {
+	return false, nil
+}
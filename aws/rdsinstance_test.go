@@ -0,0 +1,55 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestRDSInstanceFilter(t *testing.T) {
+	// avoids looking up the account for the ARN
+	account = "123456789012"
+
+	a := NewRDSInstance("us-west-2", &rds.DBInstance{
+		DBInstanceIdentifier:                  aws.String("db"),
+		DBInstanceClass:                       aws.String("db.t2.micro"),
+		DBInstanceStatus:                      aws.String("available"),
+		Engine:                                aws.String("postgres"),
+		MultiAZ:                               aws.Bool(false),
+		InstanceCreateTime:                    aws.Time(time.Now().Add(-48 * time.Hour)),
+		ReadReplicaSourceDBInstanceIdentifier: aws.String("source"),
+	}, []*rds.Tag{
+		{Key: aws.String("aws:cloudformation:stack-name"), Value: aws.String("db")},
+	})
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"Engine", []string{"postgres"}, true},
+		{"NotEngine", []string{"postgres"}, false},
+		{"InstanceClass", []string{"db.t2.micro"}, true},
+		{"NotInstanceClass", []string{"db.m4.large"}, true},
+		{"Status", []string{"available"}, true},
+		{"MultiAZ", []string{"false"}, true},
+		{"MultiAZ", []string{"true"}, false},
+		{"IsReadReplica", []string{"true"}, true},
+		{"CreatedInTheLast", []string{"72h"}, true},
+		{"CreatedNotInTheLast", []string{"24h"}, true},
+		{"InCloudformation", []string{"true"}, true},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+
+	if a.ARN != "arn:aws:rds:us-west-2:123456789012:db:db" {
+		t.Errorf("ARN = %s", a.ARN)
+	}
+}
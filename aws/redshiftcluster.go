@@ -0,0 +1,401 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/redshift"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// RedshiftCluster is a Reapable, Filterable
+// embeds AWS API's redshift.Cluster
+type RedshiftCluster struct {
+	Resource
+	redshift.Cluster
+
+	// ARN is used to tag the RedshiftCluster
+	ARN string
+}
+
+// NewRedshiftCluster creates a RedshiftCluster from the AWS API's redshift.Cluster
+func NewRedshiftCluster(region string, cluster *redshift.Cluster) *RedshiftCluster {
+	a := RedshiftCluster{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*cluster.ClusterIdentifier),
+			Name:   *cluster.ClusterIdentifier,
+			Tags:   make(map[string]string),
+		},
+		Cluster: *cluster,
+		ARN:     fmt.Sprintf("arn:aws:redshift:%s:%s:cluster:%s", region, accountID(), *cluster.ClusterIdentifier),
+	}
+
+	for _, tag := range cluster.Tags {
+		a.Resource.Tags[*tag.Key] = *tag.Value
+	}
+
+	if a.Tagged("aws:cloudformation:stack-name") {
+		a.Dependency = true
+		a.IsInCloudformation = true
+	}
+
+	if a.Tagged(reaperTag) {
+		// restore previously tagged state
+		a.reaperState = state.NewStateWithTag(a.Tag(reaperTag))
+	} else {
+		// initial state
+		a.reaperState = state.NewState()
+	}
+
+	return &a
+}
+
+// FinalSnapshotIdentifier returns the name of the snapshot taken when the RedshiftCluster is terminated
+func (a *RedshiftCluster) FinalSnapshotIdentifier() string {
+	return finalSnapshotIdentifier(a.ID(), a.ClusterCreateTime)
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *RedshiftCluster) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableRedshiftClusterEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *RedshiftCluster) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableRedshiftClusterEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *RedshiftCluster) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableRedshiftClusterEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *RedshiftCluster) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableRedshiftClusterEventHTMLShort)
+	return
+}
+
+type redshiftClusterEventData struct {
+	Config          *Config
+	RedshiftCluster *RedshiftCluster
+	TerminateLink   string
+	StopLink        string
+	WhitelistLink   string
+	IgnoreLink1     string
+	IgnoreLink3     string
+	IgnoreLink7     string
+}
+
+func (a *RedshiftCluster) getTemplateData() (interface{}, error) {
+	ignore1, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(1*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore3, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(3*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore7, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &redshiftClusterEventData{
+		Config:          config,
+		RedshiftCluster: a,
+		TerminateLink:   terminate,
+		StopLink:        stop,
+		WhitelistLink:   whitelist,
+		IgnoreLink1:     ignore1,
+		IgnoreLink3:     ignore3,
+		IgnoreLink7:     ignore7,
+	}, nil
+}
+
+const reapableRedshiftClusterEventHTML = `
+<html>
+<body>
+	<p>RedshiftCluster <a href="{{ .RedshiftCluster.AWSConsoleURL }}">{{ .RedshiftCluster.ID }} in {{.RedshiftCluster.Region}}</a> ({{.RedshiftCluster.NumberOfNodes}} {{.RedshiftCluster.NodeType}} nodes) is scheduled to be deleted.</p>
+
+	<p>
+		A final snapshot named <strong>{{ .RedshiftCluster.FinalSnapshotIdentifier }}</strong> will be taken before it is deleted.
+		To restore the cluster, choose "Restore From Snapshot" on that snapshot in the AWS Console, or run:
+		<pre>aws redshift restore-from-cluster-snapshot --region {{ .RedshiftCluster.Region }} --cluster-identifier {{ .RedshiftCluster.ID }} --snapshot-identifier {{ .RedshiftCluster.FinalSnapshotIdentifier }}</pre>
+	</p>
+
+	<p>
+		You can ignore this message and your RedshiftCluster will advance to the next state after <strong>{{.RedshiftCluster.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>. If you do not take action it will be deleted!
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+			<li><a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a></li>
+			<li><a href="{{ .IgnoreLink3 }}">Ignore it for 3 more days</a></li>
+			<li><a href="{{ .IgnoreLink7}}">Ignore it for 7 more days</a></li>
+		</ul>
+	</p>
+
+	<p>
+		If you want the Reaper to ignore this RedshiftCluster tag it with {{ .Config.WhitelistTag }} with any value, or click <a href="{{ .WhitelistLink }}">here</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableRedshiftClusterEventHTMLShort = `
+<html>
+<body>
+	<p>RedshiftCluster <a href="{{ .RedshiftCluster.AWSConsoleURL }}">{{ .RedshiftCluster.ID }}</a> in {{.RedshiftCluster.Region}} ({{.RedshiftCluster.NumberOfNodes}} {{.RedshiftCluster.NodeType}} nodes) is scheduled to be deleted after <strong>{{.RedshiftCluster.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>. It can be restored from its final snapshot, {{ .RedshiftCluster.FinalSnapshotIdentifier }}.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a>,
+		<a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a>,
+		<a href="{{ .IgnoreLink3 }}">3 days</a>,
+		<a href="{{ .IgnoreLink7}}"> 7 days</a>, or
+		<a href="{{ .WhitelistLink }}">Whitelist</a> it.
+	</p>
+</body>
+</html>
+`
+
+const reapableRedshiftClusterEventTextShort = `%%%
+RedshiftCluster [{{.RedshiftCluster.ID}}]({{.RedshiftCluster.AWSConsoleURL}}) in region: [{{.RedshiftCluster.Region}}](https://{{.RedshiftCluster.Region}}.console.aws.amazon.com/redshift/home?region={{.RedshiftCluster.Region}}).{{if .RedshiftCluster.Owned}} Owned by {{.RedshiftCluster.Owner}}.{{end}}\n
+Final snapshot: {{.RedshiftCluster.FinalSnapshotIdentifier}}.\n
+[Whitelist]({{ .WhitelistLink }}) or [Delete]({{ .TerminateLink }}) this RedshiftCluster.
+%%%`
+
+const reapableRedshiftClusterEventText = `%%%
+Reaper has discovered a RedshiftCluster qualified as reapable: [{{.RedshiftCluster.ID}}]({{.RedshiftCluster.AWSConsoleURL}}) in region: [{{.RedshiftCluster.Region}}](https://{{.RedshiftCluster.Region}}.console.aws.amazon.com/redshift/home?region={{.RedshiftCluster.Region}}).\n
+{{if .RedshiftCluster.Owned}}Owned by {{.RedshiftCluster.Owner}}.\n{{end}}
+{{.RedshiftCluster.NumberOfNodes}} {{.RedshiftCluster.NodeType}} nodes.\n
+A final snapshot named {{.RedshiftCluster.FinalSnapshotIdentifier}} will be taken before it is deleted. Restore it with "Restore From Snapshot" in the AWS Console.\n
+{{ if .RedshiftCluster.AWSConsoleURL}}{{.RedshiftCluster.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.RedshiftCluster.AWSConsoleURL}})\n
+[Whitelist]({{ .WhitelistLink }}) this RedshiftCluster.
+[Delete]({{ .TerminateLink }}) this RedshiftCluster.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *RedshiftCluster) Save(s *state.State) (bool, error) {
+	log.Info("Saving %s", a.ReapableDescriptionTiny())
+	return tagRedshiftCluster(a.Region(), a.ARN, reaperTag, s.String())
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *RedshiftCluster) Unsave() (bool, error) {
+	log.Info("Unsaving %s", a.ReapableDescriptionTiny())
+	return untagRedshiftCluster(a.Region(), a.ARN, reaperTag)
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+func (a *RedshiftCluster) Whitelist() (bool, error) {
+	log.Info("Whitelisting RedshiftCluster %s", a.ReapableDescriptionTiny())
+	return tagRedshiftCluster(a.Region(), a.ARN, config.WhitelistTag, "true")
+}
+
+func untagRedshiftCluster(region reapable.Region, arn, key string) (bool, error) {
+	api := redshift.New(sess, aws.NewConfig().WithRegion(region.String()))
+	_, err := api.DeleteTags(&redshift.DeleteTagsInput{
+		ResourceName: aws.String(arn),
+		TagKeys:      []*string{aws.String(key)},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func tagRedshiftCluster(region reapable.Region, arn, key, value string) (bool, error) {
+	api := redshift.New(sess, aws.NewConfig().WithRegion(region.String()))
+	_, err := api.CreateTags(&redshift.CreateTagsInput{
+		ResourceName: aws.String(arn),
+		Tags: []*redshift.Tag{
+			&redshift.Tag{
+				Key:   aws.String(key),
+				Value: aws.String(value),
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *RedshiftCluster) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "NodeType":
+		if a.NodeType != nil && *a.NodeType == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNodeType":
+		if a.NodeType == nil || *a.NodeType != filter.Arguments[0] {
+			matched = true
+		}
+	case "ClusterStatus":
+		// available, creating, modifying...
+		if a.ClusterStatus != nil && *a.ClusterStatus == filter.Arguments[0] {
+			matched = true
+		}
+	case "NumberOfNodesGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.NumberOfNodes != nil && *a.NumberOfNodes > i {
+			matched = true
+		}
+	case "NumberOfNodesLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.NumberOfNodes != nil && *a.NumberOfNodes < i {
+			matched = true
+		}
+	case "NumberOfNodesEqualTo":
+		if i, err := filter.Int64Value(0); err == nil && a.NumberOfNodes != nil && *a.NumberOfNodes == i {
+			matched = true
+		}
+	case "CreatedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.ClusterCreateTime != nil && time.Since(*a.ClusterCreateTime) < d {
+			matched = true
+		}
+	case "CreatedNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.ClusterCreateTime != nil && time.Since(*a.ClusterCreateTime) > d {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering RedshiftClusters.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *RedshiftCluster) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/redshift/home?region=%s#cluster-details:cluster=%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+// a final snapshot named FinalSnapshotIdentifier is always taken
+func (a *RedshiftCluster) Terminate() (bool, error) {
+	log.Info("Terminating RedshiftCluster %s", a.ReapableDescriptionTiny())
+	api := redshift.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	_, err := api.DeleteCluster(&redshift.DeleteClusterInput{
+		ClusterIdentifier:              aws.String(a.ID().String()),
+		FinalClusterSnapshotIdentifier: aws.String(a.FinalSnapshotIdentifier()),
+	})
+	if err != nil {
+		log.Error("could not delete RedshiftCluster %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because Redshift clusters cannot be stopped
+func (a *RedshiftCluster) Stop() (bool, error) {
+	return false, nil
+}
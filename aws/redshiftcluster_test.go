@@ -0,0 +1,46 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/redshift"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestRedshiftClusterFilter(t *testing.T) {
+	// avoids looking up the account for the ARN
+	account = "123456789012"
+
+	a := NewRedshiftCluster("us-west-2", &redshift.Cluster{
+		ClusterIdentifier: aws.String("warehouse"),
+		NodeType:          aws.String("dc1.large"),
+		ClusterStatus:     aws.String("available"),
+		NumberOfNodes:     aws.Int64(2),
+		ClusterCreateTime: aws.Time(time.Now().Add(-48 * time.Hour)),
+	})
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"NodeType", []string{"dc1.large"}, true},
+		{"NotNodeType", []string{"ds2.xlarge"}, true},
+		{"ClusterStatus", []string{"available"}, true},
+		{"ClusterStatus", []string{"creating"}, false},
+		{"NumberOfNodesGreaterThan", []string{"2"}, false},
+		{"NumberOfNodesLessThan", []string{"3"}, true},
+		{"NumberOfNodesEqualTo", []string{"2"}, true},
+		{"NumberOfNodesEqualTo", []string{"two"}, false},
+		{"CreatedInTheLast", []string{"72h"}, true},
+		{"CreatedNotInTheLast", []string{"72h"}, false},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
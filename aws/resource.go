@@ -186,6 +186,15 @@ func reapableEventText(a templater, text string) (*bytes.Buffer, error) {
 	return buf, nil
 }
 
+// finalSnapshotIdentifier names the snapshot taken when a database is terminated
+// it is derived from the resource's id and creation time, so that it is known in advance
+func finalSnapshotIdentifier(id reapable.ID, created *time.Time) string {
+	if created == nil {
+		return fmt.Sprintf("%s-reaper-final", id)
+	}
+	return fmt.Sprintf("%s-reaper-final-%s", id, created.UTC().Format("20060102150405"))
+}
+
 // ReapableDescription is a method of reapable.Reapable
 func (a *Resource) ReapableDescription() string {
 	return fmt.Sprintf("%s matched %s", a.ReapableDescriptionShort(), a.MatchedFiltersString())
@@ -0,0 +1,22 @@
+package aws
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFinalSnapshotIdentifier(t *testing.T) {
+	created := time.Date(2016, 6, 1, 15, 4, 5, 0, time.FixedZone("PDT", -7*60*60))
+	for _, test := range []struct {
+		created    *time.Time
+		identifier string
+	}{
+		{nil, "db-reaper-final"},
+		// in UTC
+		{&created, "db-reaper-final-20160601220405"},
+	} {
+		if identifier := finalSnapshotIdentifier("db", test.created); identifier != test.identifier {
+			t.Errorf("finalSnapshotIdentifier(db, %v) = %s, want %s", test.created, identifier, test.identifier)
+		}
+	}
+}
@@ -1,54 +1,395 @@
 package aws
 
 import (
+	"bytes"
 	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+
 	"github.com/mozilla-services/reaper/filters"
 	"github.com/mozilla-services/reaper/reapable"
 	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
 )
 
+// Snapshot is a Reapable, Filterable
+// embeds AWS API's ec2.Snapshot
 type Snapshot struct {
 	Resource
-	SizeGB        int64
-	SnapshotState string
-	VolumeID      reapable.ID
-	LaunchTime    time.Time
+	ec2.Snapshot
+
+	// whether the volume the snapshot was created from still exists
+	VolumeExists bool
+	// whether the snapshot backs an AMI
+	UsedByImage bool
 }
 
+// NewSnapshot creates a Snapshot from the AWS API's ec2.Snapshot
 func NewSnapshot(region string, s *ec2.Snapshot) *Snapshot {
-	snap := Snapshot{
+	a := Snapshot{
 		Resource: Resource{
 			id:     reapable.ID(*s.SnapshotId),
 			region: reapable.Region(region),
 			Tags:   make(map[string]string),
 		},
-		SizeGB:        *s.VolumeSize,
-		SnapshotState: *s.State,
-		VolumeID:      reapable.ID(*s.VolumeId),
-		LaunchTime:    *s.StartTime,
+		Snapshot: *s,
 	}
 
 	for _, tag := range s.Tags {
-		snap.Tags[*tag.Key] = *tag.Value
+		a.Resource.Tags[*tag.Key] = *tag.Value
+	}
+
+	if a.Tagged("aws:cloudformation:stack-name") {
+		a.Dependency = true
+		a.IsInCloudformation = true
+	}
+
+	a.Name = a.Tag("Name")
+
+	if a.Tagged(reaperTag) {
+		// restore previously tagged state
+		a.reaperState = state.NewStateWithTag(a.Tag(reaperTag))
+	} else {
+		// initial state
+		a.reaperState = state.NewState()
+	}
+
+	return &a
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *Snapshot) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableSnapshotEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *Snapshot) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableSnapshotEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *Snapshot) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
 	}
 
-	if snap.Tagged("aws:cloudformation:stack-name") {
-		snap.Dependency = true
-		snap.IsInCloudformation = true
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableSnapshotEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *Snapshot) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
 	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableSnapshotEventHTMLShort)
+	return
+}
 
-	return &snap
+type snapshotEventData struct {
+	Config        *Config
+	Snapshot      *Snapshot
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+	IgnoreLink1   string
+	IgnoreLink3   string
+	IgnoreLink7   string
 }
 
-func (s *Snapshot) Filter(filter filters.Filter) bool {
+func (a *Snapshot) getTemplateData() (interface{}, error) {
+	ignore1, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(1*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore3, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(3*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore7, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &snapshotEventData{
+		Config:        config,
+		Snapshot:      a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+		IgnoreLink1:   ignore1,
+		IgnoreLink3:   ignore3,
+		IgnoreLink7:   ignore7,
+	}, nil
+}
+
+const reapableSnapshotEventHTML = `
+<html>
+<body>
+	<p>Snapshot <a href="{{ .Snapshot.AWSConsoleURL }}">{{ if .Snapshot.Name }}"{{.Snapshot.Name}}" {{ end }}{{.Snapshot.ID}} in {{.Snapshot.Region}}</a> is scheduled to be deleted.</p>
+
+	<p>
+		This snapshot of {{.Snapshot.VolumeSize}} GiB was started at {{.Snapshot.StartTime.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}{{ if .Snapshot.VolumeExists }}.{{ else }} and the volume it was created from no longer exists.{{ end }}
+	</p>
+
+	<p>
+		You can ignore this message and your Snapshot will advance to the next state after <strong>{{.Snapshot.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>. If you do not take action it will be deleted!
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Delete it now</a></li>
+			<li><a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a></li>
+			<li><a href="{{ .IgnoreLink3 }}">Ignore it for 3 more days</a></li>
+			<li><a href="{{ .IgnoreLink7}}">Ignore it for 7 more days</a></li>
+		</ul>
+	</p>
+
+	<p>
+		If you want the Reaper to ignore this Snapshot tag it with {{ .Config.WhitelistTag }} with any value, or click <a href="{{ .WhitelistLink }}">here</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableSnapshotEventHTMLShort = `
+<html>
+<body>
+	<p>Snapshot <a href="{{ .Snapshot.AWSConsoleURL }}">{{ if .Snapshot.Name }}"{{.Snapshot.Name}}" {{ end }}{{.Snapshot.ID}}</a> ({{.Snapshot.VolumeSize}} GiB) in {{.Snapshot.Region}} is scheduled to be deleted after <strong>{{.Snapshot.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>.
+		<br />
+		<a href="{{ .TerminateLink }}">Delete</a>,
+		<a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a>,
+		<a href="{{ .IgnoreLink3 }}">3 days</a>,
+		<a href="{{ .IgnoreLink7}}"> 7 days</a>, or
+		<a href="{{ .WhitelistLink }}">Whitelist</a> it.
+	</p>
+</body>
+</html>
+`
+
+const reapableSnapshotEventTextShort = `%%%
+Snapshot {{if .Snapshot.Name}}"{{.Snapshot.Name}}" {{end}}[{{.Snapshot.ID}}]({{.Snapshot.AWSConsoleURL}}) in region: [{{.Snapshot.Region}}](https://{{.Snapshot.Region}}.console.aws.amazon.com/ec2/v2/home?region={{.Snapshot.Region}}).{{if .Snapshot.Owned}} Owned by {{.Snapshot.Owner}}.{{end}}\n
+Size: {{.Snapshot.VolumeSize}} GiB, {{.Snapshot.State}}.\n
+[Whitelist]({{ .WhitelistLink }}) or [Delete]({{ .TerminateLink }}) this Snapshot.
+%%%`
+
+const reapableSnapshotEventText = `%%%
+Reaper has discovered a Snapshot qualified as reapable: {{if .Snapshot.Name}}"{{.Snapshot.Name}}" {{end}}[{{.Snapshot.ID}}]({{.Snapshot.AWSConsoleURL}}) in region: [{{.Snapshot.Region}}](https://{{.Snapshot.Region}}.console.aws.amazon.com/ec2/v2/home?region={{.Snapshot.Region}}).\n
+{{if .Snapshot.Owned}}Owned by {{.Snapshot.Owner}}.\n{{end}}
+State: {{.Snapshot.State}}.\n
+Size: {{.Snapshot.VolumeSize}} GiB.\n
+{{if .Snapshot.VolumeId}}Volume: {{.Snapshot.VolumeId}}{{if not .Snapshot.VolumeExists}} (deleted){{end}}.\n{{end}}
+{{ if .Snapshot.AWSConsoleURL}}{{.Snapshot.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.Snapshot.AWSConsoleURL}})\n
+[Whitelist]({{ .WhitelistLink }}) this Snapshot.
+[Delete]({{ .TerminateLink }}) this Snapshot.
+%%%`
+
+func (a *Snapshot) sizeGreaterThanOrEqualTo(size int64) bool {
+	if a.VolumeSize != nil {
+		return *a.VolumeSize >= size
+	}
+	return false
+}
+
+func (a *Snapshot) sizeLessThanOrEqualTo(size int64) bool {
+	if a.VolumeSize != nil {
+		return *a.VolumeSize <= size
+	}
+	return false
+}
+
+func (a *Snapshot) sizeEqualTo(size int64) bool {
+	if a.VolumeSize != nil {
+		return *a.VolumeSize == size
+	}
+	return false
+}
+
+func (a *Snapshot) sizeLessThan(size int64) bool {
+	if a.VolumeSize != nil {
+		return *a.VolumeSize < size
+	}
+	return false
+}
+
+func (a *Snapshot) sizeGreaterThan(size int64) bool {
+	if a.VolumeSize != nil {
+		return *a.VolumeSize > size
+	}
+	return false
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *Snapshot) Filter(filter filters.Filter) bool {
 	matched := false
 	// map function names to function calls
 	switch filter.Function {
+	case "SizeGreaterThan":
+		if i, err := filter.Int64Value(0); err == nil && a.sizeGreaterThan(i) {
+			matched = true
+		}
+	case "SizeLessThan":
+		if i, err := filter.Int64Value(0); err == nil && a.sizeLessThan(i) {
+			matched = true
+		}
+	case "SizeEqualTo":
+		if i, err := filter.Int64Value(0); err == nil && a.sizeEqualTo(i) {
+			matched = true
+		}
+	case "SizeLessThanOrEqualTo":
+		if i, err := filter.Int64Value(0); err == nil && a.sizeLessThanOrEqualTo(i) {
+			matched = true
+		}
+	case "SizeGreaterThanOrEqualTo":
+		if i, err := filter.Int64Value(0); err == nil && a.sizeGreaterThanOrEqualTo(i) {
+			matched = true
+		}
+	case "State":
+		// one of:
+		// pending
+		// completed
+		// error
+		if a.State != nil && *a.State == filter.Arguments[0] {
+			matched = true
+		}
+	case "VolumeExists":
+		if b, err := filter.BoolValue(0); err == nil && a.VolumeExists == b {
+			matched = true
+		}
+	case "UsedByImage":
+		if b, err := filter.BoolValue(0); err == nil && a.UsedByImage == b {
+			matched = true
+		}
+	case "CreatedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.StartTime != nil && time.Since(*a.StartTime) < d {
+			matched = true
+		}
+	case "CreatedNotInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.StartTime != nil && time.Since(*a.StartTime) > d {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
 	default:
-		log.Error(fmt.Sprintf("No function %s could be found for filtering Snapshots.", filter.Function))
+		log.Error("No function %s could be found for filtering Snapshots.", filter.Function)
 	}
 	return matched
 }
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *Snapshot) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/ec2/v2/home?region=%s#Snapshots:visibility=owned-by-me;snapshotId=%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+func (a *Snapshot) Terminate() (bool, error) {
+	log.Info("Terminating Snapshot %s", a.ReapableDescriptionTiny())
+	api := ec2.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	input := &ec2.DeleteSnapshotInput{
+		SnapshotId: aws.String(a.ID().String()),
+	}
+	_, err := api.DeleteSnapshot(input)
+	if err != nil {
+		log.Error("could not delete Snapshot %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// noop because there is no concept of stopping a snapshot
+func (a *Snapshot) Stop() (bool, error) {
+	return false, nil
+}
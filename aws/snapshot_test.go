@@ -0,0 +1,63 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestSnapshotFilter(t *testing.T) {
+	a := NewSnapshot("us-west-2", &ec2.Snapshot{
+		SnapshotId: aws.String("snap-1234"),
+		VolumeId:   aws.String("vol-1234"),
+		VolumeSize: aws.Int64(8),
+		State:      aws.String("completed"),
+		StartTime:  aws.Time(time.Now().Add(-48 * time.Hour)),
+		Tags: []*ec2.Tag{
+			{Key: aws.String("Name"), Value: aws.String("backup")},
+		},
+	})
+	a.UsedByImage = true
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"SizeGreaterThan", []string{"4"}, true},
+		{"SizeLessThan", []string{"8"}, false},
+		{"SizeLessThanOrEqualTo", []string{"8"}, true},
+		{"State", []string{"completed"}, true},
+		{"State", []string{"pending"}, false},
+		{"VolumeExists", []string{"false"}, true},
+		{"UsedByImage", []string{"true"}, true},
+		{"CreatedInTheLast", []string{"24h"}, false},
+		{"CreatedNotInTheLast", []string{"24h"}, true},
+		{"Named", []string{"backup"}, true},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
+
+func TestSnapshotInCloudformation(t *testing.T) {
+	a := NewSnapshot("us-west-2", &ec2.Snapshot{
+		SnapshotId: aws.String("snap-5678"),
+		Tags: []*ec2.Tag{
+			{Key: aws.String("aws:cloudformation:stack-name"), Value: aws.String("web")},
+		},
+	})
+	// snapshots of a stack are deleted with it
+	if !a.IsInCloudformation || !a.Dependency {
+		t.Error("a Snapshot tagged with a stack name is not in a Cloudformation")
+	}
+	// unknown start time
+	if a.Filter(*filters.NewFilter("CreatedNotInTheLast", []string{"1h"})) {
+		t.Error("CreatedNotInTheLast matched a Snapshot without a start time")
+	}
+}
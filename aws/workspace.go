@@ -0,0 +1,580 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/workspaces"
+
+	"github.com/mozilla-services/reaper/filters"
+	"github.com/mozilla-services/reaper/reapable"
+	log "github.com/mozilla-services/reaper/reaperlog"
+	"github.com/mozilla-services/reaper/state"
+)
+
+// WorkSpace is a Reapable, Filterable
+// embeds AWS API's workspaces.Workspace
+type WorkSpace struct {
+	Resource
+	workspaces.Workspace
+
+	// ALWAYS_ON or AUTO_STOP, empty if unknown
+	RunningMode string
+
+	// whether the connection status of the WorkSpace is known
+	ConnectionStatusKnown bool
+	// when the user last connected, nil if they never did
+	LastKnownUserConnection *time.Time
+}
+
+// NewWorkSpace creates a WorkSpace from the AWS API's workspaces.Workspace
+func NewWorkSpace(region string, workspace *workspaces.Workspace, runningMode string, connectionStatusKnown bool, lastKnownUserConnection *time.Time, tags []*workspaces.Tag) *WorkSpace {
+	a := WorkSpace{
+		Resource: Resource{
+			region: reapable.Region(region),
+			id:     reapable.ID(*workspace.WorkspaceId),
+			Name:   *workspace.WorkspaceId,
+			Tags:   make(map[string]string),
+		},
+		Workspace:               *workspace,
+		RunningMode:             runningMode,
+		ConnectionStatusKnown:   connectionStatusKnown,
+		LastKnownUserConnection: lastKnownUserConnection,
+	}
+
+	for _, tag := range tags {
+		if tag.Value != nil {
+			a.Resource.Tags[*tag.Key] = *tag.Value
+		} else {
+			a.Resource.Tags[*tag.Key] = ""
+		}
+	}
+
+	if a.Tagged("aws:cloudformation:stack-name") {
+		a.Dependency = true
+		a.IsInCloudformation = true
+	}
+
+	if a.Tagged(reaperTag) {
+		// restore previously tagged state
+		a.reaperState = state.NewStateWithTag(a.Tag(reaperTag))
+	} else {
+		// initial state
+		a.reaperState = state.NewState()
+	}
+
+	return &a
+}
+
+// Owner maps the WorkSpace's user name to an address at the DefaultEmailHost
+// the same way Resource.Owner maps an Owner tag, which takes precedence
+func (a *WorkSpace) Owner() *mail.Address {
+	if !a.Tagged("Owner") && a.UserName != nil && config.DefaultEmailHost != "" {
+		if addr, err := mail.ParseAddress(fmt.Sprintf("%s@%s", *a.UserName, config.DefaultEmailHost)); err == nil {
+			return addr
+		}
+	}
+	return a.Resource.Owner()
+}
+
+// Owned returns whether the WorkSpace has a clear owner
+func (a *WorkSpace) Owned() bool {
+	return a.Resource.Owned() || (a.UserName != nil && config.DefaultEmailHost != "")
+}
+
+// the vendored WorkSpaces client predates running modes, StopWorkspaces
+// and DescribeWorkspacesConnectionStatus, so they are requested directly
+
+type workspaceProperties struct {
+	_ struct{} `type:"structure"`
+
+	RunningMode *string `type:"string"`
+}
+
+type workspaceWithProperties struct {
+	_ struct{} `type:"structure"`
+
+	WorkspaceId         *string              `type:"string"`
+	WorkspaceProperties *workspaceProperties `type:"structure"`
+}
+
+type describeWorkspacePropertiesOutput struct {
+	_ struct{} `type:"structure"`
+
+	NextToken  *string                    `type:"string"`
+	Workspaces []*workspaceWithProperties `type:"list"`
+}
+
+type describeWorkspacesConnectionStatusInput struct {
+	_ struct{} `type:"structure"`
+
+	NextToken    *string   `type:"string"`
+	WorkspaceIds []*string `type:"list"`
+}
+
+type workspaceConnectionStatus struct {
+	_ struct{} `type:"structure"`
+
+	LastKnownUserConnectionTimestamp *time.Time `type:"timestamp" timestampFormat:"unix"`
+	WorkspaceId                      *string    `type:"string"`
+}
+
+type describeWorkspacesConnectionStatusOutput struct {
+	_ struct{} `type:"structure"`
+
+	NextToken                  *string                      `type:"string"`
+	WorkspacesConnectionStatus []*workspaceConnectionStatus `type:"list"`
+}
+
+type stopRequest struct {
+	_ struct{} `type:"structure"`
+
+	WorkspaceId *string `type:"string"`
+}
+
+type stopWorkspacesInput struct {
+	_ struct{} `type:"structure"`
+
+	StopWorkspaceRequests []*stopRequest `type:"list"`
+}
+
+type stopWorkspacesOutput struct {
+	_ struct{} `type:"structure"`
+
+	FailedRequests []*workspaces.FailedWorkspaceChangeRequest `type:"list"`
+}
+
+// workspacesRequest sends a WorkSpaces operation that the vendored client does not have
+func workspacesRequest(api *workspaces.WorkSpaces, operation string, input, output interface{}) error {
+	return api.NewRequest(&request.Operation{
+		Name:       operation,
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}, input, output).Send()
+}
+
+// workspaceRunningModes returns the running mode of each WorkSpace in a region
+func workspaceRunningModes(api *workspaces.WorkSpaces, region string) map[string]string {
+	modes := make(map[string]string)
+	input := &workspaces.DescribeWorkspacesInput{}
+	for {
+		output := &describeWorkspacePropertiesOutput{}
+		if err := workspacesRequest(api, "DescribeWorkspaces", input, output); err != nil {
+			log.Error("Error describing the running modes of WorkSpaces in %s: %s", region, err.Error())
+			return modes
+		}
+		for _, workspace := range output.Workspaces {
+			if workspace.WorkspaceProperties != nil && workspace.WorkspaceProperties.RunningMode != nil {
+				modes[*workspace.WorkspaceId] = *workspace.WorkspaceProperties.RunningMode
+			}
+		}
+		if output.NextToken == nil {
+			return modes
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// workspaceConnectionStatuses returns the connection status of each WorkSpace in a region
+func workspaceConnectionStatuses(api *workspaces.WorkSpaces, region string) map[string]*workspaceConnectionStatus {
+	statuses := make(map[string]*workspaceConnectionStatus)
+	input := &describeWorkspacesConnectionStatusInput{}
+	for {
+		output := &describeWorkspacesConnectionStatusOutput{}
+		if err := workspacesRequest(api, "DescribeWorkspacesConnectionStatus", input, output); err != nil {
+			log.Error("Error describing the connection status of WorkSpaces in %s: %s", region, err.Error())
+			return statuses
+		}
+		for _, status := range output.WorkspacesConnectionStatus {
+			statuses[*status.WorkspaceId] = status
+		}
+		if output.NextToken == nil {
+			return statuses
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// ReapableEventText is part of the events.Reapable interface
+func (a *WorkSpace) ReapableEventText() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableWorkSpaceEventText)
+}
+
+// ReapableEventTextShort is part of the events.Reapable interface
+func (a *WorkSpace) ReapableEventTextShort() (*bytes.Buffer, error) {
+	return reapableEventText(a, reapableWorkSpaceEventTextShort)
+}
+
+// ReapableEventEmail is part of the events.Reapable interface
+func (a *WorkSpace) ReapableEventEmail() (owner mail.Address, subject string, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+
+	subject = fmt.Sprintf("AWS Resource %s is going to be Reaped!", a.ReapableDescriptionTiny())
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableWorkSpaceEventHTML)
+	return
+}
+
+// ReapableEventEmailShort is part of the events.Reapable interface
+func (a *WorkSpace) ReapableEventEmailShort() (owner mail.Address, body *bytes.Buffer, err error) {
+	// if unowned, return unowned error
+	if !a.Owned() {
+		err = reapable.UnownedError{ErrorText: fmt.Sprintf("%s does not have an owner tag", a.ReapableDescriptionShort())}
+		return
+	}
+	owner = *a.Owner()
+	body, err = reapableEventHTML(a, reapableWorkSpaceEventHTMLShort)
+	return
+}
+
+type workSpaceEventData struct {
+	Config        *Config
+	WorkSpace     *WorkSpace
+	TerminateLink string
+	StopLink      string
+	WhitelistLink string
+	IgnoreLink1   string
+	IgnoreLink3   string
+	IgnoreLink7   string
+}
+
+func (a *WorkSpace) getTemplateData() (interface{}, error) {
+	ignore1, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(1*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore3, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(3*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	ignore7, err := makeIgnoreLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL, time.Duration(7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	terminate, err := makeTerminateLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := makeStopLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+	whitelist, err := makeWhitelistLink(a, config.HTTP.TokenSecret, config.HTTP.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &workSpaceEventData{
+		Config:        config,
+		WorkSpace:     a,
+		TerminateLink: terminate,
+		StopLink:      stop,
+		WhitelistLink: whitelist,
+		IgnoreLink1:   ignore1,
+		IgnoreLink3:   ignore3,
+		IgnoreLink7:   ignore7,
+	}, nil
+}
+
+const reapableWorkSpaceEventHTML = `
+<html>
+<body>
+	<p>WorkSpace <a href="{{ .WorkSpace.AWSConsoleURL }}">{{ .WorkSpace.ID }} in {{.WorkSpace.Region}}</a> of {{ .WorkSpace.UserName }} is scheduled to be terminated.</p>
+
+	<p>
+		It is {{ .WorkSpace.State }}{{ if .WorkSpace.RunningMode }} and {{ .WorkSpace.RunningMode }}{{ end }}. {{ if .WorkSpace.LastKnownUserConnection }}{{ .WorkSpace.UserName }} last connected on {{ .WorkSpace.LastKnownUserConnection.UTC.Format "Jan 2, 2006" }}.{{ else if .WorkSpace.ConnectionStatusKnown }}{{ .WorkSpace.UserName }} never connected to it.{{ end }}
+		Its user volume will be deleted with it.
+	</p>
+
+	<p>
+		You can ignore this message and your WorkSpace will advance to the next state after <strong>{{.WorkSpace.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>. If you do not take action it will be terminated!
+	</p>
+
+	<p>
+		You may also choose to:
+		<ul>
+			<li><a href="{{ .TerminateLink }}">Terminate it now</a></li>
+			<li><a href="{{ .StopLink }}">Stop it</a></li>
+			<li><a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a></li>
+			<li><a href="{{ .IgnoreLink3 }}">Ignore it for 3 more days</a></li>
+			<li><a href="{{ .IgnoreLink7}}">Ignore it for 7 more days</a></li>
+		</ul>
+	</p>
+
+	<p>
+		If you want the Reaper to ignore this WorkSpace tag it with {{ .Config.WhitelistTag }} with any value, or click <a href="{{ .WhitelistLink }}">here</a>.
+	</p>
+</body>
+</html>
+`
+
+const reapableWorkSpaceEventHTMLShort = `
+<html>
+<body>
+	<p>WorkSpace <a href="{{ .WorkSpace.AWSConsoleURL }}">{{ .WorkSpace.ID }}</a> of {{ .WorkSpace.UserName }} in {{.WorkSpace.Region}} is scheduled to be terminated after <strong>{{.WorkSpace.ReaperState.Until.UTC.Format "Jan 2, 2006 at 3:04pm (MST)"}}</strong>.
+		<br />
+		<a href="{{ .TerminateLink }}">Terminate</a>,
+		<a href="{{ .StopLink }}">Stop</a>,
+		<a href="{{ .IgnoreLink1 }}">Ignore it for 1 more day</a>,
+		<a href="{{ .IgnoreLink3 }}">3 days</a>,
+		<a href="{{ .IgnoreLink7}}"> 7 days</a>, or
+		<a href="{{ .WhitelistLink }}">Whitelist</a> it.
+	</p>
+</body>
+</html>
+`
+
+const reapableWorkSpaceEventTextShort = `%%%
+WorkSpace [{{.WorkSpace.ID}}]({{.WorkSpace.AWSConsoleURL}}) of {{.WorkSpace.UserName}} in region: [{{.WorkSpace.Region}}](https://{{.WorkSpace.Region}}.console.aws.amazon.com/workspaces/home?region={{.WorkSpace.Region}}).{{if .WorkSpace.Owned}} Owned by {{.WorkSpace.Owner}}.{{end}}\n
+[Whitelist]({{ .WhitelistLink }}), [Stop]({{ .StopLink }}), or [Terminate]({{ .TerminateLink }}) this WorkSpace.
+%%%`
+
+const reapableWorkSpaceEventText = `%%%
+Reaper has discovered a WorkSpace qualified as reapable: [{{.WorkSpace.ID}}]({{.WorkSpace.AWSConsoleURL}}) in region: [{{.WorkSpace.Region}}](https://{{.WorkSpace.Region}}.console.aws.amazon.com/workspaces/home?region={{.WorkSpace.Region}}).\n
+{{if .WorkSpace.Owned}}Owned by {{.WorkSpace.Owner}}.\n{{end}}
+User: {{.WorkSpace.UserName}}, state: {{.WorkSpace.State}}{{if .WorkSpace.RunningMode}}, running mode: {{.WorkSpace.RunningMode}}{{end}}, bundle: {{.WorkSpace.BundleId}}.\n
+{{if .WorkSpace.LastKnownUserConnection}}Last connection: {{.WorkSpace.LastKnownUserConnection.UTC.Format "Jan 2, 2006"}}.\n{{end}}
+{{ if .WorkSpace.AWSConsoleURL}}{{.WorkSpace.AWSConsoleURL}}\n{{end}}
+[AWS Console URL]({{.WorkSpace.AWSConsoleURL}})\n
+[Whitelist]({{ .WhitelistLink }}) this WorkSpace.
+[Stop]({{ .StopLink }}) this WorkSpace.
+[Terminate]({{ .TerminateLink }}) this WorkSpace.
+%%%`
+
+// Save is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *WorkSpace) Save(s *state.State) (bool, error) {
+	log.Info("Saving %s", a.ReapableDescriptionTiny())
+	return tagWorkSpace(a.Region(), a.ID(), reaperTag, s.RestrictedString())
+}
+
+// Unsave is part of reapable.Saveable, which embedded in reapable.Reapable
+func (a *WorkSpace) Unsave() (bool, error) {
+	log.Info("Unsaving %s", a.ReapableDescriptionTiny())
+	return untagWorkSpace(a.Region(), a.ID(), reaperTag)
+}
+
+// Whitelist is a method of reapable.Whitelistable, which is embedded in reapable.Reapable
+func (a *WorkSpace) Whitelist() (bool, error) {
+	log.Info("Whitelisting WorkSpace %s", a.ReapableDescriptionTiny())
+	return tagWorkSpace(a.Region(), a.ID(), config.WhitelistTag, "true")
+}
+
+func untagWorkSpace(region reapable.Region, id reapable.ID, key string) (bool, error) {
+	api := workspaces.New(sess, aws.NewConfig().WithRegion(region.String()))
+	_, err := api.DeleteTags(&workspaces.DeleteTagsInput{
+		ResourceId: aws.String(id.String()),
+		TagKeys:    []*string{aws.String(key)},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func tagWorkSpace(region reapable.Region, id reapable.ID, key, value string) (bool, error) {
+	api := workspaces.New(sess, aws.NewConfig().WithRegion(region.String()))
+	_, err := api.CreateTags(&workspaces.CreateTagsInput{
+		ResourceId: aws.String(id.String()),
+		Tags: []*workspaces.Tag{
+			&workspaces.Tag{
+				Key:   aws.String(key),
+				Value: aws.String(value),
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Filter is part of the filter.Filterable interface
+func (a *WorkSpace) Filter(filter filters.Filter) bool {
+	matched := false
+	// map function names to function calls
+	switch filter.Function {
+	case "State":
+		// one of:
+		// PENDING
+		// AVAILABLE
+		// IMPAIRED
+		// UNHEALTHY
+		// REBOOTING
+		// STARTING
+		// REBUILDING
+		// MAINTENANCE
+		// TERMINATING
+		// SUSPENDED
+		// STOPPING
+		// STOPPED
+		// ERROR
+		if a.State != nil && *a.State == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotState":
+		if a.State != nil && *a.State != filter.Arguments[0] {
+			matched = true
+		}
+	case "RunningMode":
+		// ALWAYS_ON or AUTO_STOP
+		if a.RunningMode == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotRunningMode":
+		if a.RunningMode != "" && a.RunningMode != filter.Arguments[0] {
+			matched = true
+		}
+	case "Bundle":
+		if a.BundleId != nil && *a.BundleId == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotBundle":
+		if a.BundleId != nil && *a.BundleId != filter.Arguments[0] {
+			matched = true
+		}
+	case "UserName":
+		if a.UserName != nil && *a.UserName == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotUserName":
+		if a.UserName != nil && *a.UserName != filter.Arguments[0] {
+			matched = true
+		}
+	case "ConnectedInTheLast":
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.LastKnownUserConnection != nil && time.Since(*a.LastKnownUserConnection) < d {
+			matched = true
+		}
+	case "NotConnectedInTheLast":
+		// WorkSpaces whose user never connected match, unless their connection status is unknown
+		d, err := time.ParseDuration(filter.Arguments[0])
+		if err == nil && a.ConnectionStatusKnown &&
+			(a.LastKnownUserConnection == nil || time.Since(*a.LastKnownUserConnection) > d) {
+			matched = true
+		}
+	case "InCloudformation":
+		if b, err := filter.BoolValue(0); err == nil && a.IsInCloudformation == b {
+			matched = true
+		}
+	case "Region":
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				matched = true
+			}
+		}
+	case "NotRegion":
+		// was this resource's region one of those in the NOT list
+		regionSpecified := false
+		for _, region := range filter.Arguments {
+			if a.Region() == reapable.Region(region) {
+				regionSpecified = true
+			}
+		}
+		if !regionSpecified {
+			matched = true
+		}
+	case "Tagged":
+		if a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotTagged":
+		if !a.Tagged(filter.Arguments[0]) {
+			matched = true
+		}
+	case "TagNotEqual":
+		if a.Tag(filter.Arguments[0]) != filter.Arguments[1] {
+			matched = true
+		}
+	case "ReaperState":
+		if a.reaperState.State.String() == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotReaperState":
+		if a.reaperState.State.String() != filter.Arguments[0] {
+			matched = true
+		}
+	case "Named":
+		if a.Name == filter.Arguments[0] {
+			matched = true
+		}
+	case "NotNamed":
+		if a.Name != filter.Arguments[0] {
+			matched = true
+		}
+	case "IsDependency":
+		if b, err := filter.BoolValue(0); err == nil && a.Dependency == b {
+			matched = true
+		}
+	case "NameContains":
+		if strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	case "NotNameContains":
+		if !strings.Contains(a.Name, filter.Arguments[0]) {
+			matched = true
+		}
+	default:
+		log.Error("No function %s could be found for filtering WorkSpaces.", filter.Function)
+	}
+	return matched
+}
+
+// AWSConsoleURL returns the url that can be used to access the resource on the AWS Console
+func (a *WorkSpace) AWSConsoleURL() *url.URL {
+	url, err := url.Parse(fmt.Sprintf("https://%s.console.aws.amazon.com/workspaces/home?region=%s#listworkspaces:search=%s",
+		a.Region().String(), a.Region().String(), url.QueryEscape(a.ID().String())))
+	if err != nil {
+		log.Error("Error generating AWSConsoleURL. %s", err)
+	}
+	return url
+}
+
+// Terminate is a method of reapable.Terminable, which is embedded in reapable.Reapable
+func (a *WorkSpace) Terminate() (bool, error) {
+	log.Info("Terminating WorkSpace %s", a.ReapableDescriptionTiny())
+	api := workspaces.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	resp, err := api.TerminateWorkspaces(&workspaces.TerminateWorkspacesInput{
+		TerminateWorkspaceRequests: []*workspaces.TerminateRequest{
+			&workspaces.TerminateRequest{WorkspaceId: aws.String(a.ID().String())},
+		},
+	})
+	if err == nil && len(resp.FailedRequests) > 0 {
+		err = fmt.Errorf("%s: %s", *resp.FailedRequests[0].ErrorCode, *resp.FailedRequests[0].ErrorMessage)
+	}
+	if err != nil {
+		log.Error("could not terminate WorkSpace %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
+
+// Stop is a method of reapable.Stoppable, which is embedded in reapable.Reapable
+// only AUTO_STOP WorkSpaces can be stopped
+func (a *WorkSpace) Stop() (bool, error) {
+	log.Info("Stopping WorkSpace %s", a.ReapableDescriptionTiny())
+	api := workspaces.New(sess, aws.NewConfig().WithRegion(a.Region().String()))
+	resp := &stopWorkspacesOutput{}
+	err := workspacesRequest(api, "StopWorkspaces", &stopWorkspacesInput{
+		StopWorkspaceRequests: []*stopRequest{
+			&stopRequest{WorkspaceId: aws.String(a.ID().String())},
+		},
+	}, resp)
+	if err == nil && len(resp.FailedRequests) > 0 {
+		err = fmt.Errorf("%s: %s", *resp.FailedRequests[0].ErrorCode, *resp.FailedRequests[0].ErrorMessage)
+	}
+	if err != nil {
+		log.Error("could not stop WorkSpace %s", a.ReapableDescriptionTiny())
+		return false, err
+	}
+	return true, nil
+}
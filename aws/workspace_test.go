@@ -0,0 +1,63 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/workspaces"
+
+	"github.com/mozilla-services/reaper/filters"
+)
+
+func TestWorkSpaceFilter(t *testing.T) {
+	a := NewWorkSpace("us-west-2", &workspaces.Workspace{
+		WorkspaceId: aws.String("ws-1234"),
+		State:       aws.String("AVAILABLE"),
+		BundleId:    aws.String("wsb-1"),
+		UserName:    aws.String("bob"),
+	}, "ALWAYS_ON", true, aws.Time(time.Now().Add(-48*time.Hour)), nil)
+
+	for _, test := range []struct {
+		function  string
+		arguments []string
+		matched   bool
+	}{
+		{"State", []string{"AVAILABLE"}, true},
+		{"NotState", []string{"AVAILABLE"}, false},
+		{"RunningMode", []string{"ALWAYS_ON"}, true},
+		{"NotRunningMode", []string{"AUTO_STOP"}, true},
+		{"Bundle", []string{"wsb-1"}, true},
+		{"NotBundle", []string{"wsb-1"}, false},
+		{"UserName", []string{"bob"}, true},
+		{"NotUserName", []string{"alice"}, true},
+		{"ConnectedInTheLast", []string{"24h"}, false},
+		{"NotConnectedInTheLast", []string{"24h"}, true},
+		{"Unknown", []string{}, false},
+	} {
+		if matched := a.Filter(*filters.NewFilter(test.function, test.arguments)); matched != test.matched {
+			t.Errorf("%s(%v) = %t, want %t", test.function, test.arguments, matched, test.matched)
+		}
+	}
+}
+
+func TestWorkSpaceConnectionStatusFilter(t *testing.T) {
+	for _, test := range []struct {
+		known   bool
+		matched bool
+	}{
+		// WorkSpaces whose user never connected match
+		{true, true},
+		// unless their connection status is unknown
+		{false, false},
+	} {
+		a := NewWorkSpace("us-west-2", &workspaces.Workspace{WorkspaceId: aws.String("ws-5678")}, "", test.known, nil, nil)
+		if matched := a.Filter(*filters.NewFilter("NotConnectedInTheLast", []string{"24h"})); matched != test.matched {
+			t.Errorf("known %t: NotConnectedInTheLast = %t, want %t", test.known, matched, test.matched)
+		}
+		// an unknown running mode is not another running mode
+		if a.Filter(*filters.NewFilter("NotRunningMode", []string{"AUTO_STOP"})) {
+			t.Error("NotRunningMode matched an unknown running mode")
+		}
+	}
+}
@@ -7,11 +7,16 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strings"
 
 	log "github.com/mozilla-services/reaper/reaperlog"
 )
 
 const Ec2PricingUrl = "https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonEC2/current/index.json"
+const RDSPricingUrl = "https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonRDS/current/index.json"
+
+// IdleAddressPriceKey is the PricesMap key of the hourly price of an unassociated Elastic IP
+const IdleAddressPriceKey = "ElasticIP:IdleAddress"
 
 type PricesMap map[string]map[string]string
 
@@ -35,6 +40,9 @@ type ProductPriceData struct {
 	Attributes struct {
 		ClockSpeed            string `json:"clockSpeed"`
 		CurrentGeneration     string `json:"currentGeneration"`
+		DatabaseEdition       string `json:"databaseEdition"`
+		DatabaseEngine        string `json:"databaseEngine"`
+		DeploymentOption      string `json:"deploymentOption"`
 		InstanceFamily        string `json:"instanceFamily"`
 		InstanceType          string `json:"instanceType"`
 		LicenseModel          string `json:"licenseModel"`
@@ -95,7 +103,7 @@ func GetPricesMapFromFile(filename string) (PricesMap, error) {
 	if err != nil {
 		return nil, err
 	}
-	return populatePricesMap(bytes.NewReader(bs))
+	return populatePricesMap(bytes.NewReader(bs), ec2PriceKey)
 }
 
 func DownloadPricesMap(url string) (PricesMap, error) {
@@ -108,13 +116,114 @@ func DownloadPricesMap(url string) (PricesMap, error) {
 		return PricesMap{}, err
 	}
 	defer res.Body.Close()
-	return populatePricesMap(res.Body)
+	return populatePricesMap(res.Body, ec2PriceKey)
+}
+
+// DownloadRDSPricesMap downloads RDS prices, keyed by RDSPriceKey
+func DownloadRDSPricesMap(url string) (PricesMap, error) {
+	if url == "" {
+		return PricesMap{}, fmt.Errorf("Invalid price url")
+	}
+
+	res, err := http.Get(url)
+	if err != nil {
+		return PricesMap{}, err
+	}
+	defer res.Body.Close()
+	return populatePricesMap(res.Body, rdsPriceKey)
+}
+
+// RDSPriceKey returns the PricesMap key for a DB instance class, engine, license model and deployment
+// engine and licenseModel are the names used by the RDS API (mysql, oracle-se1..., license-included...)
+func RDSPriceKey(instanceClass, engine, licenseModel string, multiAZ bool) string {
+	deploymentOption := "Single-AZ"
+	if multiAZ {
+		deploymentOption = "Multi-AZ"
+	}
+	return strings.Join([]string{instanceClass, rdsDatabaseEngine(engine), rdsDatabaseEdition(engine),
+		rdsLicenseModel(licenseModel), deploymentOption}, "|")
+}
+
+// rdsLicenseModel maps RDS API license models to the license models used by the price list
+func rdsLicenseModel(licenseModel string) string {
+	switch licenseModel {
+	case "license-included":
+		return "License included"
+	case "bring-your-own-license":
+		return "Bring your own license"
+	}
+	// general-public-license, postgresql-license...
+	return "No license required"
+}
+
+// rdsDatabaseEngine maps RDS API engine names to the engine names used by the price list
+func rdsDatabaseEngine(engine string) string {
+	switch {
+	case engine == "mysql":
+		return "MySQL"
+	case engine == "mariadb":
+		return "MariaDB"
+	case engine == "postgres":
+		return "PostgreSQL"
+	case engine == "aurora":
+		return "Amazon Aurora"
+	case strings.HasPrefix(engine, "oracle"):
+		return "Oracle"
+	case strings.HasPrefix(engine, "sqlserver"):
+		return "SQL Server"
+	}
+	return engine
+}
+
+// rdsDatabaseEdition maps RDS API engine names to the editions used by the price list
+// only Oracle and SQL Server have editions
+func rdsDatabaseEdition(engine string) string {
+	switch engine {
+	case "oracle-se1":
+		return "Standard One"
+	case "oracle-se2":
+		return "Standard Two"
+	case "oracle-se", "sqlserver-se":
+		return "Standard"
+	case "oracle-ee", "sqlserver-ee":
+		return "Enterprise"
+	case "sqlserver-ex":
+		return "Express"
+	case "sqlserver-web":
+		return "Web"
+	}
+	return ""
+}
+
+// ec2PriceKey keys instances by InstanceType, and idle Elastic IPs by IdleAddressPriceKey
+func ec2PriceKey(productData ProductPriceData) string {
+	switch productData.ProductFamily {
+	case "Compute Instance":
+		return productData.Attributes.InstanceType
+	case "IP Address":
+		// usagetypes are prefixed by a region code outside of us-east-1
+		if strings.HasSuffix(productData.Attributes.Usagetype, "ElasticIP:IdleAddress") {
+			return IdleAddressPriceKey
+		}
+	}
+	return ""
+}
+
+func rdsPriceKey(productData ProductPriceData) string {
+	if productData.ProductFamily != "Database Instance" {
+		return ""
+	}
+	return strings.Join([]string{productData.Attributes.InstanceType, productData.Attributes.DatabaseEngine,
+		productData.Attributes.DatabaseEdition, productData.Attributes.LicenseModel,
+		productData.Attributes.DeploymentOption}, "|")
 }
 
-func populatePricesMap(r io.Reader) (PricesMap, error) {
+// populatePricesMap reads on demand prices from an offer file
+// keyed by region, then by key(product), products with an empty key are skipped
+func populatePricesMap(r io.Reader, key func(ProductPriceData) string) (PricesMap, error) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Error("Recovered from a panic: ", r)
+			log.Error("Recovered from a panic: %v", r)
 		}
 	}()
 
@@ -131,16 +240,16 @@ func populatePricesMap(r io.Reader) (PricesMap, error) {
 	}
 
 	for sku, productData := range pd.Products {
-		// only get prices for EC2 instances
-		if productData.ProductFamily != "Compute Instance" {
+		// only get prices for the requested products
+		if key(productData) == "" {
 			continue
 		}
 		for _, termData := range pd.Terms.OnDemand[sku] {
 			for _, dimensionData := range termData.PriceDimensions {
 				if region, ok := regions[productData.Attributes.Location]; ok {
-					pricesMap[region][productData.Attributes.InstanceType] = dimensionData.PricePerUnit.USD
+					pricesMap[region][key(productData)] = dimensionData.PricePerUnit.USD
 				} else {
-					log.Error(fmt.Sprintf("Region not found for sku %s location %s", sku, productData.Attributes.Location))
+					log.Error("Region not found for sku %s location %s", sku, productData.Attributes.Location)
 				}
 			}
 		}
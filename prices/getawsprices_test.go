@@ -0,0 +1,74 @@
+package prices
+
+import "testing"
+
+func product(family string, attributes map[string]string) ProductPriceData {
+	var p ProductPriceData
+	p.ProductFamily = family
+	p.Attributes.InstanceType = attributes["instanceType"]
+	p.Attributes.DatabaseEngine = attributes["databaseEngine"]
+	p.Attributes.DatabaseEdition = attributes["databaseEdition"]
+	p.Attributes.LicenseModel = attributes["licenseModel"]
+	p.Attributes.DeploymentOption = attributes["deploymentOption"]
+	p.Attributes.Usagetype = attributes["usagetype"]
+	return p
+}
+
+func TestEC2PriceKey(t *testing.T) {
+	for _, test := range []struct {
+		product ProductPriceData
+		key     string
+	}{
+		{product("Compute Instance", map[string]string{"instanceType": "m4.large"}), "m4.large"},
+		{product("IP Address", map[string]string{"usagetype": "ElasticIP:IdleAddress"}), IdleAddressPriceKey},
+		// usagetypes are prefixed by a region code outside of us-east-1
+		{product("IP Address", map[string]string{"usagetype": "USW2-ElasticIP:IdleAddress"}), IdleAddressPriceKey},
+		{product("IP Address", map[string]string{"usagetype": "USW2-ElasticIP:AdditionalAddress"}), ""},
+		{product("Storage", map[string]string{}), ""},
+	} {
+		if key := ec2PriceKey(test.product); key != test.key {
+			t.Errorf("ec2PriceKey(%+v) = %q, want %q", test.product, key, test.key)
+		}
+	}
+}
+
+func TestRDSPriceKey(t *testing.T) {
+	for _, test := range []struct {
+		instanceClass, engine, licenseModel string
+		multiAZ                             bool
+		product                             ProductPriceData
+	}{
+		{"db.t2.micro", "postgres", "postgresql-license", false, product("Database Instance", map[string]string{
+			"instanceType": "db.t2.micro", "databaseEngine": "PostgreSQL", "licenseModel": "No license required", "deploymentOption": "Single-AZ"})},
+		{"db.m4.large", "mysql", "general-public-license", true, product("Database Instance", map[string]string{
+			"instanceType": "db.m4.large", "databaseEngine": "MySQL", "licenseModel": "No license required", "deploymentOption": "Multi-AZ"})},
+		{"db.m4.large", "oracle-se1", "license-included", false, product("Database Instance", map[string]string{
+			"instanceType": "db.m4.large", "databaseEngine": "Oracle", "databaseEdition": "Standard One", "licenseModel": "License included", "deploymentOption": "Single-AZ"})},
+		{"db.m4.large", "oracle-ee", "bring-your-own-license", false, product("Database Instance", map[string]string{
+			"instanceType": "db.m4.large", "databaseEngine": "Oracle", "databaseEdition": "Enterprise", "licenseModel": "Bring your own license", "deploymentOption": "Single-AZ"})},
+		{"db.m4.large", "sqlserver-se", "license-included", true, product("Database Instance", map[string]string{
+			"instanceType": "db.m4.large", "databaseEngine": "SQL Server", "databaseEdition": "Standard", "licenseModel": "License included", "deploymentOption": "Multi-AZ"})},
+		{"db.m4.large", "sqlserver-ex", "license-included", false, product("Database Instance", map[string]string{
+			"instanceType": "db.m4.large", "databaseEngine": "SQL Server", "databaseEdition": "Express", "licenseModel": "License included", "deploymentOption": "Single-AZ"})},
+		{"db.m4.large", "sqlserver-ee", "license-included", false, product("Database Instance", map[string]string{
+			"instanceType": "db.m4.large", "databaseEngine": "SQL Server", "databaseEdition": "Enterprise", "licenseModel": "License included", "deploymentOption": "Single-AZ"})},
+	} {
+		key := RDSPriceKey(test.instanceClass, test.engine, test.licenseModel, test.multiAZ)
+		if productKey := rdsPriceKey(test.product); key != productKey {
+			t.Errorf("RDSPriceKey(%s, %s, %s, %t) = %q, want %q", test.instanceClass, test.engine, test.licenseModel, test.multiAZ, key, productKey)
+		}
+	}
+
+	// license models do not share prices
+	if RDSPriceKey("db.m4.large", "oracle-se1", "license-included", false) == RDSPriceKey("db.m4.large", "oracle-se1", "bring-your-own-license", false) {
+		t.Error("RDSPriceKey does not distinguish license models")
+	}
+	// nor do editions
+	if RDSPriceKey("db.m4.large", "sqlserver-ex", "license-included", false) == RDSPriceKey("db.m4.large", "sqlserver-ee", "license-included", false) {
+		t.Error("RDSPriceKey does not distinguish SQL Server editions")
+	}
+
+	if key := rdsPriceKey(product("Storage", map[string]string{})); key != "" {
+		t.Errorf("rdsPriceKey of storage = %q, want \"\"", key)
+	}
+}
@@ -3,6 +3,7 @@ package reapable
 import (
 	"fmt"
 	"net/mail"
+	"reflect"
 	"sync"
 
 	"github.com/mozilla-services/reaper/filters"
@@ -63,9 +64,27 @@ func (i ID) String() string {
 	return string(i)
 }
 
+// Kind is the type of a Reapable
+// many resources are identified by names, which are only unique per Kind
+type Kind string
+
+func (k Kind) String() string {
+	return string(k)
+}
+
+// KindOf returns the Kind of a Reapable, the name of its type
+func KindOf(r Reapable) Kind {
+	return Kind(reflect.Indirect(reflect.ValueOf(r)).Type().Name())
+}
+
+type key struct {
+	kind Kind
+	id   ID
+}
+
 type Reapables struct {
 	sync.RWMutex
-	storage map[Region]map[ID]Reapable
+	storage map[Region]map[key]Reapable
 }
 
 func NewReapables(regions []string) *Reapables {
@@ -74,9 +93,9 @@ func NewReapables(regions []string) *Reapables {
 	defer r.Unlock()
 
 	// initialize Reapables map
-	r.storage = make(map[Region]map[ID]Reapable)
+	r.storage = make(map[Region]map[key]Reapable)
 	for _, region := range regions {
-		r.storage[Region(region)] = make(map[ID]Reapable)
+		r.storage[Region(region)] = make(map[key]Reapable)
 	}
 	return &r
 }
@@ -84,23 +103,42 @@ func NewReapables(regions []string) *Reapables {
 func (rs *Reapables) Put(region Region, id ID, r Reapable) {
 	rs.Lock()
 	defer rs.Unlock()
-	rs.storage[region][id] = r
+	rs.storage[region][key{KindOf(r), id}] = r
 }
 
-func (rs *Reapables) Get(region Region, id ID) (Reapable, error) {
+// Get returns the Reapable of kind with id in region
+// if kind is empty, as in tokens issued before kinds were added,
+// the Reapable is only returned if no other kind shares its id
+func (rs *Reapables) Get(region Region, kind Kind, id ID) (Reapable, error) {
 	rs.RLock()
 	defer rs.RUnlock()
-	r, ok := rs.storage[region][id]
-	if ok {
-		return r, nil
+	if kind != "" {
+		r, ok := rs.storage[region][key{kind, id}]
+		if ok {
+			return r, nil
+		}
+		return r, ReapableNotFoundError{fmt.Sprintf("Could not find %s %s in %s", kind.String(), id.String(), region.String())}
 	}
-	return r, ReapableNotFoundError{fmt.Sprintf("Could not find resource %s in %s", id.String(), region.String())}
+
+	var found []Reapable
+	for k, r := range rs.storage[region] {
+		if k.id == id {
+			found = append(found, r)
+		}
+	}
+	if len(found) == 1 {
+		return found[0], nil
+	}
+	if len(found) > 1 {
+		return nil, ReapableNotFoundError{fmt.Sprintf("Resource %s in %s is ambiguous, %d resources share its id", id.String(), region.String(), len(found))}
+	}
+	return nil, ReapableNotFoundError{fmt.Sprintf("Could not find resource %s in %s", id.String(), region.String())}
 }
 
-func (rs *Reapables) Delete(region Region, id ID) {
-	rs.RLock()
-	defer rs.RUnlock()
-	delete(rs.storage[region], id)
+func (rs *Reapables) Delete(region Region, kind Kind, id ID) {
+	rs.Lock()
+	defer rs.Unlock()
+	delete(rs.storage[region], key{kind, id})
 }
 
 type ReapableContainer struct {
@@ -123,8 +161,8 @@ func (rs *Reapables) Iter() <-chan ReapableContainer {
 		rs.Lock()
 		defer rs.Unlock()
 		for region, regionMap := range rs.storage {
-			for id, r := range regionMap {
-				c <- ReapableContainer{r, region, id}
+			for k, r := range regionMap {
+				c <- ReapableContainer{r, region, k.id}
 			}
 		}
 		close(ch)
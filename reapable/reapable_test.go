@@ -0,0 +1,66 @@
+package reapable
+
+import "testing"
+
+// stand-ins for the Reapables of the aws package, only their kind is used
+type Instance struct{ Reapable }
+type AutoScalingGroup struct{ Reapable }
+type LoadBalancer struct{ Reapable }
+
+func TestKindOf(t *testing.T) {
+	if kind := KindOf(&AutoScalingGroup{}); kind != "AutoScalingGroup" {
+		t.Errorf("KindOf(&AutoScalingGroup{}) = %s, want AutoScalingGroup", kind)
+	}
+}
+
+func TestReapablesGet(t *testing.T) {
+	rs := NewReapables([]string{"us-west-2"})
+	group, loadBalancer, instance := &AutoScalingGroup{}, &LoadBalancer{}, &Instance{}
+	// names are only unique per kind
+	rs.Put("us-west-2", "web", group)
+	rs.Put("us-west-2", "web", loadBalancer)
+	rs.Put("us-west-2", "i-1234", instance)
+
+	for _, test := range []struct {
+		kind     Kind
+		id       ID
+		reapable Reapable
+	}{
+		{"AutoScalingGroup", "web", group},
+		{"LoadBalancer", "web", loadBalancer},
+		{"Instance", "i-1234", instance},
+		{"Instance", "web", nil},
+		// tokens issued before kinds were added have none,
+		// they find a Reapable whose id is unique
+		{"", "i-1234", instance},
+		// but not one that shares its id with another kind
+		{"", "web", nil},
+		{"", "i-5678", nil},
+	} {
+		r, err := rs.Get("us-west-2", test.kind, test.id)
+		if test.reapable == nil {
+			if _, ok := err.(ReapableNotFoundError); !ok {
+				t.Errorf("Get(%q, %q) = %v, %v, want a ReapableNotFoundError", test.kind, test.id, r, err)
+			}
+			continue
+		}
+		if err != nil || r != test.reapable {
+			t.Errorf("Get(%q, %q) = %v, %v, want %v", test.kind, test.id, r, err, test.reapable)
+		}
+	}
+}
+
+func TestReapablesDelete(t *testing.T) {
+	rs := NewReapables([]string{"us-west-2"})
+	rs.Put("us-west-2", "web", &AutoScalingGroup{})
+	rs.Put("us-west-2", "web", &LoadBalancer{})
+
+	// deleting one kind keeps the other
+	rs.Delete("us-west-2", "AutoScalingGroup", "web")
+	if _, err := rs.Get("us-west-2", "AutoScalingGroup", "web"); err == nil {
+		t.Error("the deleted AutoScalingGroup was found")
+	}
+	if _, err := rs.Get("us-west-2", "", "web"); err != nil {
+		t.Errorf("the LoadBalancer sharing its name was not found: %s", err)
+	}
+}
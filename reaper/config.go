@@ -30,8 +30,9 @@ func LoadConfig(path string) (*Config, error) {
 	}
 	conf := Config{
 		AWS: reaperaws.Config{
-			HTTP:          httpconfig,
-			Notifications: notifications,
+			HTTP:                    httpconfig,
+			Notifications:           notifications,
+			LogGroupRetentionInDays: 30,
 		},
 		SMTP: reaperevents.MailerConfig{
 			HTTPConfig: httpconfig,
@@ -94,12 +95,38 @@ type Config struct {
 	DefaultOwner     string
 	DefaultEmailHost string
 
-	AutoScalingGroups ResourceConfig
-	Instances         ResourceConfig
-	Snapshots         ResourceConfig
-	Cloudformations   ResourceConfig
-	SecurityGroups    ResourceConfig
-	Volumes           ResourceConfig
+	AutoScalingGroups    ResourceConfig
+	Instances            ResourceConfig
+	Snapshots            ResourceConfig
+	Cloudformations      ResourceConfig
+	SecurityGroups       ResourceConfig
+	Volumes              ResourceConfig
+	Images               ResourceConfig
+	LoadBalancers        ResourceConfig
+	RDSInstances         ResourceConfig
+	Addresses            ResourceConfig
+	NetworkInterfaces    ResourceConfig
+	LaunchConfigurations ResourceConfig
+	CacheClusters        ResourceConfig
+	RedshiftClusters     ResourceConfig
+	EMRClusters          ResourceConfig
+	Buckets              ResourceConfig
+	LambdaFunctions      ResourceConfig
+	DynamoDBTables       ResourceConfig
+	ECSClusters          ResourceConfig
+	ECSServices          ResourceConfig
+	EFSFileSystems       ResourceConfig
+	ElasticsearchDomains ResourceConfig
+	LogGroups            ResourceConfig
+	KeyPairs             ResourceConfig
+	NatGateways          ResourceConfig
+	Certificates         ResourceConfig
+	ECRImages            ResourceConfig
+	ECRRepositories      ResourceConfig
+	MetricAlarms         ResourceConfig
+	HostedZones          ResourceConfig
+	WorkSpaces           ResourceConfig
+	EBEnvironments       ResourceConfig
 
 	DryRun bool
 }
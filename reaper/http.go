@@ -106,7 +106,7 @@ func processToken(h *HTTPApi) func(http.ResponseWriter, *http.Request) {
 		}
 
 		// find reapable associated with the job
-		r, err := reapables.Get(reapable.Region(job.Region), reapable.ID(job.ID))
+		r, err := reapables.Get(reapable.Region(job.Region), reapable.Kind(job.Kind), reapable.ID(job.ID))
 		if err != nil {
 			writeResponse(w, http.StatusInternalServerError, err.Error())
 			return
@@ -197,6 +197,58 @@ func processToken(h *HTTPApi) func(http.ResponseWriter, *http.Request) {
 			consoleURL = t.AWSConsoleURL()
 		case *reaperaws.AutoScalingGroup:
 			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.Snapshot:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.Image:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.LoadBalancer:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.RDSInstance:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.Address:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.NetworkInterface:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.LaunchConfiguration:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.CacheCluster:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.RedshiftCluster:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.EMRCluster:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.Bucket:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.LambdaFunction:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.DynamoDBTable:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.ECSCluster:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.ECSService:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.EFSFileSystem:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.ElasticsearchDomain:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.LogGroup:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.KeyPair:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.NatGateway:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.Certificate:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.ECRRepository:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.MetricAlarm:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.HostedZone:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.WorkSpace:
+			consoleURL = t.AWSConsoleURL()
+		case *reaperaws.EBEnvironment:
+			consoleURL = t.AWSConsoleURL()
 		default:
 			log.Error("No AWSConsoleURL")
 		}
@@ -3,6 +3,7 @@ package reaper
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	reaperaws "github.com/mozilla-services/reaper/aws"
@@ -19,6 +20,8 @@ var (
 	config    *Config
 	schedule  *cron.Cron
 	pricesMap prices.PricesMap
+
+	rdsPricesMap prices.PricesMap
 )
 
 func SetConfig(c *Config) {
@@ -59,6 +62,14 @@ func GetPrices() {
 		return
 	}
 	log.Info("Successfully downloaded prices")
+
+	log.Info("Downloading RDS prices")
+	rdsPricesMap, err = prices.DownloadRDSPricesMap(prices.RDSPricingUrl)
+	if err != nil {
+		log.Error("Error getting RDS prices: %s", err.Error())
+		return
+	}
+	log.Info("Successfully downloaded RDS prices")
 }
 
 // Start begins Reaper's schedule
@@ -242,81 +253,111 @@ func getVolumes() chan *reaperaws.Volume {
 	return ch
 }
 
-func getInstances() chan *reaperaws.Instance {
-	ch := make(chan *reaperaws.Instance)
+func getSnapshots() chan *reaperaws.Snapshot {
+	ch := make(chan *reaperaws.Snapshot)
 	go func() {
-		instanceCh := reaperaws.AllInstances()
+		snapshotCh := reaperaws.AllSnapshots()
 		regionSums := make(map[reapable.Region]int)
-		instanceTypeSums := make(map[reapable.Region]map[string]int)
+		snapshotSizeSums := make(map[reapable.Region]int64)
 		filteredCount := make(map[reapable.Region]int)
 		whitelistedCount := make(map[reapable.Region]int)
-		for instance := range instanceCh {
-			// make the map if it is not initialized
-			if instanceTypeSums[instance.Region()] == nil {
-				instanceTypeSums[instance.Region()] = make(map[string]int)
+		for snapshot := range snapshotCh {
+			regionSums[snapshot.Region()]++
+
+			if snapshot.VolumeSize != nil {
+				snapshotSizeSums[snapshot.Region()] += *snapshot.VolumeSize
 			}
 
-			// don't count terminated or stopped instances
-			if !instance.Terminated() && !instance.Stopped() {
-				// increment InstanceType counter
-				instanceTypeSums[instance.Region()][*instance.InstanceType]++
+			if isWhitelisted(snapshot) {
+				whitelistedCount[snapshot.Region()]++
+			}
 
-				if isWhitelisted(instance) {
-					whitelistedCount[instance.Region()]++
+			if matchesFilters(snapshot) {
+				filteredCount[snapshot.Region()]++
+			}
+			ch <- snapshot
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total Snapshots in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.snapshots.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.snapshots.size",
+					float64(snapshotSizeSums[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.snapshots.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.snapshots.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
 				}
 			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
 
-			regionSums[instance.Region()]++
+func getImages() chan *reaperaws.Image {
+	ch := make(chan *reaperaws.Image)
+	go func() {
+		imageCh := reaperaws.AllImages()
+		regionSums := make(map[reapable.Region]int)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for image := range imageCh {
+			regionSums[image.Region()]++
 
-			if matchesFilters(instance) {
-				filteredCount[instance.Region()]++
+			if isWhitelisted(image) {
+				whitelistedCount[image.Region()]++
 			}
-			ch <- instance
+
+			if matchesFilters(image) {
+				filteredCount[image.Region()]++
+			}
+			ch <- image
 		}
 
 		for region, sum := range regionSums {
-			log.Info("Found %d total Instances in %s", sum, region)
+			log.Info("Found %d total Images in %s", sum, region)
 		}
 
 		go func() {
-			for region, regionMap := range instanceTypeSums {
-				for instanceType, instanceTypeSum := range regionMap {
-					if pricesMap != nil {
-						price, ok := pricesMap[string(region)][instanceType]
-						if ok {
-							priceFloat, err := strconv.ParseFloat(price, 64)
-							if err != nil {
-								log.Error(err.Error())
-							}
-							err = reaperevents.NewStatistic("reaper.instances.totalcost",
-								float64(instanceTypeSum)*priceFloat,
-								[]string{fmt.Sprintf("region:%s,instancetype:%s", region, instanceType), config.EventTag})
-							if err != nil {
-								log.Error(err.Error())
-							}
-						} else {
-							// some instance types are priceless
-							log.Error(fmt.Sprintf("No price for %s", instanceType))
-						}
-					}
-					err := reaperevents.NewStatistic("reaper.instances.total",
-						float64(instanceTypeSum),
-						[]string{fmt.Sprintf("region:%s,instancetype:%s", region, instanceType), config.EventTag})
-					if err != nil {
-						log.Error(err.Error())
-					}
-					err = reaperevents.NewStatistic("reaper.instances.filtered",
-						float64(filteredCount[region]),
-						[]string{fmt.Sprintf("region:%s,instancetype:%s", region, instanceType), config.EventTag})
-					if err != nil {
-						log.Error(err.Error())
-					}
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.images.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
 				}
-				err := reaperevents.NewStatistic("reaper.instances.whitelistedCount",
+				err = reaperevents.NewStatistic("reaper.images.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.images.whitelistedCount",
 					float64(whitelistedCount[region]),
 					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
 				if err != nil {
-					log.Error(err.Error())
+					log.Error("%s", err.Error())
 				}
 			}
 		}()
@@ -325,46 +366,59 @@ func getInstances() chan *reaperaws.Instance {
 	return ch
 }
 
-func getCloudformations() chan *reaperaws.Cloudformation {
-	ch := make(chan *reaperaws.Cloudformation)
+func getLoadBalancers() chan *reaperaws.LoadBalancer {
+	ch := make(chan *reaperaws.LoadBalancer)
 	go func() {
-		cfs := reaperaws.AllCloudformations()
+		lCh := reaperaws.AllLoadBalancers()
 		regionSums := make(map[reapable.Region]int)
+		idleCount := make(map[reapable.Region]int)
 		filteredCount := make(map[reapable.Region]int)
 		whitelistedCount := make(map[reapable.Region]int)
-		for cf := range cfs {
-			if isWhitelisted(cf) {
-				whitelistedCount[cf.Region()]++
+		for l := range lCh {
+			regionSums[l.Region()]++
+			if l.RegisteredInstanceCount() == 0 {
+				idleCount[l.Region()]++
 			}
-			regionSums[cf.Region()]++
 
-			if matchesFilters(cf) {
-				filteredCount[cf.Region()]++
+			if isWhitelisted(l) {
+				whitelistedCount[l.Region()]++
 			}
-			ch <- cf
+
+			if matchesFilters(l) {
+				filteredCount[l.Region()]++
+			}
+			ch <- l
 		}
+
 		for region, sum := range regionSums {
-			log.Info("Found %d total Cloudformation Stacks in %s", sum, region)
+			log.Info("Found %d total LoadBalancers in %s", sum, region)
 		}
+
 		go func() {
 			for region, regionSum := range regionSums {
-				err := reaperevents.NewStatistic("reaper.cloudformations.total",
+				err := reaperevents.NewStatistic("reaper.loadbalancers.total",
 					float64(regionSum),
 					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
 				if err != nil {
-					log.Error(err.Error())
+					log.Error("%s", err.Error())
 				}
-				err = reaperevents.NewStatistic("reaper.cloudformations.filtered",
+				err = reaperevents.NewStatistic("reaper.loadbalancers.idle",
+					float64(idleCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.loadbalancers.filtered",
 					float64(filteredCount[region]),
 					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
 				if err != nil {
-					log.Error(err.Error())
+					log.Error("%s", err.Error())
 				}
-				err = reaperevents.NewStatistic("reaper.cloudformations.whitelistedCount",
+				err = reaperevents.NewStatistic("reaper.loadbalancers.whitelistedCount",
 					float64(whitelistedCount[region]),
 					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
 				if err != nil {
-					log.Error(err.Error())
+					log.Error("%s", err.Error())
 				}
 			}
 		}()
@@ -373,171 +427,2269 @@ func getCloudformations() chan *reaperaws.Cloudformation {
 	return ch
 }
 
-func getAutoScalingGroups() chan *reaperaws.AutoScalingGroup {
-	ch := make(chan *reaperaws.AutoScalingGroup)
+func getRDSInstances() chan *reaperaws.RDSInstance {
+	ch := make(chan *reaperaws.RDSInstance)
 	go func() {
-		asgCh := reaperaws.AllAutoScalingGroups()
+		rCh := reaperaws.AllRDSInstances()
 		regionSums := make(map[reapable.Region]int)
-		asgSizeSums := make(map[reapable.Region]map[int64]int)
+		instanceClassCosts := make(map[reapable.Region]map[string]float64)
 		filteredCount := make(map[reapable.Region]int)
 		whitelistedCount := make(map[reapable.Region]int)
-		for asg := range asgCh {
+		for r := range rCh {
+			regionSums[r.Region()]++
 			// make the map if it is not initialized
-			if asgSizeSums[asg.Region()] == nil {
-				asgSizeSums[asg.Region()] = make(map[int64]int)
+			if instanceClassCosts[r.Region()] == nil {
+				instanceClassCosts[r.Region()] = make(map[string]float64)
 			}
-			if asg.DesiredCapacity != nil {
-				asgSizeSums[asg.Region()][*asg.DesiredCapacity]++
+			if price, ok := rdsInstancePrice(r); ok {
+				instanceClassCosts[r.Region()][*r.DBInstanceClass] += price
 			}
 
-			if isWhitelisted(asg) {
-				whitelistedCount[asg.Region()]++
+			if isWhitelisted(r) {
+				whitelistedCount[r.Region()]++
 			}
 
-			regionSums[asg.Region()]++
-
-			if matchesFilters(asg) {
-				filteredCount[asg.Region()]++
+			if matchesFilters(r) {
+				filteredCount[r.Region()]++
 			}
-			ch <- asg
+			ch <- r
 		}
+
 		for region, sum := range regionSums {
-			log.Info("Found %d total AutoScalingGroups in %s", sum, region)
+			log.Info("Found %d total RDSInstances in %s", sum, region)
 		}
+
 		go func() {
-			for region, regionMap := range asgSizeSums {
-				for asgSize, asgSizeSum := range regionMap {
-					err := reaperevents.NewStatistic("reaper.asgs.asgsizes",
-						float64(asgSizeSum),
-						[]string{fmt.Sprintf("region:%s,asgsize:%d", region, asgSize), config.EventTag})
-					if err != nil {
-						log.Error(err.Error())
-					}
-				}
-			}
 			for region, regionSum := range regionSums {
-				err := reaperevents.NewStatistic("reaper.asgs.total",
+				err := reaperevents.NewStatistic("reaper.rdsinstances.total",
 					float64(regionSum),
 					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
 				if err != nil {
-					log.Error(err.Error())
+					log.Error("%s", err.Error())
 				}
-				err = reaperevents.NewStatistic("reaper.asgs.filtered",
+				for instanceClass, cost := range instanceClassCosts[region] {
+					err = reaperevents.NewStatistic("reaper.rdsinstances.totalcost",
+						cost,
+						[]string{fmt.Sprintf("region:%s,instanceclass:%s", region, instanceClass), config.EventTag})
+					if err != nil {
+						log.Error("%s", err.Error())
+					}
+				}
+				err = reaperevents.NewStatistic("reaper.rdsinstances.filtered",
 					float64(filteredCount[region]),
 					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
 				if err != nil {
-					log.Error(err.Error())
+					log.Error("%s", err.Error())
 				}
-				err = reaperevents.NewStatistic("reaper.asgs.whitelistedCount",
+				err = reaperevents.NewStatistic("reaper.rdsinstances.whitelistedCount",
 					float64(whitelistedCount[region]),
 					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
 				if err != nil {
-					log.Error(err.Error())
+					log.Error("%s", err.Error())
 				}
 			}
-
 		}()
 		close(ch)
 	}()
 	return ch
 }
 
-// makes a slice of all filterables by appending
-// output of each filterable types aggregator function
-func allReapables() []reaperevents.Reapable {
-	var resources []reaperevents.Reapable
-
-	// initialize dependency and isInCloudformation
-	dependency := make(map[reapable.Region]map[reapable.ID]bool)
-	for _, region := range config.AWS.Regions {
-		dependency[reapable.Region(region)] = make(map[reapable.ID]bool)
+// rdsInstancePrice returns the hourly price of an RDSInstance, if it is known
+func rdsInstancePrice(r *reaperaws.RDSInstance) (float64, bool) {
+	if rdsPricesMap == nil || r.DBInstanceClass == nil || r.Engine == nil {
+		return 0, false
 	}
-
-	isInCloudformation := make(map[reapable.Region]map[reapable.ID]bool)
-	for _, region := range config.AWS.Regions {
-		isInCloudformation[reapable.Region(region)] = make(map[reapable.ID]bool)
+	multiAZ := r.MultiAZ != nil && *r.MultiAZ
+	licenseModel := ""
+	if r.LicenseModel != nil {
+		licenseModel = *r.LicenseModel
 	}
-
-	// initialize the map of instances in ASGs
-	instancesInASGs := make(map[reapable.Region]map[reapable.ID]bool)
-	for _, region := range config.AWS.Regions {
-		instancesInASGs[reapable.Region(region)] = make(map[reapable.ID]bool)
+	price, ok := rdsPricesMap[string(r.Region())][prices.RDSPriceKey(*r.DBInstanceClass, *r.Engine, licenseModel, multiAZ)]
+	if !ok {
+		// some classes and engines are priceless
+		log.Error("No price for %s %s", *r.DBInstanceClass, *r.Engine)
+		return 0, false
+	}
+	priceFloat, err := strconv.ParseFloat(price, 64)
+	if err != nil {
+		log.Error("%s", err.Error())
+		return 0, false
 	}
+	return priceFloat, true
+}
 
-	// without getCloudformations cannot populate basic dependency logic
-	for c := range getCloudformations() {
-		// because getting resources is rate limited...
-		c.RLock()
-		for _, resource := range c.Resources {
-			if resource.PhysicalResourceId != nil {
-				dependency[c.Region()][reapable.ID(*resource.PhysicalResourceId)] = true
-				isInCloudformation[c.Region()][reapable.ID(*resource.PhysicalResourceId)] = true
+func getAddresses() chan *reaperaws.Address {
+	ch := make(chan *reaperaws.Address)
+	go func() {
+		aCh := reaperaws.AllAddresses()
+		regionSums := make(map[reapable.Region]int)
+		unassociatedCount := make(map[reapable.Region]int)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for a := range aCh {
+			regionSums[a.Region()]++
+			if !a.Associated() {
+				unassociatedCount[a.Region()]++
+			}
+
+			if isWhitelisted(a) {
+				whitelistedCount[a.Region()]++
+			}
+
+			if matchesFilters(a) {
+				filteredCount[a.Region()]++
 			}
+			ch <- a
 		}
-		c.RUnlock()
-		if config.Cloudformations.Enabled {
-			resources = append(resources, c)
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total Addresses in %s", sum, region)
 		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.addresses.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.addresses.unassociated",
+					float64(unassociatedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				// only unassociated addresses are charged for
+				if price, ok := idleAddressPrice(region); ok {
+					err = reaperevents.NewStatistic("reaper.addresses.totalcost",
+						float64(unassociatedCount[region])*price,
+						[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+					if err != nil {
+						log.Error("%s", err.Error())
+					}
+				}
+				err = reaperevents.NewStatistic("reaper.addresses.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.addresses.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+// idleAddressPrice returns the hourly price of an unassociated Elastic IP in a region, if it is known
+func idleAddressPrice(region reapable.Region) (float64, bool) {
+	if pricesMap == nil {
+		return 0, false
 	}
+	price, ok := pricesMap[string(region)][prices.IdleAddressPriceKey]
+	if !ok {
+		log.Error("No price for idle Elastic IPs in %s", region)
+		return 0, false
+	}
+	priceFloat, err := strconv.ParseFloat(price, 64)
+	if err != nil {
+		log.Error("%s", err.Error())
+		return 0, false
+	}
+	return priceFloat, true
+}
 
-	for a := range getAutoScalingGroups() {
-		// ASGs can be identified by name...
-		if isInCloudformation[a.Region()][a.ID()] ||
-			isInCloudformation[a.Region()][reapable.ID(a.Name)] {
-			a.IsInCloudformation = true
-		}
+func getNetworkInterfaces() chan *reaperaws.NetworkInterface {
+	ch := make(chan *reaperaws.NetworkInterface)
+	go func() {
+		nCh := reaperaws.AllNetworkInterfaces()
+		regionSums := make(map[reapable.Region]int)
+		availableCount := make(map[reapable.Region]int)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for n := range nCh {
+			regionSums[n.Region()]++
+			if n.Status != nil && *n.Status == "available" {
+				availableCount[n.Region()]++
+			}
 
-		if dependency[a.Region()][a.ID()] ||
-			dependency[a.Region()][reapable.ID(a.Name)] {
-			a.Dependency = true
-		}
+			if isWhitelisted(n) {
+				whitelistedCount[n.Region()]++
+			}
 
-		// identify instances in an ASG
-		instanceIDsInASGs := reaperaws.AutoScalingGroupInstanceIDs(a)
-		for region := range instanceIDsInASGs {
-			for instanceID := range instanceIDsInASGs[region] {
-				instancesInASGs[region][instanceID] = true
-				dependency[region][instanceID] = true
+			if matchesFilters(n) {
+				filteredCount[n.Region()]++
 			}
+			ch <- n
 		}
 
-		if config.AutoScalingGroups.Enabled {
-			resources = append(resources, a)
+		for region, sum := range regionSums {
+			log.Info("Found %d total NetworkInterfaces in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.networkinterfaces.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.networkinterfaces.available",
+					float64(availableCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.networkinterfaces.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.networkinterfaces.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getLaunchConfigurations() chan *reaperaws.LaunchConfiguration {
+	ch := make(chan *reaperaws.LaunchConfiguration)
+	go func() {
+		lCh := reaperaws.AllLaunchConfigurations()
+		regionSums := make(map[reapable.Region]int)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for l := range lCh {
+			regionSums[l.Region()]++
+
+			if isWhitelisted(l) {
+				whitelistedCount[l.Region()]++
+			}
+
+			if matchesFilters(l) {
+				filteredCount[l.Region()]++
+			}
+			ch <- l
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total LaunchConfigurations in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.launchconfigurations.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.launchconfigurations.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.launchconfigurations.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getCacheClusters() chan *reaperaws.CacheCluster {
+	ch := make(chan *reaperaws.CacheCluster)
+	go func() {
+		cCh := reaperaws.AllCacheClusters()
+		regionSums := make(map[reapable.Region]int)
+		nodeTypeSums := make(map[reapable.Region]map[string]int64)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for c := range cCh {
+			regionSums[c.Region()]++
+			// make the map if it is not initialized
+			if nodeTypeSums[c.Region()] == nil {
+				nodeTypeSums[c.Region()] = make(map[string]int64)
+			}
+			if c.CacheNodeType != nil {
+				nodeTypeSums[c.Region()][*c.CacheNodeType] += c.NodeCount()
+			}
+
+			if isWhitelisted(c) {
+				whitelistedCount[c.Region()]++
+			}
+
+			if matchesFilters(c) {
+				filteredCount[c.Region()]++
+			}
+			ch <- c
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total CacheClusters in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.cacheclusters.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				for nodeType, nodeTypeSum := range nodeTypeSums[region] {
+					err = reaperevents.NewStatistic("reaper.cacheclusters.nodes",
+						float64(nodeTypeSum),
+						[]string{fmt.Sprintf("region:%s,nodetype:%s", region, nodeType), config.EventTag})
+					if err != nil {
+						log.Error("%s", err.Error())
+					}
+				}
+				err = reaperevents.NewStatistic("reaper.cacheclusters.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.cacheclusters.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getRedshiftClusters() chan *reaperaws.RedshiftCluster {
+	ch := make(chan *reaperaws.RedshiftCluster)
+	go func() {
+		rCh := reaperaws.AllRedshiftClusters()
+		regionSums := make(map[reapable.Region]int)
+		nodeTypeSums := make(map[reapable.Region]map[string]int64)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for r := range rCh {
+			regionSums[r.Region()]++
+			// make the map if it is not initialized
+			if nodeTypeSums[r.Region()] == nil {
+				nodeTypeSums[r.Region()] = make(map[string]int64)
+			}
+			if r.NodeType != nil && r.NumberOfNodes != nil {
+				nodeTypeSums[r.Region()][*r.NodeType] += *r.NumberOfNodes
+			}
+
+			if isWhitelisted(r) {
+				whitelistedCount[r.Region()]++
+			}
+
+			if matchesFilters(r) {
+				filteredCount[r.Region()]++
+			}
+			ch <- r
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total RedshiftClusters in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.redshiftclusters.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				for nodeType, nodeTypeSum := range nodeTypeSums[region] {
+					err = reaperevents.NewStatistic("reaper.redshiftclusters.nodes",
+						float64(nodeTypeSum),
+						[]string{fmt.Sprintf("region:%s,nodetype:%s", region, nodeType), config.EventTag})
+					if err != nil {
+						log.Error("%s", err.Error())
+					}
+				}
+				err = reaperevents.NewStatistic("reaper.redshiftclusters.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.redshiftclusters.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getEMRClusters() chan *reaperaws.EMRCluster {
+	ch := make(chan *reaperaws.EMRCluster)
+	go func() {
+		eCh := reaperaws.AllEMRClusters()
+		regionSums := make(map[reapable.Region]int)
+		waitingCount := make(map[reapable.Region]int)
+		terminationProtectedCount := make(map[reapable.Region]int)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for e := range eCh {
+			regionSums[e.Region()]++
+			if e.State() == "WAITING" {
+				waitingCount[e.Region()]++
+			}
+			if e.TerminationProtected() {
+				terminationProtectedCount[e.Region()]++
+			}
+
+			if isWhitelisted(e) {
+				whitelistedCount[e.Region()]++
+			}
+
+			if matchesFilters(e) {
+				filteredCount[e.Region()]++
+			}
+			ch <- e
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total EMRClusters in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.emrclusters.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.emrclusters.waiting",
+					float64(waitingCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.emrclusters.terminationprotected",
+					float64(terminationProtectedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.emrclusters.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.emrclusters.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getBuckets() chan *reaperaws.Bucket {
+	ch := make(chan *reaperaws.Bucket)
+	go func() {
+		bCh := reaperaws.AllBuckets()
+		regionSums := make(map[reapable.Region]int)
+		emptyCount := make(map[reapable.Region]int)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for b := range bCh {
+			regionSums[b.Region()]++
+			if b.Empty() {
+				emptyCount[b.Region()]++
+			}
+
+			if isWhitelisted(b) {
+				whitelistedCount[b.Region()]++
+			}
+
+			if matchesFilters(b) {
+				filteredCount[b.Region()]++
+			}
+			ch <- b
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total Buckets in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.buckets.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.buckets.empty",
+					float64(emptyCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.buckets.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.buckets.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getLambdaFunctions() chan *reaperaws.LambdaFunction {
+	ch := make(chan *reaperaws.LambdaFunction)
+	go func() {
+		lCh := reaperaws.AllLambdaFunctions()
+		regionSums := make(map[reapable.Region]int)
+		codeSizes := make(map[reapable.Region]int64)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for l := range lCh {
+			regionSums[l.Region()]++
+			if l.CodeSize != nil {
+				codeSizes[l.Region()] += *l.CodeSize
+			}
+
+			if isWhitelisted(l) {
+				whitelistedCount[l.Region()]++
+			}
+
+			if matchesFilters(l) {
+				filteredCount[l.Region()]++
+			}
+			ch <- l
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total LambdaFunctions in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.lambdafunctions.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.lambdafunctions.codesize",
+					float64(codeSizes[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.lambdafunctions.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.lambdafunctions.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getDynamoDBTables() chan *reaperaws.DynamoDBTable {
+	ch := make(chan *reaperaws.DynamoDBTable)
+	go func() {
+		dCh := reaperaws.AllDynamoDBTables()
+		regionSums := make(map[reapable.Region]int)
+		readCapacity := make(map[reapable.Region]int64)
+		writeCapacity := make(map[reapable.Region]int64)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for d := range dCh {
+			regionSums[d.Region()]++
+			readCapacity[d.Region()] += d.ReadCapacityUnits()
+			writeCapacity[d.Region()] += d.WriteCapacityUnits()
+
+			if isWhitelisted(d) {
+				whitelistedCount[d.Region()]++
+			}
+
+			if matchesFilters(d) {
+				filteredCount[d.Region()]++
+			}
+			ch <- d
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total DynamoDBTables in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.dynamodbtables.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.dynamodbtables.readcapacity",
+					float64(readCapacity[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.dynamodbtables.writecapacity",
+					float64(writeCapacity[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.dynamodbtables.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.dynamodbtables.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getECSClusters() chan *reaperaws.ECSCluster {
+	ch := make(chan *reaperaws.ECSCluster)
+	go func() {
+		cCh := reaperaws.AllECSClusters()
+		regionSums := make(map[reapable.Region]int)
+		containerInstanceCount := make(map[reapable.Region]int64)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for c := range cCh {
+			regionSums[c.Region()]++
+			containerInstanceCount[c.Region()] += c.ContainerInstances()
+
+			if isWhitelisted(c) {
+				whitelistedCount[c.Region()]++
+			}
+
+			if matchesFilters(c) {
+				filteredCount[c.Region()]++
+			}
+			ch <- c
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total ECSClusters in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.ecsclusters.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.ecsclusters.containerinstances",
+					float64(containerInstanceCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.ecsclusters.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.ecsclusters.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getECSServices() chan *reaperaws.ECSService {
+	ch := make(chan *reaperaws.ECSService)
+	go func() {
+		sCh := reaperaws.AllECSServices()
+		regionSums := make(map[reapable.Region]int)
+		scaledToZeroCount := make(map[reapable.Region]int)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for s := range sCh {
+			regionSums[s.Region()]++
+			if s.Desired() == 0 {
+				scaledToZeroCount[s.Region()]++
+			}
+
+			if isWhitelisted(s) {
+				whitelistedCount[s.Region()]++
+			}
+
+			if matchesFilters(s) {
+				filteredCount[s.Region()]++
+			}
+			ch <- s
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total ECSServices in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.ecsservices.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.ecsservices.scaledtozero",
+					float64(scaledToZeroCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.ecsservices.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.ecsservices.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getEFSFileSystems() chan *reaperaws.EFSFileSystem {
+	ch := make(chan *reaperaws.EFSFileSystem)
+	go func() {
+		fCh := reaperaws.AllEFSFileSystems()
+		regionSums := make(map[reapable.Region]int)
+		sizeSums := make(map[reapable.Region]int64)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for f := range fCh {
+			regionSums[f.Region()]++
+			sizeSums[f.Region()] += f.Size()
+
+			if isWhitelisted(f) {
+				whitelistedCount[f.Region()]++
+			}
+
+			if matchesFilters(f) {
+				filteredCount[f.Region()]++
+			}
+			ch <- f
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total EFSFileSystems in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.efsfilesystems.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				// in GB, like reaper.volumes.total
+				err = reaperevents.NewStatistic("reaper.efsfilesystems.size",
+					float64(sizeSums[region])/(1<<30),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.efsfilesystems.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.efsfilesystems.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getElasticsearchDomains() chan *reaperaws.ElasticsearchDomain {
+	ch := make(chan *reaperaws.ElasticsearchDomain)
+	go func() {
+		eCh := reaperaws.AllElasticsearchDomains()
+		regionSums := make(map[reapable.Region]int)
+		instanceTypeSums := make(map[reapable.Region]map[string]int64)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for e := range eCh {
+			regionSums[e.Region()]++
+			// make the map if it is not initialized
+			if instanceTypeSums[e.Region()] == nil {
+				instanceTypeSums[e.Region()] = make(map[string]int64)
+			}
+			instanceTypeSums[e.Region()][e.InstanceType()] += e.InstanceCount()
+
+			if isWhitelisted(e) {
+				whitelistedCount[e.Region()]++
+			}
+
+			if matchesFilters(e) {
+				filteredCount[e.Region()]++
+			}
+			ch <- e
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total ElasticsearchDomains in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.elasticsearchdomains.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				for instanceType, instanceTypeSum := range instanceTypeSums[region] {
+					err = reaperevents.NewStatistic("reaper.elasticsearchdomains.instances",
+						float64(instanceTypeSum),
+						[]string{fmt.Sprintf("region:%s,instancetype:%s", region, instanceType), config.EventTag})
+					if err != nil {
+						log.Error("%s", err.Error())
+					}
+				}
+				err = reaperevents.NewStatistic("reaper.elasticsearchdomains.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.elasticsearchdomains.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getLogGroups() chan *reaperaws.LogGroup {
+	ch := make(chan *reaperaws.LogGroup)
+	go func() {
+		lCh := reaperaws.AllLogGroups()
+		regionSums := make(map[reapable.Region]int)
+		storedBytes := make(map[reapable.Region]int64)
+		noRetentionCount := make(map[reapable.Region]int)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for l := range lCh {
+			regionSums[l.Region()]++
+			storedBytes[l.Region()] += l.Stored()
+			if l.Retention() == 0 {
+				noRetentionCount[l.Region()]++
+			}
+
+			if isWhitelisted(l) {
+				whitelistedCount[l.Region()]++
+			}
+
+			if matchesFilters(l) {
+				filteredCount[l.Region()]++
+			}
+			ch <- l
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total LogGroups in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.loggroups.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.loggroups.storedbytes",
+					float64(storedBytes[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.loggroups.noretention",
+					float64(noRetentionCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.loggroups.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.loggroups.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getKeyPairs() chan *reaperaws.KeyPair {
+	ch := make(chan *reaperaws.KeyPair)
+	go func() {
+		kCh := reaperaws.AllKeyPairs()
+		regionSums := make(map[reapable.Region]int)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for k := range kCh {
+			regionSums[k.Region()]++
+
+			if isWhitelisted(k) {
+				whitelistedCount[k.Region()]++
+			}
+
+			if matchesFilters(k) {
+				filteredCount[k.Region()]++
+			}
+			ch <- k
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total KeyPairs in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.keypairs.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.keypairs.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.keypairs.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getNatGateways() chan *reaperaws.NatGateway {
+	ch := make(chan *reaperaws.NatGateway)
+	go func() {
+		nCh := reaperaws.AllNatGateways()
+		regionSums := make(map[reapable.Region]int)
+		unroutedCount := make(map[reapable.Region]int)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for n := range nCh {
+			regionSums[n.Region()]++
+			if !n.Routed() {
+				unroutedCount[n.Region()]++
+			}
+
+			if isWhitelisted(n) {
+				whitelistedCount[n.Region()]++
+			}
+
+			if matchesFilters(n) {
+				filteredCount[n.Region()]++
+			}
+			ch <- n
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total NatGateways in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.natgateways.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.natgateways.unrouted",
+					float64(unroutedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.natgateways.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.natgateways.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getCertificates() chan *reaperaws.Certificate {
+	ch := make(chan *reaperaws.Certificate)
+	go func() {
+		cCh := reaperaws.AllCertificates()
+		regionSums := make(map[reapable.Region]int)
+		expiredCount := make(map[reapable.Region]int)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for c := range cCh {
+			regionSums[c.Region()]++
+			if c.Expired() {
+				expiredCount[c.Region()]++
+			}
+
+			if isWhitelisted(c) {
+				whitelistedCount[c.Region()]++
+			}
+
+			if matchesFilters(c) {
+				filteredCount[c.Region()]++
+			}
+			ch <- c
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total Certificates in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.certificates.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.certificates.expired",
+					float64(expiredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.certificates.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.certificates.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getECRRepositories() chan *reaperaws.ECRRepository {
+	ch := make(chan *reaperaws.ECRRepository)
+	go func() {
+		rCh := reaperaws.AllECRRepositories()
+		regionSums := make(map[reapable.Region]int)
+		imageSums := make(map[reapable.Region]int)
+		inTaskDefinitionCount := make(map[reapable.Region]int)
+		filteredCount := make(map[reapable.Region]int)
+		for r := range rCh {
+			regionSums[r.Region()]++
+			r.ImageFilter = func(image *reaperaws.ECRImage) bool {
+				return matchesFilters(image)
+			}
+			// images are filtered individually and reaped by their repository
+			for _, image := range r.Images {
+				imageSums[r.Region()]++
+				if image.InTaskDefinition {
+					inTaskDefinitionCount[r.Region()]++
+				}
+				if matchesFilters(image) {
+					filteredCount[r.Region()]++
+					r.ReapableImages = append(r.ReapableImages, image)
+				}
+			}
+			ch <- r
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total ECRRepositories with %d images in %s", sum, imageSums[region], region)
+		}
+
+		go func() {
+			for region, imageSum := range imageSums {
+				err := reaperevents.NewStatistic("reaper.ecrimages.total",
+					float64(imageSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.ecrimages.intaskdefinition",
+					float64(inTaskDefinitionCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.ecrimages.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getMetricAlarms() chan *reaperaws.MetricAlarm {
+	ch := make(chan *reaperaws.MetricAlarm)
+	go func() {
+		mCh := reaperaws.AllMetricAlarms()
+		regionSums := make(map[reapable.Region]int)
+		insufficientDataCount := make(map[reapable.Region]int)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for m := range mCh {
+			regionSums[m.Region()]++
+			if m.StateValue != nil && *m.StateValue == "INSUFFICIENT_DATA" {
+				insufficientDataCount[m.Region()]++
+			}
+
+			if isWhitelisted(m) {
+				whitelistedCount[m.Region()]++
+			}
+
+			if matchesFilters(m) {
+				filteredCount[m.Region()]++
+			}
+			ch <- m
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total MetricAlarms in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.metricalarms.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.metricalarms.insufficientdata",
+					float64(insufficientDataCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.metricalarms.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.metricalarms.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getHostedZones() chan *reaperaws.HostedZone {
+	ch := make(chan *reaperaws.HostedZone)
+	go func() {
+		hCh := reaperaws.AllHostedZones()
+		regionSums := make(map[reapable.Region]int)
+		emptyCount := make(map[reapable.Region]int)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for h := range hCh {
+			regionSums[h.Region()]++
+			if h.Empty() {
+				emptyCount[h.Region()]++
+			}
+
+			if isWhitelisted(h) {
+				whitelistedCount[h.Region()]++
+			}
+
+			if matchesFilters(h) {
+				filteredCount[h.Region()]++
+			}
+			ch <- h
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total HostedZones in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.hostedzones.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.hostedzones.empty",
+					float64(emptyCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.hostedzones.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.hostedzones.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getWorkSpaces() chan *reaperaws.WorkSpace {
+	ch := make(chan *reaperaws.WorkSpace)
+	go func() {
+		wCh := reaperaws.AllWorkSpaces()
+		regionSums := make(map[reapable.Region]int)
+		neverConnectedCount := make(map[reapable.Region]int)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for w := range wCh {
+			regionSums[w.Region()]++
+			if w.ConnectionStatusKnown && w.LastKnownUserConnection == nil {
+				neverConnectedCount[w.Region()]++
+			}
+
+			if isWhitelisted(w) {
+				whitelistedCount[w.Region()]++
+			}
+
+			if matchesFilters(w) {
+				filteredCount[w.Region()]++
+			}
+			ch <- w
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total WorkSpaces in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.workspaces.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.workspaces.neverconnected",
+					float64(neverConnectedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.workspaces.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.workspaces.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getEBEnvironments() chan *reaperaws.EBEnvironment {
+	ch := make(chan *reaperaws.EBEnvironment)
+	go func() {
+		eCh := reaperaws.AllEBEnvironments()
+		regionSums := make(map[reapable.Region]int)
+		redCount := make(map[reapable.Region]int)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for e := range eCh {
+			regionSums[e.Region()]++
+			if e.Health != nil && *e.Health == "Red" {
+				redCount[e.Region()]++
+			}
+
+			if isWhitelisted(e) {
+				whitelistedCount[e.Region()]++
+			}
+
+			if matchesFilters(e) {
+				filteredCount[e.Region()]++
+			}
+			ch <- e
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total EBEnvironments in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.ebenvironments.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.ebenvironments.red",
+					float64(redCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.ebenvironments.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.ebenvironments.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error("%s", err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getInstances() chan *reaperaws.Instance {
+	ch := make(chan *reaperaws.Instance)
+	go func() {
+		instanceCh := reaperaws.AllInstances()
+		regionSums := make(map[reapable.Region]int)
+		instanceTypeSums := make(map[reapable.Region]map[string]int)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for instance := range instanceCh {
+			// make the map if it is not initialized
+			if instanceTypeSums[instance.Region()] == nil {
+				instanceTypeSums[instance.Region()] = make(map[string]int)
+			}
+
+			// don't count terminated or stopped instances
+			if !instance.Terminated() && !instance.Stopped() {
+				// increment InstanceType counter
+				instanceTypeSums[instance.Region()][*instance.InstanceType]++
+
+				if isWhitelisted(instance) {
+					whitelistedCount[instance.Region()]++
+				}
+			}
+
+			regionSums[instance.Region()]++
+
+			if matchesFilters(instance) {
+				filteredCount[instance.Region()]++
+			}
+			ch <- instance
+		}
+
+		for region, sum := range regionSums {
+			log.Info("Found %d total Instances in %s", sum, region)
+		}
+
+		go func() {
+			for region, regionMap := range instanceTypeSums {
+				for instanceType, instanceTypeSum := range regionMap {
+					if pricesMap != nil {
+						price, ok := pricesMap[string(region)][instanceType]
+						if ok {
+							priceFloat, err := strconv.ParseFloat(price, 64)
+							if err != nil {
+								log.Error(err.Error())
+							}
+							err = reaperevents.NewStatistic("reaper.instances.totalcost",
+								float64(instanceTypeSum)*priceFloat,
+								[]string{fmt.Sprintf("region:%s,instancetype:%s", region, instanceType), config.EventTag})
+							if err != nil {
+								log.Error(err.Error())
+							}
+						} else {
+							// some instance types are priceless
+							log.Error(fmt.Sprintf("No price for %s", instanceType))
+						}
+					}
+					err := reaperevents.NewStatistic("reaper.instances.total",
+						float64(instanceTypeSum),
+						[]string{fmt.Sprintf("region:%s,instancetype:%s", region, instanceType), config.EventTag})
+					if err != nil {
+						log.Error(err.Error())
+					}
+					err = reaperevents.NewStatistic("reaper.instances.filtered",
+						float64(filteredCount[region]),
+						[]string{fmt.Sprintf("region:%s,instancetype:%s", region, instanceType), config.EventTag})
+					if err != nil {
+						log.Error(err.Error())
+					}
+				}
+				err := reaperevents.NewStatistic("reaper.instances.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error(err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getCloudformations() chan *reaperaws.Cloudformation {
+	ch := make(chan *reaperaws.Cloudformation)
+	go func() {
+		cfs := reaperaws.AllCloudformations()
+		regionSums := make(map[reapable.Region]int)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for cf := range cfs {
+			if isWhitelisted(cf) {
+				whitelistedCount[cf.Region()]++
+			}
+			regionSums[cf.Region()]++
+
+			if matchesFilters(cf) {
+				filteredCount[cf.Region()]++
+			}
+			ch <- cf
+		}
+		for region, sum := range regionSums {
+			log.Info("Found %d total Cloudformation Stacks in %s", sum, region)
+		}
+		go func() {
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.cloudformations.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error(err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.cloudformations.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error(err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.cloudformations.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error(err.Error())
+				}
+			}
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+func getAutoScalingGroups() chan *reaperaws.AutoScalingGroup {
+	ch := make(chan *reaperaws.AutoScalingGroup)
+	go func() {
+		asgCh := reaperaws.AllAutoScalingGroups()
+		regionSums := make(map[reapable.Region]int)
+		asgSizeSums := make(map[reapable.Region]map[int64]int)
+		filteredCount := make(map[reapable.Region]int)
+		whitelistedCount := make(map[reapable.Region]int)
+		for asg := range asgCh {
+			// make the map if it is not initialized
+			if asgSizeSums[asg.Region()] == nil {
+				asgSizeSums[asg.Region()] = make(map[int64]int)
+			}
+			if asg.DesiredCapacity != nil {
+				asgSizeSums[asg.Region()][*asg.DesiredCapacity]++
+			}
+
+			if isWhitelisted(asg) {
+				whitelistedCount[asg.Region()]++
+			}
+
+			regionSums[asg.Region()]++
+
+			if matchesFilters(asg) {
+				filteredCount[asg.Region()]++
+			}
+			ch <- asg
+		}
+		for region, sum := range regionSums {
+			log.Info("Found %d total AutoScalingGroups in %s", sum, region)
+		}
+		go func() {
+			for region, regionMap := range asgSizeSums {
+				for asgSize, asgSizeSum := range regionMap {
+					err := reaperevents.NewStatistic("reaper.asgs.asgsizes",
+						float64(asgSizeSum),
+						[]string{fmt.Sprintf("region:%s,asgsize:%d", region, asgSize), config.EventTag})
+					if err != nil {
+						log.Error(err.Error())
+					}
+				}
+			}
+			for region, regionSum := range regionSums {
+				err := reaperevents.NewStatistic("reaper.asgs.total",
+					float64(regionSum),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error(err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.asgs.filtered",
+					float64(filteredCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error(err.Error())
+				}
+				err = reaperevents.NewStatistic("reaper.asgs.whitelistedCount",
+					float64(whitelistedCount[region]),
+					[]string{fmt.Sprintf("region:%s", region), config.EventTag})
+				if err != nil {
+					log.Error(err.Error())
+				}
+			}
+
+		}()
+		close(ch)
+	}()
+	return ch
+}
+
+// Cloudformation types of the resources identified by names
+// names are only unique per type, unlike ids such as i-1234
+const (
+	autoScalingGroupType    = "AWS::AutoScaling::AutoScalingGroup"
+	launchConfigurationType = "AWS::AutoScaling::LaunchConfiguration"
+	loadBalancerType        = "AWS::ElasticLoadBalancing::LoadBalancer"
+	dbInstanceType          = "AWS::RDS::DBInstance"
+	cacheClusterType        = "AWS::ElastiCache::CacheCluster"
+	replicationGroupType    = "AWS::ElastiCache::ReplicationGroup"
+	redshiftClusterType     = "AWS::Redshift::Cluster"
+	lambdaFunctionType      = "AWS::Lambda::Function"
+	ecsClusterType          = "AWS::ECS::Cluster"
+	bucketType              = "AWS::S3::Bucket"
+	dynamoDBTableType       = "AWS::DynamoDB::Table"
+	elasticsearchDomainType = "AWS::Elasticsearch::Domain"
+	logGroupType            = "AWS::Logs::LogGroup"
+	alarmType               = "AWS::CloudWatch::Alarm"
+	ebEnvironmentType       = "AWS::ElasticBeanstalk::Environment"
+	securityGroupType       = "AWS::EC2::SecurityGroup"
+)
+
+var namedResourceTypes = map[string]bool{
+	autoScalingGroupType:    true,
+	launchConfigurationType: true,
+	loadBalancerType:        true,
+	dbInstanceType:          true,
+	cacheClusterType:        true,
+	replicationGroupType:    true,
+	redshiftClusterType:     true,
+	lambdaFunctionType:      true,
+	ecsClusterType:          true,
+	bucketType:              true,
+	dynamoDBTableType:       true,
+	elasticsearchDomainType: true,
+	logGroupType:            true,
+	alarmType:               true,
+	ebEnvironmentType:       true,
+}
+
+// namedID qualifies a name with the Cloudformation type of the resource it identifies
+// so that an AutoScalingGroup and the LaunchConfiguration it was created with
+// can share a name without marking each other in the dependency maps
+func namedID(resourceType, name string) reapable.ID {
+	return reapable.ID(resourceType + "/" + name)
+}
+
+// securityGroupID returns the id a security group referenced by id or by name is marked by
+func securityGroupID(group string) reapable.ID {
+	if strings.HasPrefix(group, "sg-") {
+		return reapable.ID(group)
+	}
+	return namedID(securityGroupType, group)
+}
+
+// cloudformationResourceID returns the id a stack resource is marked by in the dependency maps
+func cloudformationResourceID(resourceType, physicalID string) reapable.ID {
+	switch {
+	case namedResourceTypes[resourceType]:
+		return namedID(resourceType, physicalID)
+	case resourceType == securityGroupType:
+		// security groups outside of a VPC are identified by name
+		return securityGroupID(physicalID)
+	}
+	return reapable.ID(physicalID)
+}
+
+// dependencyID returns the id a Reapable is marked by in the dependency maps
+func dependencyID(r reapable.Reapable) reapable.ID {
+	switch t := r.(type) {
+	case *reaperaws.AutoScalingGroup:
+		return namedID(autoScalingGroupType, t.ID().String())
+	case *reaperaws.LaunchConfiguration:
+		return namedID(launchConfigurationType, t.ID().String())
+	case *reaperaws.LoadBalancer:
+		return namedID(loadBalancerType, t.ID().String())
+	case *reaperaws.RDSInstance:
+		return namedID(dbInstanceType, t.ID().String())
+	case *reaperaws.CacheCluster:
+		if t.ReplicationGroup != nil {
+			return namedID(replicationGroupType, t.ID().String())
+		}
+		return namedID(cacheClusterType, t.ID().String())
+	case *reaperaws.RedshiftCluster:
+		return namedID(redshiftClusterType, t.ID().String())
+	case *reaperaws.LambdaFunction:
+		return namedID(lambdaFunctionType, t.ID().String())
+	case *reaperaws.ECSCluster:
+		return namedID(ecsClusterType, t.ID().String())
+	case *reaperaws.Bucket:
+		return namedID(bucketType, t.ID().String())
+	case *reaperaws.DynamoDBTable:
+		return namedID(dynamoDBTableType, t.ID().String())
+	case *reaperaws.ElasticsearchDomain:
+		return namedID(elasticsearchDomainType, t.ID().String())
+	case *reaperaws.LogGroup:
+		return namedID(logGroupType, t.ID().String())
+	case *reaperaws.MetricAlarm:
+		return namedID(alarmType, t.ID().String())
+	}
+	return r.ID()
+}
+
+// metricDimension is a CloudWatch dimension in the metrics of a namespace
+type metricDimension struct {
+	Namespace string
+	Name      string
+}
+
+// inventoriedDimensions are the CloudWatch dimensions identifying the resources
+// that allReapables always describes, whether or not they are enabled, by the kind of those resources
+// names such as ClusterName are used by several namespaces, for different kinds of resources
+var inventoriedDimensions = map[metricDimension]reapable.Kind{
+	{"AWS/AutoScaling", "AutoScalingGroupName"}: "AutoScalingGroup",
+	{"AWS/EC2", "AutoScalingGroupName"}:         "AutoScalingGroup",
+	{"AWS/ELB", "LoadBalancerName"}:             "LoadBalancer",
+	{"AWS/RDS", "DBInstanceIdentifier"}:         "RDSInstance",
+	{"AWS/ElastiCache", "CacheClusterId"}:       "CacheCluster",
+	{"AWS/Redshift", "ClusterIdentifier"}:       "RedshiftCluster",
+	{"AWS/Lambda", "FunctionName"}:              "LambdaFunction",
+	{"AWS/ECS", "ClusterName"}:                  "ECSCluster",
+	{"AWS/ElasticMapReduce", "JobFlowId"}:       "EMRCluster",
+	{"AWS/EC2", "InstanceId"}:                   "Instance",
+	{"AWS/NATGateway", "NatGatewayId"}:          "NatGateway",
+	{"AWS/EBS", "VolumeId"}:                     "Volume",
+	{"AWS/EC2", "ImageId"}:                      "Image",
+}
+
+// makes a slice of all filterables by appending
+// output of each filterable types aggregator function
+func allReapables() []reaperevents.Reapable {
+	var resources []reaperevents.Reapable
+
+	// initialize dependency and isInCloudformation
+	dependency := make(map[reapable.Region]map[reapable.ID]bool)
+	for _, region := range config.AWS.Regions {
+		dependency[reapable.Region(region)] = make(map[reapable.ID]bool)
+	}
+
+	isInCloudformation := make(map[reapable.Region]map[reapable.ID]bool)
+	for _, region := range config.AWS.Regions {
+		isInCloudformation[reapable.Region(region)] = make(map[reapable.ID]bool)
+	}
+
+	// initialize the map of instances in ASGs
+	instancesInASGs := make(map[reapable.Region]map[reapable.ID]bool)
+	for _, region := range config.AWS.Regions {
+		instancesInASGs[reapable.Region(region)] = make(map[reapable.ID]bool)
+	}
+
+	// resources that exist, by the CloudWatch dimension identifying them in metrics
+	// used to find alarms watching deleted resources
+	existingDimensions := make(map[reapable.Region]map[metricDimension]map[reapable.ID]bool)
+	for _, region := range config.AWS.Regions {
+		existingDimensions[reapable.Region(region)] = make(map[metricDimension]map[reapable.ID]bool)
+		for dimension := range inventoriedDimensions {
+			existingDimensions[reapable.Region(region)][dimension] = make(map[reapable.ID]bool)
+		}
+	}
+	// marks a resource as existing in every namespace that identifies it by name
+	inventory := func(region reapable.Region, name string, id reapable.ID) {
+		for dimension := range inventoriedDimensions {
+			if dimension.Name == name {
+				existingDimensions[region][dimension][id] = true
+			}
+		}
+	}
+	// describe failures are recorded per run
+	reaperaws.ResetDescribeFailures()
+
+	// Elastic Beanstalk environments own the resources they create
+	// they are described first so their Cloudformation stacks can be recognized
+	var ebEnvironments []*reaperaws.EBEnvironment
+	ebEnvironmentStacks := make(map[reapable.Region]map[string]bool)
+	ebEnvironmentResources := make(map[reapable.Region]map[reapable.ID]bool)
+	for _, region := range config.AWS.Regions {
+		ebEnvironmentStacks[reapable.Region(region)] = make(map[string]bool)
+		ebEnvironmentResources[reapable.Region(region)] = make(map[reapable.ID]bool)
+	}
+	for e := range getEBEnvironments() {
+		ebEnvironmentStacks[e.Region()][e.StackName()] = true
+		for _, id := range ebEnvironmentResourceIDs(e) {
+			dependency[e.Region()][id] = true
+			ebEnvironmentResources[e.Region()][id] = true
+		}
+		ebEnvironments = append(ebEnvironments, e)
+	}
+
+	// without getCloudformations cannot populate basic dependency logic
+	for c := range getCloudformations() {
+		// because getting resources is rate limited...
+		c.RLock()
+		for _, resource := range c.Resources {
+			if resource.PhysicalResourceId != nil {
+				id := cloudformationResourceID(*resource.ResourceType, *resource.PhysicalResourceId)
+				dependency[c.Region()][id] = true
+				isInCloudformation[c.Region()][id] = true
+			}
+		}
+		c.RUnlock()
+
+		// an Elastic Beanstalk environment's stack, and everything in it, belongs to the environment
+		if ebEnvironmentStacks[c.Region()][c.Name] {
+			ebEnvironmentResources[c.Region()][c.ID()] = true
+			c.RLock()
+			for _, resource := range c.Resources {
+				if resource.PhysicalResourceId != nil {
+					ebEnvironmentResources[c.Region()][cloudformationResourceID(*resource.ResourceType, *resource.PhysicalResourceId)] = true
+				}
+			}
+			c.RUnlock()
+		}
+
+		// AMIs are referenced through parameters, not resources
+		for _, parameter := range c.Parameters {
+			if parameter.ParameterValue != nil && strings.HasPrefix(*parameter.ParameterValue, "ami-") {
+				dependency[c.Region()][reapable.ID(*parameter.ParameterValue)] = true
+			}
+		}
+
+		if config.Cloudformations.Enabled {
+			resources = append(resources, c)
+		}
+	}
+
+	for _, e := range ebEnvironments {
+		// EBEnvironments in a stack are identified by name
+		if isInCloudformation[e.Region()][namedID(ebEnvironmentType, e.Name)] {
+			e.IsInCloudformation = true
+		}
+		if config.EBEnvironments.Enabled {
+			resources = append(resources, e)
+		}
+	}
+
+	for a := range getAutoScalingGroups() {
+		inventory(a.Region(), "AutoScalingGroupName", a.ID())
+
+		if isInCloudformation[a.Region()][dependencyID(a)] {
+			a.IsInCloudformation = true
+		}
+
+		if dependency[a.Region()][dependencyID(a)] {
+			a.Dependency = true
+		}
+
+		// identify instances in an ASG
+		instanceIDsInASGs := reaperaws.AutoScalingGroupInstanceIDs(a)
+		for region := range instanceIDsInASGs {
+			for instanceID := range instanceIDsInASGs[region] {
+				instancesInASGs[region][instanceID] = true
+				dependency[region][instanceID] = true
+			}
+		}
+
+		// the launch configuration of an ASG
+		if a.LaunchConfigurationName != nil {
+			dependency[a.Region()][namedID(launchConfigurationType, *a.LaunchConfigurationName)] = true
+		}
+
+		// load balancers attached to an ASG
+		for _, name := range a.LoadBalancerNames {
+			if name != nil {
+				dependency[a.Region()][namedID(loadBalancerType, *name)] = true
+			}
+		}
+
+		if config.AutoScalingGroups.Enabled {
+			resources = append(resources, a)
+		}
+	}
+
+	// key pairs used by launch configurations and instances
+	keyPairsInUse := make(map[reapable.Region]map[reapable.ID]bool)
+	for _, region := range config.AWS.Regions {
+		keyPairsInUse[reapable.Region(region)] = make(map[reapable.ID]bool)
+	}
+
+	// get all launch configurations
+	for l := range getLaunchConfigurations() {
+		if l.KeyName != nil {
+			keyPairsInUse[l.Region()][reapable.ID(*l.KeyName)] = true
+		}
+		// AMIs and security groups are referenced by the instances an ASG would launch
+		if l.ImageId != nil {
+			dependency[l.Region()][reapable.ID(*l.ImageId)] = true
+		}
+		// security groups can be referenced by ID or name
+		for _, group := range l.SecurityGroups {
+			if group != nil {
+				dependency[l.Region()][securityGroupID(*group)] = true
+			}
+		}
+
+		if isInCloudformation[l.Region()][dependencyID(l)] {
+			l.IsInCloudformation = true
+		}
+		// an AutoScalingGroup that could not be described may still use it
+		if dependency[l.Region()][dependencyID(l)] ||
+			reaperaws.DescribeFailed("AutoScalingGroup", l.Region()) {
+			l.Dependency = true
+		}
+
+		if config.LaunchConfigurations.Enabled {
+			resources = append(resources, l)
+		}
+	}
+
+	// get all load balancers
+	for l := range getLoadBalancers() {
+		inventory(l.Region(), "LoadBalancerName", l.ID())
+
+		// instances registered with a load balancer are serving traffic
+		for instanceID := range l.InstanceStates {
+			dependency[l.Region()][instanceID] = true
+		}
+
+		// security groups can be referenced by ID or name
+		for _, groupID := range l.SecurityGroups {
+			if groupID != nil {
+				dependency[l.Region()][reapable.ID(*groupID)] = true
+			}
+		}
+		if l.SourceSecurityGroup != nil && l.SourceSecurityGroup.GroupName != nil {
+			dependency[l.Region()][namedID(securityGroupType, *l.SourceSecurityGroup.GroupName)] = true
+		}
+
+		if isInCloudformation[l.Region()][dependencyID(l)] {
+			l.IsInCloudformation = true
+		}
+		if dependency[l.Region()][dependencyID(l)] {
+			l.Dependency = true
+		}
+
+		if config.LoadBalancers.Enabled {
+			resources = append(resources, l)
+		}
+	}
+
+	// get all DB instances
+	for r := range getRDSInstances() {
+		inventory(r.Region(), "DBInstanceIdentifier", r.ID())
+
+		// VPC security groups of a DB instance are in use
+		for _, group := range r.VpcSecurityGroups {
+			if group.VpcSecurityGroupId != nil {
+				dependency[r.Region()][reapable.ID(*group.VpcSecurityGroupId)] = true
+			}
+		}
+
+		if isInCloudformation[r.Region()][dependencyID(r)] {
+			r.IsInCloudformation = true
+		}
+		if dependency[r.Region()][dependencyID(r)] {
+			r.Dependency = true
+		}
+
+		if config.RDSInstances.Enabled {
+			resources = append(resources, r)
+		}
+	}
+
+	// get all cache clusters
+	for c := range getCacheClusters() {
+		// alarms are on the members of a replication group
+		if c.ReplicationGroup != nil {
+			for _, member := range c.MemberClusters {
+				inventory(c.Region(), "CacheClusterId", reapable.ID(*member.CacheClusterId))
+			}
+		} else {
+			inventory(c.Region(), "CacheClusterId", c.ID())
+		}
+
+		// VPC security groups of a cache cluster are in use
+		for _, groupID := range c.SecurityGroupIDs {
+			dependency[c.Region()][groupID] = true
+		}
+
+		if isInCloudformation[c.Region()][dependencyID(c)] {
+			c.IsInCloudformation = true
+		}
+		if dependency[c.Region()][dependencyID(c)] {
+			c.Dependency = true
+		}
+
+		if config.CacheClusters.Enabled {
+			resources = append(resources, c)
+		}
+	}
+
+	// get all Redshift clusters
+	for r := range getRedshiftClusters() {
+		inventory(r.Region(), "ClusterIdentifier", r.ID())
+
+		// VPC security groups of a Redshift cluster are in use
+		for _, group := range r.VpcSecurityGroups {
+			if group.VpcSecurityGroupId != nil {
+				dependency[r.Region()][reapable.ID(*group.VpcSecurityGroupId)] = true
+			}
+		}
+
+		if isInCloudformation[r.Region()][dependencyID(r)] {
+			r.IsInCloudformation = true
+		}
+		if dependency[r.Region()][dependencyID(r)] {
+			r.Dependency = true
+		}
+
+		if config.RedshiftClusters.Enabled {
+			resources = append(resources, r)
+		}
+	}
+
+	// get all Lambda functions
+	for l := range getLambdaFunctions() {
+		inventory(l.Region(), "FunctionName", l.ID())
+
+		// security groups of a function in a VPC are in use
+		for _, groupID := range l.SecurityGroupIDs {
+			dependency[l.Region()][groupID] = true
+		}
+
+		if isInCloudformation[l.Region()][dependencyID(l)] {
+			l.IsInCloudformation = true
+		}
+		if dependency[l.Region()][dependencyID(l)] {
+			l.Dependency = true
+		}
+
+		if config.LambdaFunctions.Enabled {
+			resources = append(resources, l)
+		}
+	}
+
+	// get all ECS clusters
+	for c := range getECSClusters() {
+		inventory(c.Region(), "ClusterName", c.ID())
+
+		// container instances of an ECS cluster are in use
+		for _, instanceID := range c.InstanceIDs {
+			dependency[c.Region()][instanceID] = true
+		}
+
+		if isInCloudformation[c.Region()][dependencyID(c)] {
+			c.IsInCloudformation = true
+		}
+		if dependency[c.Region()][dependencyID(c)] {
+			c.Dependency = true
+		}
+
+		if config.ECSClusters.Enabled {
+			resources = append(resources, c)
 		}
 	}
 
+	// active EMR clusters, whose instances are tagged with their id
+	emrClusterIDs := make(map[reapable.Region]map[reapable.ID]bool)
+	for _, region := range config.AWS.Regions {
+		emrClusterIDs[reapable.Region(region)] = make(map[reapable.ID]bool)
+	}
+
+	// get all EMR clusters
+	for e := range getEMRClusters() {
+		emrClusterIDs[e.Region()][e.ID()] = true
+		inventory(e.Region(), "JobFlowId", e.ID())
+
+		// security groups of an EMR cluster are in use
+		for _, groupID := range e.SecurityGroupIDs {
+			dependency[e.Region()][groupID] = true
+		}
+
+		if isInCloudformation[e.Region()][dependencyID(e)] {
+			e.IsInCloudformation = true
+		}
+		if dependency[e.Region()][dependencyID(e)] {
+			e.Dependency = true
+		}
+
+		if config.EMRClusters.Enabled {
+			resources = append(resources, e)
+		}
+	}
+
+	// images used by running instances, and when they were last launched from
+	imagesInUse := make(map[reapable.Region]map[reapable.ID]bool)
+	imageLastLaunchTimes := make(map[reapable.Region]map[reapable.ID]time.Time)
+	for _, region := range config.AWS.Regions {
+		imagesInUse[reapable.Region(region)] = make(map[reapable.ID]bool)
+		imageLastLaunchTimes[reapable.Region(region)] = make(map[reapable.ID]time.Time)
+	}
+
 	// get all instances
 	for i := range getInstances() {
+		inventory(i.Region(), "InstanceId", i.ID())
+
+		if i.KeyName != nil {
+			keyPairsInUse[i.Region()][reapable.ID(*i.KeyName)] = true
+		}
+		if i.ImageId != nil {
+			imageID := reapable.ID(*i.ImageId)
+			if i.Running() {
+				imagesInUse[i.Region()][imageID] = true
+			}
+			if i.LaunchTime != nil && i.LaunchTime.After(imageLastLaunchTimes[i.Region()][imageID]) {
+				imageLastLaunchTimes[i.Region()][imageID] = *i.LaunchTime
+			}
+		}
+
 		// add security groups to map of in use
 		for id, name := range i.SecurityGroups {
-			dependency[i.Region()][reapable.ID(name)] = true
+			dependency[i.Region()][namedID(securityGroupType, name)] = true
 			dependency[i.Region()][id] = true
 		}
 
-		if dependency[i.Region()][i.ID()] {
+		if dependency[i.Region()][dependencyID(i)] {
 			i.Dependency = true
 		}
-		if isInCloudformation[i.Region()][i.ID()] {
+		if isInCloudformation[i.Region()][dependencyID(i)] {
 			i.IsInCloudformation = true
 		}
 		if instancesInASGs[i.Region()][i.ID()] {
 			i.AutoScaled = true
 		}
+		// instances of an EMR cluster are terminated with it
+		if emrClusterIDs[i.Region()][reapable.ID(i.Tag("aws:elasticmapreduce:job-flow-id"))] {
+			i.Dependency = true
+		}
 
 		if config.Instances.Enabled {
 			resources = append(resources, i)
 		}
 	}
 
+	// get all NAT gateways
+	for n := range getNatGateways() {
+		inventory(n.Region(), "NatGatewayId", n.ID())
+
+		// the subnet, addresses and interfaces of a NAT gateway are in use
+		if n.SubnetId != nil {
+			dependency[n.Region()][reapable.ID(*n.SubnetId)] = true
+		}
+		for _, address := range n.NatGatewayAddresses {
+			if address.AllocationId != nil {
+				dependency[n.Region()][reapable.ID(*address.AllocationId)] = true
+			}
+			if address.NetworkInterfaceId != nil {
+				dependency[n.Region()][reapable.ID(*address.NetworkInterfaceId)] = true
+			}
+		}
+
+		if isInCloudformation[n.Region()][dependencyID(n)] {
+			n.IsInCloudformation = true
+		}
+		if dependency[n.Region()][dependencyID(n)] {
+			n.Dependency = true
+		}
+
+		if config.NatGateways.Enabled {
+			resources = append(resources, n)
+		}
+	}
+
+	// get all network interfaces
+	for n := range getNetworkInterfaces() {
+		// security groups attached to any interface cannot be deleted
+		for _, groupID := range n.SecurityGroupIDs {
+			dependency[n.Region()][groupID] = true
+		}
+
+		if isInCloudformation[n.Region()][dependencyID(n)] {
+			n.IsInCloudformation = true
+		}
+		if dependency[n.Region()][dependencyID(n)] {
+			n.Dependency = true
+		}
+
+		if config.NetworkInterfaces.Enabled {
+			resources = append(resources, n)
+		}
+	}
+
 	// get all security groups
 	for s := range getSecurityGroups() {
 		// if the security group is in use, it isn't reapable
 		// names and IDs are used interchangeably by different parts of the API
-		if isInCloudformation[s.Region()][s.ID()] {
+		if isInCloudformation[s.Region()][dependencyID(s)] {
 			s.IsInCloudformation = true
 		}
-		if dependency[s.Region()][s.ID()] ||
-			dependency[s.Region()][reapable.ID(*s.GroupName)] {
+		if dependency[s.Region()][dependencyID(s)] ||
+			dependency[s.Region()][namedID(securityGroupType, *s.GroupName)] {
 			s.Dependency = true
 		}
 		if config.SecurityGroups.Enabled {
@@ -545,27 +2697,327 @@ func allReapables() []reaperevents.Reapable {
 		}
 	}
 
+	// volumes that still exist, used to find orphaned snapshots
+	volumeIDs := make(map[reapable.Region]map[reapable.ID]bool)
+	for _, region := range config.AWS.Regions {
+		volumeIDs[reapable.Region(region)] = make(map[reapable.ID]bool)
+	}
+
 	// get all the volumes
 	for v := range getVolumes() {
+		volumeIDs[v.Region()][v.ID()] = true
+		inventory(v.Region(), "VolumeId", v.ID())
+
 		// if the volume is in use, it isn't reapable
 		// names and IDs are used interchangeably by different parts of the API
 
 		// sort of doesn't make sense for volume
-		if isInCloudformation[v.Region()][v.ID()] {
+		if isInCloudformation[v.Region()][dependencyID(v)] {
 			v.IsInCloudformation = true
 		}
 
 		// if it is a dependency or is attached to an instance
-		if dependency[v.Region()][v.ID()] || len(v.AttachedInstanceIDs) > 0 {
+		if dependency[v.Region()][dependencyID(v)] || len(v.AttachedInstanceIDs) > 0 {
 			v.Dependency = true
 		}
 		if config.Volumes.Enabled {
 			resources = append(resources, v)
 		}
 	}
+
+	// snapshots backing an image
+	snapshotsInImages := make(map[reapable.Region]map[reapable.ID]bool)
+	for _, region := range config.AWS.Regions {
+		snapshotsInImages[reapable.Region(region)] = make(map[reapable.ID]bool)
+	}
+
+	// get all the images
+	for i := range getImages() {
+		inventory(i.Region(), "ImageId", i.ID())
+
+		for _, snapshotID := range i.SnapshotIDs {
+			snapshotsInImages[i.Region()][snapshotID] = true
+		}
+
+		if isInCloudformation[i.Region()][dependencyID(i)] {
+			i.IsInCloudformation = true
+		}
+
+		i.LastLaunchTime = imageLastLaunchTimes[i.Region()][i.ID()]
+
+		// if it is referenced by a running instance, a launch configuration or a stack
+		if dependency[i.Region()][dependencyID(i)] ||
+			imagesInUse[i.Region()][i.ID()] {
+			i.Dependency = true
+		}
+		if config.Images.Enabled {
+			resources = append(resources, i)
+		}
+	}
+
+	// snapshots do not inform the dependencies of other resources
+	if config.Snapshots.Enabled {
+		// get all the snapshots
+		for s := range getSnapshots() {
+			if isInCloudformation[s.Region()][dependencyID(s)] {
+				s.IsInCloudformation = true
+			}
+
+			markSnapshot(s, volumeIDs[s.Region()], snapshotsInImages[s.Region()])
+
+			if dependency[s.Region()][dependencyID(s)] {
+				s.Dependency = true
+			}
+			resources = append(resources, s)
+		}
+	}
+
+	// addresses do not inform the dependencies of other resources
+	if config.Addresses.Enabled {
+		// get all the addresses
+		for a := range getAddresses() {
+			// addresses in a stack are identified by their public IP
+			if isInCloudformation[a.Region()][dependencyID(a)] ||
+				isInCloudformation[a.Region()][reapable.ID(*a.PublicIp)] {
+				a.IsInCloudformation = true
+			}
+			if dependency[a.Region()][dependencyID(a)] ||
+				dependency[a.Region()][reapable.ID(*a.PublicIp)] {
+				a.Dependency = true
+			}
+			resources = append(resources, a)
+		}
+	}
+
+	// key pairs do not inform the dependencies of other resources
+	if config.KeyPairs.Enabled {
+		// get all the key pairs
+		for k := range getKeyPairs() {
+			// an Instance or LaunchConfiguration that could not be described may still use it
+			if keyPairsInUse[k.Region()][k.ID()] ||
+				reaperaws.DescribeFailed("Instance", k.Region()) ||
+				reaperaws.DescribeFailed("LaunchConfiguration", k.Region()) {
+				k.Dependency = true
+			}
+			resources = append(resources, k)
+		}
+	}
+
+	// buckets do not inform the dependencies of other resources
+	if config.Buckets.Enabled {
+		// get all the buckets
+		for b := range getBuckets() {
+			if isInCloudformation[b.Region()][dependencyID(b)] {
+				b.IsInCloudformation = true
+			}
+			if dependency[b.Region()][dependencyID(b)] {
+				b.Dependency = true
+			}
+			resources = append(resources, b)
+		}
+	}
+
+	// ECS services do not inform the dependencies of other resources
+	if config.ECSServices.Enabled {
+		// get all the ECS services
+		for s := range getECSServices() {
+			// services in a stack are identified by their ARN
+			if isInCloudformation[s.Region()][reapable.ID(*s.ServiceArn)] {
+				s.IsInCloudformation = true
+			}
+			if dependency[s.Region()][dependencyID(s)] {
+				s.Dependency = true
+			}
+			resources = append(resources, s)
+		}
+	}
+
+	// EFS file systems do not inform the dependencies of other resources
+	if config.EFSFileSystems.Enabled {
+		// get all the EFS file systems
+		for f := range getEFSFileSystems() {
+			if isInCloudformation[f.Region()][dependencyID(f)] {
+				f.IsInCloudformation = true
+			}
+			if dependency[f.Region()][dependencyID(f)] {
+				f.Dependency = true
+			}
+			resources = append(resources, f)
+		}
+	}
+
+	// Elasticsearch domains do not inform the dependencies of other resources
+	if config.ElasticsearchDomains.Enabled {
+		// get all the Elasticsearch domains
+		for e := range getElasticsearchDomains() {
+			if isInCloudformation[e.Region()][dependencyID(e)] {
+				e.IsInCloudformation = true
+			}
+			if dependency[e.Region()][dependencyID(e)] {
+				e.Dependency = true
+			}
+			resources = append(resources, e)
+		}
+	}
+
+	// log groups do not inform the dependencies of other resources
+	if config.LogGroups.Enabled {
+		// get all the log groups
+		for l := range getLogGroups() {
+			if isInCloudformation[l.Region()][dependencyID(l)] {
+				l.IsInCloudformation = true
+			}
+			if dependency[l.Region()][dependencyID(l)] {
+				l.Dependency = true
+			}
+			resources = append(resources, l)
+		}
+	}
+
+	// DynamoDB tables do not inform the dependencies of other resources
+	if config.DynamoDBTables.Enabled {
+		// get all the DynamoDB tables
+		for d := range getDynamoDBTables() {
+			if isInCloudformation[d.Region()][dependencyID(d)] {
+				d.IsInCloudformation = true
+			}
+			if dependency[d.Region()][dependencyID(d)] {
+				d.Dependency = true
+			}
+			resources = append(resources, d)
+		}
+	}
+
+	// certificates do not inform the dependencies of other resources
+	if config.Certificates.Enabled {
+		// get all the certificates
+		for c := range getCertificates() {
+			if isInCloudformation[c.Region()][dependencyID(c)] {
+				c.IsInCloudformation = true
+			}
+			if dependency[c.Region()][dependencyID(c)] {
+				c.Dependency = true
+			}
+			resources = append(resources, c)
+		}
+	}
+
+	// ECR images do not inform the dependencies of other resources
+	if config.ECRImages.Enabled {
+		// get all the ECR repositories and their images
+		for r := range getECRRepositories() {
+			resources = append(resources, r)
+		}
+	}
+
+	// alarms do not inform the dependencies of other resources
+	if config.MetricAlarms.Enabled {
+		// get all the alarms
+		for m := range getMetricAlarms() {
+			for _, dimension := range m.Dimensions {
+				if m.Namespace == nil {
+					break
+				}
+				d := metricDimension{*m.Namespace, *dimension.Name}
+				kind, ok := inventoriedDimensions[d]
+				// resources that could not all be described may still exist
+				if !ok || reaperaws.DescribeFailed(kind, m.Region()) {
+					continue
+				}
+				if !existingDimensions[m.Region()][d][reapable.ID(*dimension.Value)] {
+					m.DeletedDimensions = append(m.DeletedDimensions, dimension)
+				}
+			}
+
+			if isInCloudformation[m.Region()][dependencyID(m)] {
+				m.IsInCloudformation = true
+			}
+			if dependency[m.Region()][dependencyID(m)] {
+				m.Dependency = true
+			}
+			resources = append(resources, m)
+		}
+	}
+
+	// hosted zones do not inform the dependencies of other resources
+	if config.HostedZones.Enabled {
+		// get all the hosted zones
+		for h := range getHostedZones() {
+			if isInCloudformation[h.Region()][dependencyID(h)] {
+				h.IsInCloudformation = true
+			}
+			if dependency[h.Region()][dependencyID(h)] {
+				h.Dependency = true
+			}
+			resources = append(resources, h)
+		}
+	}
+
+	// WorkSpaces do not inform the dependencies of other resources
+	if config.WorkSpaces.Enabled {
+		// get all the WorkSpaces
+		for w := range getWorkSpaces() {
+			if isInCloudformation[w.Region()][dependencyID(w)] {
+				w.IsInCloudformation = true
+			}
+			if dependency[w.Region()][dependencyID(w)] {
+				w.Dependency = true
+			}
+			resources = append(resources, w)
+		}
+	}
+
+	if config.EBEnvironments.Enabled {
+		// resources belonging to an EBEnvironment are reaped with it, not separately
+		resources = withoutEBEnvironmentResources(resources, ebEnvironmentResources)
+	}
 	return resources
 }
 
+// ebEnvironmentResourceIDs returns the ids the resources an EBEnvironment created are marked by in the dependency maps
+func ebEnvironmentResourceIDs(e *reaperaws.EBEnvironment) []reapable.ID {
+	var ids []reapable.ID
+	if e.EnvironmentResources == nil {
+		return ids
+	}
+	for _, group := range e.EnvironmentResources.AutoScalingGroups {
+		ids = append(ids, namedID(autoScalingGroupType, *group.Name))
+	}
+	for _, instance := range e.EnvironmentResources.Instances {
+		ids = append(ids, reapable.ID(*instance.Id))
+	}
+	for _, configuration := range e.EnvironmentResources.LaunchConfigurations {
+		ids = append(ids, namedID(launchConfigurationType, *configuration.Name))
+	}
+	for _, loadBalancer := range e.EnvironmentResources.LoadBalancers {
+		ids = append(ids, namedID(loadBalancerType, *loadBalancer.Name))
+	}
+	return ids
+}
+
+// withoutEBEnvironmentResources returns the resources that do not belong to an EBEnvironment, by region
+func withoutEBEnvironmentResources(resources []reaperevents.Reapable, owned map[reapable.Region]map[reapable.ID]bool) []reaperevents.Reapable {
+	var remaining []reaperevents.Reapable
+	for _, r := range resources {
+		if !owned[r.Region()][dependencyID(r)] {
+			remaining = append(remaining, r)
+		}
+	}
+	return remaining
+}
+
+// markSnapshot records whether the volume a snapshot was created from still exists,
+// and whether it backs an AMI, in which case it cannot be deleted
+func markSnapshot(s *reaperaws.Snapshot, volumeIDs, snapshotsInImages map[reapable.ID]bool) {
+	if s.VolumeId != nil && volumeIDs[reapable.ID(*s.VolumeId)] {
+		s.VolumeExists = true
+	}
+	if snapshotsInImages[s.ID()] {
+		s.UsedByImage = true
+		s.Dependency = true
+	}
+}
+
 // isWhitelisted returns whether the filterable is tagged
 // with the whitelist tag
 func isWhitelisted(filterable filters.Filterable) bool {
@@ -582,7 +3034,7 @@ func matchesFilters(filterable filters.Filterable) bool {
 	}()
 
 	var groups map[string]filters.FilterGroup
-	switch filterable.(type) {
+	switch t := filterable.(type) {
 	case *reaperaws.Instance:
 		groups = config.Instances.FilterGroups
 	case *reaperaws.AutoScalingGroup:
@@ -593,6 +3045,68 @@ func matchesFilters(filterable filters.Filterable) bool {
 		groups = config.SecurityGroups.FilterGroups
 	case *reaperaws.Volume:
 		groups = config.Volumes.FilterGroups
+	case *reaperaws.Snapshot:
+		groups = config.Snapshots.FilterGroups
+	case *reaperaws.Image:
+		groups = config.Images.FilterGroups
+	case *reaperaws.LoadBalancer:
+		groups = config.LoadBalancers.FilterGroups
+	case *reaperaws.RDSInstance:
+		groups = config.RDSInstances.FilterGroups
+	case *reaperaws.Address:
+		groups = config.Addresses.FilterGroups
+	case *reaperaws.NetworkInterface:
+		groups = config.NetworkInterfaces.FilterGroups
+	case *reaperaws.LaunchConfiguration:
+		groups = config.LaunchConfigurations.FilterGroups
+	case *reaperaws.CacheCluster:
+		groups = config.CacheClusters.FilterGroups
+	case *reaperaws.RedshiftCluster:
+		groups = config.RedshiftClusters.FilterGroups
+	case *reaperaws.EMRCluster:
+		groups = config.EMRClusters.FilterGroups
+	case *reaperaws.Bucket:
+		groups = config.Buckets.FilterGroups
+	case *reaperaws.LambdaFunction:
+		groups = config.LambdaFunctions.FilterGroups
+	case *reaperaws.DynamoDBTable:
+		groups = config.DynamoDBTables.FilterGroups
+	case *reaperaws.ECSCluster:
+		groups = config.ECSClusters.FilterGroups
+	case *reaperaws.ECSService:
+		groups = config.ECSServices.FilterGroups
+	case *reaperaws.EFSFileSystem:
+		groups = config.EFSFileSystems.FilterGroups
+	case *reaperaws.ElasticsearchDomain:
+		groups = config.ElasticsearchDomains.FilterGroups
+	case *reaperaws.LogGroup:
+		groups = config.LogGroups.FilterGroups
+	case *reaperaws.KeyPair:
+		groups = config.KeyPairs.FilterGroups
+	case *reaperaws.NatGateway:
+		groups = config.NatGateways.FilterGroups
+	case *reaperaws.Certificate:
+		groups = config.Certificates.FilterGroups
+	case *reaperaws.ECRImage:
+		groups = config.ECRImages.FilterGroups
+	case *reaperaws.ECRRepository:
+		// a repository matches when any of its images matched the ECRImages filter groups
+		// and, if there are any, one of the ECRRepositories filter groups
+		if len(t.ReapableImages) == 0 {
+			return false
+		}
+		if len(config.ECRRepositories.FilterGroups) == 0 {
+			return true
+		}
+		groups = config.ECRRepositories.FilterGroups
+	case *reaperaws.MetricAlarm:
+		groups = config.MetricAlarms.FilterGroups
+	case *reaperaws.HostedZone:
+		groups = config.HostedZones.FilterGroups
+	case *reaperaws.WorkSpace:
+		groups = config.WorkSpaces.FilterGroups
+	case *reaperaws.EBEnvironment:
+		groups = config.EBEnvironments.FilterGroups
 	default:
 		log.Warning("You probably screwed up and need to make sure matchesFilters works!")
 		return false
@@ -643,9 +3157,9 @@ func registerReapable(a reaperevents.Reapable) {
 	reapables.Put(a.Region(), a.ID(), a)
 }
 
-// Terminate by region, id, calls a Reapable's own Terminate method
-func Terminate(region reapable.Region, id reapable.ID) error {
-	reapable, err := reapables.Get(region, id)
+// Terminate by region, kind, id, calls a Reapable's own Terminate method
+func Terminate(region reapable.Region, kind reapable.Kind, id reapable.ID) error {
+	reapable, err := reapables.Get(region, kind, id)
 	if err != nil {
 		return err
 	}
@@ -660,9 +3174,9 @@ func Terminate(region reapable.Region, id reapable.ID) error {
 	return nil
 }
 
-// Stop by region, id, calls a Reapable's own Stop method
-func Stop(region reapable.Region, id reapable.ID) error {
-	reapable, err := reapables.Get(region, id)
+// Stop by region, kind, id, calls a Reapable's own Stop method
+func Stop(region reapable.Region, kind reapable.Kind, id reapable.ID) error {
+	reapable, err := reapables.Get(region, kind, id)
 	if err != nil {
 		return err
 	}
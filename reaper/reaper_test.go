@@ -0,0 +1,92 @@
+package reaper
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
+
+	reaperaws "github.com/mozilla-services/reaper/aws"
+	reaperevents "github.com/mozilla-services/reaper/events"
+	"github.com/mozilla-services/reaper/reapable"
+)
+
+func TestMarkSnapshot(t *testing.T) {
+	image := reaperaws.NewImage("us-west-2", &ec2.Image{
+		ImageId: aws.String("ami-1234"),
+		BlockDeviceMappings: []*ec2.BlockDeviceMapping{
+			{DeviceName: aws.String("/dev/sda1"), Ebs: &ec2.EbsBlockDevice{SnapshotId: aws.String("snap-1")}},
+			// instance store volumes have no snapshot
+			{DeviceName: aws.String("/dev/sdb"), VirtualName: aws.String("ephemeral0")},
+		},
+	})
+	snapshotsInImages := make(map[reapable.ID]bool)
+	for _, id := range image.SnapshotIDs {
+		snapshotsInImages[id] = true
+	}
+	volumeIDs := map[reapable.ID]bool{"vol-2": true}
+
+	for _, test := range []struct {
+		snapshot     *ec2.Snapshot
+		volumeExists bool
+		usedByImage  bool
+	}{
+		{&ec2.Snapshot{SnapshotId: aws.String("snap-1"), VolumeId: aws.String("vol-1")}, false, true},
+		{&ec2.Snapshot{SnapshotId: aws.String("snap-2"), VolumeId: aws.String("vol-2")}, true, false},
+		// snapshots copied from another region have no volume
+		{&ec2.Snapshot{SnapshotId: aws.String("snap-3")}, false, false},
+	} {
+		s := reaperaws.NewSnapshot("us-west-2", test.snapshot)
+		markSnapshot(s, volumeIDs, snapshotsInImages)
+		if s.VolumeExists != test.volumeExists {
+			t.Errorf("%s: VolumeExists = %t, want %t", s.ID(), s.VolumeExists, test.volumeExists)
+		}
+		// snapshots backing an AMI cannot be deleted
+		if s.UsedByImage != test.usedByImage || s.Dependency != test.usedByImage {
+			t.Errorf("%s: UsedByImage = %t, Dependency = %t, want %t", s.ID(), s.UsedByImage, s.Dependency, test.usedByImage)
+		}
+	}
+}
+
+func TestWithoutEBEnvironmentResources(t *testing.T) {
+	e := reaperaws.NewEBEnvironment("us-west-2", &elasticbeanstalk.EnvironmentDescription{
+		EnvironmentId:   aws.String("e-1234"),
+		EnvironmentName: aws.String("web"),
+	}, &elasticbeanstalk.EnvironmentResourceDescription{
+		AutoScalingGroups:    []*elasticbeanstalk.AutoScalingGroup{{Name: aws.String("awseb-e-1234-asg")}},
+		Instances:            []*elasticbeanstalk.Instance{{Id: aws.String("i-1")}},
+		LaunchConfigurations: []*elasticbeanstalk.LaunchConfiguration{{Name: aws.String("awseb-e-1234-lc")}},
+	})
+	owned := map[reapable.Region]map[reapable.ID]bool{"us-west-2": {}}
+	for _, id := range ebEnvironmentResourceIDs(e) {
+		owned["us-west-2"][id] = true
+	}
+
+	resources := []reaperevents.Reapable{
+		e,
+		reaperaws.NewAutoScalingGroup("us-west-2", &autoscaling.Group{AutoScalingGroupName: aws.String("awseb-e-1234-asg")}),
+		reaperaws.NewLaunchConfiguration("us-west-2", &autoscaling.LaunchConfiguration{LaunchConfigurationName: aws.String("awseb-e-1234-lc")}),
+		reaperaws.NewInstance("us-west-2", &ec2.Instance{InstanceId: aws.String("i-1")}),
+		reaperaws.NewInstance("us-west-2", &ec2.Instance{InstanceId: aws.String("i-2")}),
+		// a LaunchConfiguration named like the environment's AutoScalingGroup is not the environment's
+		reaperaws.NewLaunchConfiguration("us-west-2", &autoscaling.LaunchConfiguration{LaunchConfigurationName: aws.String("awseb-e-1234-asg")}),
+		// ids are only owned in the environment's region
+		reaperaws.NewInstance("us-east-1", &ec2.Instance{InstanceId: aws.String("i-1")}),
+	}
+	var remaining []string
+	for _, r := range withoutEBEnvironmentResources(resources, owned) {
+		remaining = append(remaining, r.Region().String()+"/"+r.ID().String())
+	}
+	want := []string{"us-west-2/e-1234", "us-west-2/i-2", "us-west-2/awseb-e-1234-asg", "us-east-1/i-1"}
+	if len(remaining) != len(want) {
+		t.Fatalf("remaining resources = %v, want %v", remaining, want)
+	}
+	for i := range want {
+		if remaining[i] != want[i] {
+			t.Errorf("remaining resources = %v, want %v", remaining, want)
+			break
+		}
+	}
+}
@@ -50,6 +50,15 @@ func (s *State) String() string {
 	return s.State.String() + s.reaperTagSeparator + s.Until.Format(s.reaperTagTimeFormat)
 }
 
+// RestrictedString is String with a separator that services restricting tag values
+// to letters, digits, whitespace and _.:/=+-@ accept, such as RDS
+func (s *State) RestrictedString() string {
+	return s.State.String() + restrictedTagSeparator + s.Until.Format(s.reaperTagTimeFormat)
+}
+
+// restrictedTagSeparator separates the fields of RestrictedString
+const restrictedTagSeparator = "/"
+
 func NewState() *State {
 	// default
 	return &State{
@@ -87,6 +96,11 @@ func NewStateWithTag(state string) *State {
 
 	s := strings.Split(state, NewState().reaperTagSeparator)
 
+	if len(s) != 2 {
+		// tags written by RestrictedString
+		s = strings.Split(state, restrictedTagSeparator)
+	}
+
 	if len(s) != 2 {
 		return NewState()
 	}
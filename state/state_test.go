@@ -0,0 +1,31 @@
+package state
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewStateWithTag(t *testing.T) {
+	until, err := time.Parse(NewState().reaperTagTimeFormat, "2016-06-01 03:04PM UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewStateWithUntilAndState(until, SecondState)
+
+	for _, tag := range []string{s.String(), s.RestrictedString()} {
+		parsed := NewStateWithTag(tag)
+		if parsed.State != SecondState || !parsed.Until.Equal(until) {
+			t.Errorf("NewStateWithTag(%q) = %s", tag, parsed.String())
+		}
+	}
+
+	// RDS, Elasticsearch, WorkSpaces and ACM reject | in tag values
+	if strings.Contains(s.RestrictedString(), "|") {
+		t.Errorf("RestrictedString() = %q", s.RestrictedString())
+	}
+
+	if parsed := NewStateWithTag("garbage"); parsed.State != InitialState {
+		t.Errorf("NewStateWithTag(\"garbage\") = %s", parsed.String())
+	}
+}
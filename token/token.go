@@ -39,6 +39,7 @@ const (
 // Not very scalable but good enough for our requirements
 type JobToken struct {
 	Action          Type
+	Kind            string
 	ID              string
 	Region          string
 	IgnoreUntil     time.Duration
@@ -55,6 +56,7 @@ func (j *JobToken) JSON() []byte {
 func (j *JobToken) Equal(j2 *JobToken) bool {
 
 	return j.Action != j2.Action ||
+		j.Kind != j2.Kind ||
 		j.ID != j2.ID ||
 		j.ValidUntil.Equal(j2.ValidUntil)
 }
@@ -63,9 +65,10 @@ func (j *JobToken) Expired() bool {
 	return j.ValidUntil.Before(time.Now())
 }
 
-func NewDelayJob(region, ID string, until time.Duration) *JobToken {
+func NewDelayJob(region, kind, ID string, until time.Duration) *JobToken {
 	return &JobToken{
 		Action:      J_DELAY,
+		Kind:        kind,
 		ID:          ID,
 		Region:      region,
 		IgnoreUntil: until,
@@ -73,27 +76,30 @@ func NewDelayJob(region, ID string, until time.Duration) *JobToken {
 	}
 }
 
-func NewTerminateJob(region, ID string) *JobToken {
+func NewTerminateJob(region, kind, ID string) *JobToken {
 	return &JobToken{
 		Action:     J_TERMINATE,
+		Kind:       kind,
 		ID:         ID,
 		Region:     region,
 		ValidUntil: time.Now().Add(tokenDuration),
 	}
 }
 
-func NewWhitelistJob(region, ID string) *JobToken {
+func NewWhitelistJob(region, kind, ID string) *JobToken {
 	return &JobToken{
 		Action:     J_WHITELIST,
+		Kind:       kind,
 		ID:         ID,
 		Region:     region,
 		ValidUntil: time.Now().Add(tokenDuration),
 	}
 }
 
-func NewStopJob(region, ID string) *JobToken {
+func NewStopJob(region, kind, ID string) *JobToken {
 	return &JobToken{
 		Action:     J_STOP,
+		Kind:       kind,
 		ID:         ID,
 		Region:     region,
 		ValidUntil: time.Now().Add(tokenDuration),